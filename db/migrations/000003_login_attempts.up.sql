@@ -0,0 +1,20 @@
+CREATE TABLE login_attempts (
+    key_type VARCHAR(20) NOT NULL, -- 'username' atau 'ip'
+    key_value VARCHAR(255) NOT NULL,
+    failed_count INT NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (key_type, key_value)
+);
+
+CREATE TABLE auth_audit_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    username VARCHAR(255),
+    ip_address VARCHAR(45),
+    event VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_auth_audit_logs_user ON auth_audit_logs(user_id);
+CREATE INDEX idx_auth_audit_logs_created ON auth_audit_logs(created_at);
@@ -0,0 +1,15 @@
+ALTER TABLE net_worth_histories DROP CONSTRAINT IF EXISTS unique_workspace_date;
+DELETE FROM net_worth_histories WHERE workspace_id <> user_id;
+ALTER TABLE net_worth_histories ADD CONSTRAINT unique_user_date UNIQUE (user_id, recorded_date);
+ALTER TABLE net_worth_histories DROP COLUMN IF EXISTS workspace_id;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE liabilities DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE assets DROP COLUMN IF EXISTS workspace_id;
+
+DROP TABLE IF EXISTS workspace_invitations;
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
+
+DROP TYPE IF EXISTS invitation_status;
+DROP TYPE IF EXISTS workspace_role;
@@ -0,0 +1,71 @@
+-- ========================================================================
+-- WORKSPACES (Household / Shared Net Worth)
+-- ========================================================================
+CREATE TYPE workspace_role AS ENUM ('owner', 'editor', 'viewer');
+CREATE TYPE invitation_status AS ENUM ('pending', 'accepted', 'declined', 'revoked');
+
+CREATE TABLE workspaces (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    is_personal BOOLEAN NOT NULL DEFAULT FALSE, -- workspace pribadi punya id yang sama dengan user id
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE workspace_members (
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role workspace_role NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE TABLE workspace_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    invited_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    invitee_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role workspace_role NOT NULL,
+    status invitation_status NOT NULL DEFAULT 'pending',
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Backfill: setiap user mendapat workspace pribadi
+INSERT INTO workspaces (id, name, owner_id, is_personal)
+SELECT id, 'Personal', id, TRUE FROM users;
+
+INSERT INTO workspace_members (workspace_id, user_id, role)
+SELECT id, id, 'owner' FROM users;
+
+-- ========================================================================
+-- DATA OWNED BY WORKSPACE
+-- ========================================================================
+ALTER TABLE assets ADD COLUMN workspace_id UUID REFERENCES workspaces(id) ON DELETE RESTRICT;
+UPDATE assets SET workspace_id = user_id;
+ALTER TABLE assets ALTER COLUMN workspace_id SET NOT NULL;
+
+ALTER TABLE liabilities ADD COLUMN workspace_id UUID REFERENCES workspaces(id) ON DELETE RESTRICT;
+UPDATE liabilities SET workspace_id = user_id;
+ALTER TABLE liabilities ALTER COLUMN workspace_id SET NOT NULL;
+
+ALTER TABLE transactions ADD COLUMN workspace_id UUID REFERENCES workspaces(id) ON DELETE RESTRICT;
+UPDATE transactions SET workspace_id = user_id;
+ALTER TABLE transactions ALTER COLUMN workspace_id SET NOT NULL;
+
+ALTER TABLE net_worth_histories ADD COLUMN workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+UPDATE net_worth_histories SET workspace_id = user_id;
+ALTER TABLE net_worth_histories ALTER COLUMN workspace_id SET NOT NULL;
+ALTER TABLE net_worth_histories DROP CONSTRAINT IF EXISTS unique_user_date;
+ALTER TABLE net_worth_histories DROP CONSTRAINT IF EXISTS net_worth_histories_user_id_recorded_date_key;
+ALTER TABLE net_worth_histories ADD CONSTRAINT unique_workspace_date UNIQUE (workspace_id, recorded_date);
+
+-- ========================================================================
+-- INDEXING
+-- ========================================================================
+CREATE INDEX idx_workspace_members_user ON workspace_members(user_id);
+CREATE INDEX idx_workspace_invitations_invitee ON workspace_invitations(invitee_id);
+CREATE INDEX idx_assets_workspace ON assets(workspace_id);
+CREATE INDEX idx_liabilities_workspace ON liabilities(workspace_id);
+CREATE INDEX idx_transactions_workspace ON transactions(workspace_id);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_transactions_category;
+DROP INDEX IF EXISTS idx_liabilities_category;
+DROP INDEX IF EXISTS idx_assets_category;
+
+ALTER TABLE transaction_categories DROP COLUMN IF EXISTS is_archived;
+ALTER TABLE liability_categories DROP COLUMN IF EXISTS is_archived;
+ALTER TABLE asset_categories DROP COLUMN IF EXISTS is_archived;
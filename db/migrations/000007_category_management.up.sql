@@ -0,0 +1,10 @@
+-- ========================================================================
+-- ARCHIVE KATEGORI (disembunyikan dari picker tanpa merusak histori)
+-- ========================================================================
+ALTER TABLE asset_categories ADD COLUMN is_archived BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE liability_categories ADD COLUMN is_archived BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE transaction_categories ADD COLUMN is_archived BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_assets_category ON assets(category_id);
+CREATE INDEX idx_liabilities_category ON liabilities(category_id);
+CREATE INDEX idx_transactions_category ON transactions(category_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_transaction_categories_parent;
+
+ALTER TABLE transaction_categories
+    DROP CONSTRAINT IF EXISTS transaction_categories_parent_not_self,
+    DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,8 @@
+-- ========================================================================
+-- HIERARKI KATEGORI TRANSAKSI (parent/child dengan kedalaman bebas)
+-- ========================================================================
+ALTER TABLE transaction_categories
+    ADD COLUMN parent_id UUID REFERENCES transaction_categories(id) ON DELETE SET NULL,
+    ADD CONSTRAINT transaction_categories_parent_not_self CHECK (parent_id <> id);
+
+CREATE INDEX idx_transaction_categories_parent ON transaction_categories(parent_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS categorization_rules;
+DROP TYPE IF EXISTS rule_match_type;
@@ -0,0 +1,27 @@
+-- ========================================================================
+-- TABEL CATEGORIZATION RULES (Auto-kategori transaksi per Workspace)
+-- ========================================================================
+CREATE TYPE rule_match_type AS ENUM ('contains', 'regex');
+
+CREATE TABLE categorization_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    priority INT NOT NULL DEFAULT 0, -- dievaluasi dari priority terkecil
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    -- Kondisi (semua yang diisi harus terpenuhi)
+    notes_pattern TEXT,
+    notes_match rule_match_type NOT NULL DEFAULT 'contains',
+    min_amount DECIMAL(15, 2),
+    max_amount DECIMAL(15, 2),
+    condition_asset_id UUID REFERENCES assets(id) ON DELETE CASCADE,
+    -- Aksi
+    set_category_id UUID REFERENCES transaction_categories(id) ON DELETE SET NULL,
+    set_asset_id UUID REFERENCES assets(id) ON DELETE SET NULL,
+    set_tags TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_categorization_rules_workspace ON categorization_rules(workspace_id, priority);
@@ -0,0 +1,24 @@
+-- ========================================================================
+-- TABEL TRANSACTION SPLITS (Satu transaksi dipecah ke beberapa kategori)
+-- ========================================================================
+CREATE TABLE transaction_splits (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES transaction_categories(id) ON DELETE RESTRICT,
+    amount DECIMAL(15, 2) NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_transaction_splits_transaction ON transaction_splits(transaction_id);
+CREATE INDEX idx_transaction_splits_category ON transaction_splits(category_id);
+
+-- Baris laporan: satu baris per split, atau transaksinya sendiri kalau tidak di-split
+CREATE VIEW transaction_lines AS
+SELECT
+    t.id AS transaction_id,
+    COALESCE(s.category_id, t.category_id) AS category_id,
+    COALESCE(s.amount, t.amount) AS amount,
+    COALESCE(s.notes, t.notes) AS notes
+FROM transactions t
+LEFT JOIN transaction_splits s ON s.transaction_id = t.id;
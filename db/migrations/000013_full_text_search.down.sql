@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_liabilities_search;
+DROP INDEX IF EXISTS idx_assets_search;
+DROP INDEX IF EXISTS idx_transaction_categories_search;
+DROP INDEX IF EXISTS idx_transactions_search;
+
+ALTER TABLE liabilities DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE assets DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE transaction_categories DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE transactions DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,21 @@
+-- ========================================================================
+-- FULL-TEXT SEARCH
+-- ========================================================================
+-- Pakai konfigurasi 'simple' (tanpa stemming) karena catatan user campuran
+-- bahasa Indonesia dan Inggris
+ALTER TABLE transactions ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', COALESCE(notes, ''))) STORED;
+
+ALTER TABLE transaction_categories ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', name)) STORED;
+
+ALTER TABLE assets ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', name || ' ' || COALESCE(details->>'ticker_symbol', ''))) STORED;
+
+ALTER TABLE liabilities ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', name)) STORED;
+
+CREATE INDEX idx_transactions_search ON transactions USING GIN (search_vector);
+CREATE INDEX idx_transaction_categories_search ON transaction_categories USING GIN (search_vector);
+CREATE INDEX idx_assets_search ON assets USING GIN (search_vector);
+CREATE INDEX idx_liabilities_search ON liabilities USING GIN (search_vector);
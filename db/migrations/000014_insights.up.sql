@@ -0,0 +1,20 @@
+-- ========================================================================
+-- TABEL INSIGHTS (Hasil deteksi anomali, dihitung ulang tiap malam)
+-- ========================================================================
+-- Setiap run menggantikan semua insight milik workspace, jadi endpoint cukup
+-- membaca tabel ini tanpa menghitung ulang riwayat transaksi
+CREATE TABLE insights (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL, -- 'category_spike', 'large_expense', 'asset_drop'
+    subject_id UUID, -- kategori, transaksi atau aset yang dimaksud
+    subject_name VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL, -- nilai yang tidak biasa
+    baseline DECIMAL(15, 2) NOT NULL, -- nilai pembanding (rata-rata / saldo sebelumnya)
+    score DECIMAL(10, 2) NOT NULL, -- simpangan baku di atas rata-rata, atau persentase penurunan
+    insight_date DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_insights_workspace ON insights(workspace_id, insight_date DESC);
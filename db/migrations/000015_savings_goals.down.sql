@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS savings_goal_assets;
+DROP TABLE IF EXISTS savings_goals;
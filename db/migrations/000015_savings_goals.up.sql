@@ -0,0 +1,26 @@
+-- ========================================================================
+-- TABEL SAVINGS GOALS (Target tabungan per Workspace)
+-- ========================================================================
+CREATE TABLE savings_goals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    target_amount DECIMAL(15, 2) NOT NULL,
+    target_date DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_savings_goals_workspace ON savings_goals(workspace_id, target_date);
+
+-- ========================================================================
+-- TABEL SAVINGS GOAL ASSETS (Aset yang nilainya dihitung sebagai progres)
+-- ========================================================================
+CREATE TABLE savings_goal_assets (
+    goal_id UUID NOT NULL REFERENCES savings_goals(id) ON DELETE CASCADE,
+    asset_id UUID NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    PRIMARY KEY (goal_id, asset_id)
+);
+
+CREATE INDEX idx_savings_goal_assets_asset ON savings_goal_assets(asset_id);
@@ -0,0 +1,19 @@
+-- ========================================================================
+-- TABEL ALLOCATION TARGETS (Target alokasi aset per Workspace)
+-- ========================================================================
+-- Target berlaku untuk satu kategori aset atau satu base_type. Kategori tanpa
+-- target sendiri ikut target base_type-nya, aset tanpa target tidak dihitung
+CREATE TABLE allocation_targets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id UUID REFERENCES asset_categories(id) ON DELETE CASCADE,
+    base_type asset_base_type,
+    target_percent DECIMAL(5, 2) NOT NULL,
+    tolerance_percent DECIMAL(5, 2) NOT NULL DEFAULT 5, -- batas toleransi (poin persen) sebelum perlu rebalance
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((category_id IS NULL) <> (base_type IS NULL))
+);
+
+CREATE UNIQUE INDEX idx_allocation_targets_category ON allocation_targets(workspace_id, category_id) WHERE category_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_allocation_targets_base_type ON allocation_targets(workspace_id, base_type) WHERE base_type IS NOT NULL;
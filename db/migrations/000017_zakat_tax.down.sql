@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS tax_brackets;
+DROP TABLE IF EXISTS tax_ptkp;
+DROP TABLE IF EXISTS tax_years;
+ALTER TABLE transaction_categories DROP COLUMN IF EXISTS is_taxable;
+ALTER TABLE asset_categories DROP COLUMN IF EXISTS is_zakatable;
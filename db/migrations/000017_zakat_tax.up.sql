@@ -0,0 +1,55 @@
+-- ========================================================================
+-- FLAG ZAKAT & PAJAK PADA KATEGORI
+-- ========================================================================
+-- NULL berarti ikut base_type: liquid dan investment wajib zakat, physical tidak
+ALTER TABLE asset_categories ADD COLUMN is_zakatable BOOLEAN;
+-- Hanya dipakai untuk kategori income
+ALTER TABLE transaction_categories ADD COLUMN is_taxable BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- ========================================================================
+-- TABEL TAX YEARS (Versi aturan PPh orang pribadi)
+-- ========================================================================
+-- Satu versi berlaku mulai tahunnya sampai ada versi yang lebih baru
+CREATE TABLE tax_years (
+    year INT PRIMARY KEY,
+    occupational_cost_rate DECIMAL(5, 2) NOT NULL, -- biaya jabatan (persen dari penghasilan bruto)
+    occupational_cost_cap DECIMAL(15, 2) NOT NULL, -- batas biaya jabatan setahun
+    notes TEXT
+);
+
+CREATE TABLE tax_ptkp (
+    year INT NOT NULL REFERENCES tax_years(year) ON DELETE CASCADE,
+    status VARCHAR(10) NOT NULL, -- 'TK/0' s.d. 'K/3'
+    amount DECIMAL(15, 2) NOT NULL,
+    PRIMARY KEY (year, status)
+);
+
+-- Lapisan tarif berlaku dari lower_bound sampai lower_bound lapisan berikutnya
+CREATE TABLE tax_brackets (
+    year INT NOT NULL REFERENCES tax_years(year) ON DELETE CASCADE,
+    lower_bound DECIMAL(15, 2) NOT NULL,
+    rate DECIMAL(5, 2) NOT NULL, -- persen
+    PRIMARY KEY (year, lower_bound)
+);
+
+-- UU PPh 36/2008 dengan PTKP PMK 101/2016
+INSERT INTO tax_years (year, occupational_cost_rate, occupational_cost_cap, notes)
+VALUES (2016, 5, 6000000, 'UU 36/2008, PMK 101/PMK.010/2016');
+
+INSERT INTO tax_ptkp (year, status, amount) VALUES
+    (2016, 'TK/0', 54000000), (2016, 'TK/1', 58500000), (2016, 'TK/2', 63000000), (2016, 'TK/3', 67500000),
+    (2016, 'K/0', 58500000), (2016, 'K/1', 63000000), (2016, 'K/2', 67500000), (2016, 'K/3', 72000000);
+
+INSERT INTO tax_brackets (year, lower_bound, rate) VALUES
+    (2016, 0, 5), (2016, 50000000, 15), (2016, 250000000, 25), (2016, 500000000, 30);
+
+-- UU HPP 7/2021
+INSERT INTO tax_years (year, occupational_cost_rate, occupational_cost_cap, notes)
+VALUES (2022, 5, 6000000, 'UU 7/2021 (HPP)');
+
+INSERT INTO tax_ptkp (year, status, amount) VALUES
+    (2022, 'TK/0', 54000000), (2022, 'TK/1', 58500000), (2022, 'TK/2', 63000000), (2022, 'TK/3', 67500000),
+    (2022, 'K/0', 58500000), (2022, 'K/1', 63000000), (2022, 'K/2', 67500000), (2022, 'K/3', 72000000);
+
+INSERT INTO tax_brackets (year, lower_bound, rate) VALUES
+    (2022, 0, 5), (2022, 60000000, 15), (2022, 250000000, 25), (2022, 500000000, 30), (2022, 5000000000, 35);
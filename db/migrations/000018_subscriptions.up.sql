@@ -0,0 +1,21 @@
+-- ========================================================================
+-- TABEL SUBSCRIPTIONS (Tagihan berulang hasil deteksi transaksi)
+-- ========================================================================
+-- Satu baris per pola (kategori + catatan yang dinormalisasi) yang sudah
+-- diputuskan user: 'confirmed' dilacak sebagai tagihan berulang, 'dismissed'
+-- tidak dideteksi lagi
+CREATE TABLE subscriptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES transaction_categories(id) ON DELETE CASCADE,
+    pattern VARCHAR(255) NOT NULL, -- kosong berarti semua transaksi di kategori tsb
+    status VARCHAR(20) NOT NULL CHECK (status IN ('confirmed', 'dismissed')),
+    name VARCHAR(255) NOT NULL,
+    cadence VARCHAR(20) NOT NULL, -- 'weekly', 'biweekly', 'monthly', 'quarterly', 'yearly'
+    amount DECIMAL(15, 2) NOT NULL, -- rata-rata tagihan saat dikonfirmasi
+    last_charge_date DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (workspace_id, category_id, pattern)
+);
@@ -0,0 +1,16 @@
+-- ========================================================================
+-- VERIFIKASI EMAIL USER
+-- ========================================================================
+-- Hanya email yang sudah diverifikasi (mis. lewat provider OIDC) boleh
+-- dipakai untuk menautkan login OIDC ke akun yang sudah ada
+ALTER TABLE users ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Email kosong disimpan sebagai NULL supaya tidak bentrok dengan UNIQUE
+UPDATE users SET email = NULL WHERE email = '';
+
+-- Akun yang emailnya sama dengan identitas OIDC-nya sudah terverifikasi provider
+UPDATE users u SET email_verified = TRUE
+WHERE EXISTS (
+    SELECT 1 FROM user_identities ui
+    WHERE ui.user_id = u.id AND ui.email = u.email
+);
@@ -0,0 +1,9 @@
+-- ========================================================================
+-- WAKTU NONAKTIF ASET
+-- ========================================================================
+-- Neraca untuk tanggal lampau tetap memuat aset yang saat itu masih dimiliki
+-- walaupun sekarang sudah nonaktif
+ALTER TABLE assets ADD COLUMN deactivated_at TIMESTAMP WITH TIME ZONE;
+
+-- Untuk aset yang sudah nonaktif, perubahan terakhir adalah perkiraan terbaik
+UPDATE assets SET deactivated_at = updated_at WHERE is_active = FALSE;
@@ -0,0 +1 @@
+ALTER TABLE net_worth_histories DROP COLUMN IF EXISTS zakatable_wealth;
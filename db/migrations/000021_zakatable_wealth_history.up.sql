@@ -0,0 +1,7 @@
+-- ========================================================================
+-- RIWAYAT HARTA WAJIB ZAKAT
+-- ========================================================================
+-- Haul dihitung dari harta wajib zakat (aset zakat dikurangi utang jatuh
+-- tempo), bukan dari kekayaan bersih. Snapshot lama tidak punya nilainya
+-- (NULL) dan tidak dipakai untuk haul
+ALTER TABLE net_worth_histories ADD COLUMN zakatable_wealth DECIMAL(15, 2);
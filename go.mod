@@ -13,6 +13,7 @@ require golang.org/x/crypto v0.49.0
 
 require (
 	github.com/go-co-op/gocron v1.37.0
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/go-playground/validator/v10 v10.30.1
 	github.com/golang-jwt/jwt/v5 v5.3.1
 	github.com/google/uuid v1.6.0
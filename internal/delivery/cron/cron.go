@@ -15,7 +15,7 @@ func Start(db *sqlx.DB, logger *log.Logger) {
 
 	// Refresh Token
 	userRepo := repository.NewUserRepository(db)
-	userUC := usecase.NewUserUsecase(logger, userRepo, txManager)
+	userUC := usecase.NewUserUsecase(logger, userRepo, txManager, usecase.LoginThrottleConfigFromEnv())
 	go func() {
 		RefreshTokenCleanup(userUC, logger)
 	}()
@@ -34,4 +34,11 @@ func Start(db *sqlx.DB, logger *log.Logger) {
 	go func() {
 		UpdateStockPrice(assetUC, logger)
 	}()
+
+	// Insight
+	insightRepo := repository.NewInsightRepository(db)
+	insightUC := usecase.NewInsightUsecase(logger, insightRepo, txManager, usecase.InsightConfigFromEnv())
+	go func() {
+		GenerateInsights(insightUC, logger)
+	}()
 }
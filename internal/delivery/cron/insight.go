@@ -0,0 +1,39 @@
+package cron
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/usecase"
+	"github.com/go-co-op/gocron"
+)
+
+func GenerateInsights(insightUC usecase.InsightUsecase, appLogger *log.Logger) {
+	s := gocron.NewScheduler(time.Local)
+
+	_, err := s.Every(1).Day().At("02:00").Do(func() {
+		safeExecute(appLogger, "GenerateInsights", func() {
+			appLogger.Println("Starting scheduled insight generation...")
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+			defer cancel()
+
+			err := insightUC.GenerateAll(ctx)
+			if err != nil {
+				appLogger.Printf("ERROR: Failed to generate insights: %v", err)
+				return
+			}
+
+			appLogger.Printf("SUCCESS: Insights generated at %s", time.Now().Format("2006-01-02 15:04:05"))
+		})
+	})
+
+	if err != nil {
+		appLogger.Fatalf("Failed to schedule job: %v", err)
+	}
+
+	s.StartAsync()
+
+	appLogger.Println("Insight scheduler is active.")
+}
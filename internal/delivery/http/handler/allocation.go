@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/fazriegi/netbase-be/internal/delivery/http/middleware"
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/internal/usecase"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/fazriegi/netbase-be/pkg/validator"
+)
+
+type AllocationHandler struct {
+	usecase usecase.AllocationUsecase
+	logger  *log.Logger
+}
+
+func NewAllocationHandler(mux *http.ServeMux, uc usecase.AllocationUsecase, logger *log.Logger) {
+	h := &AllocationHandler{
+		usecase: uc,
+		logger:  logger,
+	}
+
+	mux.Handle("GET /v1/assets/allocation/targets", middleware.MiddlewareAuth(http.HandlerFunc(h.ListTargets)))
+	mux.Handle("PUT /v1/assets/allocation/targets", middleware.MiddlewareAuth(http.HandlerFunc(h.SetTargets)))
+	mux.Handle("GET /v1/assets/allocation/rebalance", middleware.MiddlewareAuth(http.HandlerFunc(h.Rebalance)))
+}
+
+func (h *AllocationHandler) ListTargets(w http.ResponseWriter, r *http.Request) {
+	h.usecase.ListTargets(r.Context()).HTTP(w)
+}
+
+func (h *AllocationHandler) SetTargets(w http.ResponseWriter, r *http.Request) {
+	var req domain.SetAllocationTargets
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidJson, nil, nil).HTTP(w)
+		return
+	}
+
+	validationErr := validator.ValidateRequest(&req)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+		pkg.NewResponse(http.StatusUnprocessableEntity, constant.ErrValidation, errResponse, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.SetTargets(r.Context(), &req).HTTP(w)
+}
+
+func (h *AllocationHandler) Rebalance(w http.ResponseWriter, r *http.Request) {
+	var req domain.RebalanceRequest
+
+	if err := pkg.ParseQueryParam(r, &req); err != nil {
+		h.logger.Printf("[ERROR] parsing query params: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrParseQueryParam, nil, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.Rebalance(r.Context(), &req).HTTP(w)
+}
@@ -26,7 +26,6 @@ func NewAssetHandler(mux *http.ServeMux, uc usecase.AssetUsecase, logger *log.Lo
 	}
 
 	mux.Handle("GET /v1/assets", middleware.MiddlewareAuth(http.HandlerFunc(handler.ListAsset)))
-	mux.Handle("GET /v1/assets/categories", middleware.MiddlewareAuth(http.HandlerFunc(handler.ListAssetCategory)))
 	mux.Handle("GET /v1/assets/{id}", middleware.MiddlewareAuth(http.HandlerFunc(handler.GetByID)))
 	mux.Handle("PUT /v1/assets/{id}", middleware.MiddlewareAuth(http.HandlerFunc(handler.Update)))
 	mux.Handle("DELETE /v1/assets/{id}", middleware.MiddlewareAuth(http.HandlerFunc(handler.Delete)))
@@ -47,11 +46,6 @@ func (h *AssetHandler) ListAsset(w http.ResponseWriter, r *http.Request) {
 	response.HTTP(w)
 }
 
-func (h *AssetHandler) ListAssetCategory(w http.ResponseWriter, r *http.Request) {
-	response := h.usecase.ListAssetCategory(r.Context())
-	response.HTTP(w)
-}
-
 func (h *AssetHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 
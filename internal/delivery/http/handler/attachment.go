@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/fazriegi/netbase-be/internal/delivery/http/middleware"
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/internal/usecase"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+)
+
+// multipartOverhead leaves room for the multipart boundaries and headers on
+// top of the file itself.
+const multipartOverhead = 1 << 20
+
+type AttachmentHandler struct {
+	usecase usecase.AttachmentUsecase
+	logger  *log.Logger
+	maxSize int64
+}
+
+func NewAttachmentHandler(mux *http.ServeMux, uc usecase.AttachmentUsecase, logger *log.Logger, maxSize int64) {
+	h := &AttachmentHandler{
+		usecase: uc,
+		logger:  logger,
+		maxSize: maxSize,
+	}
+
+	mux.Handle("GET /v1/transactions/{id}/attachments", middleware.MiddlewareAuth(http.HandlerFunc(h.List)))
+	mux.Handle("POST /v1/transactions/{id}/attachments", middleware.MiddlewareAuth(http.HandlerFunc(h.Upload)))
+	mux.Handle("GET /v1/transactions/{id}/attachments/{attachmentID}/download", middleware.MiddlewareAuth(http.HandlerFunc(h.Download)))
+	mux.Handle("DELETE /v1/transactions/{id}/attachments/{attachmentID}", middleware.MiddlewareAuth(http.HandlerFunc(h.Delete)))
+}
+
+// pathIDs parses the transaction id and, when present, the attachment id of the path.
+func (h *AttachmentHandler) pathIDs(w http.ResponseWriter, r *http.Request) (transactionID, attachmentID uuid.UUID, ok bool) {
+	transactionID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Printf("[ERROR] uuid.Parse - invalid UUID format: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if r.PathValue("attachmentID") != "" {
+		attachmentID, err = uuid.Parse(r.PathValue("attachmentID"))
+		if err != nil {
+			h.logger.Printf("[ERROR] uuid.Parse - invalid UUID format: %s", err.Error())
+			pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+			return uuid.Nil, uuid.Nil, false
+		}
+	}
+
+	return transactionID, attachmentID, true
+}
+
+func (h *AttachmentHandler) List(w http.ResponseWriter, r *http.Request) {
+	transactionID, _, ok := h.pathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	h.usecase.List(r.Context(), transactionID).HTTP(w)
+}
+
+func (h *AttachmentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	transactionID, _, ok := h.pathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxSize+multipartOverhead)
+	if err := r.ParseMultipartForm(multipartOverhead); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			pkg.NewResponse(http.StatusRequestEntityTooLarge, "Attachment is too large", nil, nil).HTTP(w)
+			return
+		}
+
+		pkg.NewResponse(http.StatusBadRequest, "Expected a multipart form with a file field", nil, nil).HTTP(w)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		pkg.NewResponse(http.StatusBadRequest, "Expected a multipart form with a file field", nil, nil).HTTP(w)
+		return
+	}
+	defer file.Close()
+
+	req := domain.UploadAttachment{
+		TransactionID: transactionID,
+		FileName:      header.Filename,
+		Size:          header.Size,
+		Content:       file,
+	}
+
+	h.usecase.Upload(r.Context(), &req).HTTP(w)
+}
+
+func (h *AttachmentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	transactionID, attachmentID, ok := h.pathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	response := h.usecase.Download(r.Context(), transactionID, attachmentID)
+	file, isFile := response.Data.(*domain.AttachmentContent)
+	if response.Code != http.StatusOK || !isFile {
+		response.HTTP(w)
+		return
+	}
+	defer file.Content.Close()
+
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": file.FileName})
+	if disposition == "" {
+		disposition = "attachment"
+	}
+
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(file.SizeBytes, 10))
+	w.Header().Set("Content-Disposition", disposition)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, file.Content); err != nil {
+		h.logger.Printf("[ERROR] io.Copy attachment: %s", err.Error())
+	}
+}
+
+func (h *AttachmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	transactionID, attachmentID, ok := h.pathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	h.usecase.Delete(r.Context(), transactionID, attachmentID).HTTP(w)
+}
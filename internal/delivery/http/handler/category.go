@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/fazriegi/netbase-be/internal/delivery/http/middleware"
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/internal/usecase"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/fazriegi/netbase-be/pkg/validator"
+	"github.com/google/uuid"
+)
+
+type CategoryHandler struct {
+	usecase usecase.CategoryUsecase
+	logger  *log.Logger
+	kind    string
+}
+
+// NewCategoryHandler registers the category routes of every category kind
+// under its resource, e.g. /v1/assets/categories.
+func NewCategoryHandler(mux *http.ServeMux, uc usecase.CategoryUsecase, logger *log.Logger) {
+	prefixes := map[string]string{
+		domain.CategoryKindAsset:       "/v1/assets/categories",
+		domain.CategoryKindLiability:   "/v1/liabilities/categories",
+		domain.CategoryKindTransaction: "/v1/transactions/categories",
+	}
+
+	for kind, prefix := range prefixes {
+		h := &CategoryHandler{
+			usecase: uc,
+			logger:  logger,
+			kind:    kind,
+		}
+
+		mux.Handle("GET "+prefix, middleware.MiddlewareAuth(http.HandlerFunc(h.List)))
+		mux.Handle("POST "+prefix, middleware.MiddlewareAuth(http.HandlerFunc(h.Create)))
+		mux.Handle("PUT "+prefix+"/{id}", middleware.MiddlewareAuth(http.HandlerFunc(h.Update)))
+		mux.Handle("DELETE "+prefix+"/{id}", middleware.MiddlewareAuth(http.HandlerFunc(h.Delete)))
+		mux.Handle("POST "+prefix+"/{id}/merge", middleware.MiddlewareAuth(http.HandlerFunc(h.Merge)))
+	}
+}
+
+func (h *CategoryHandler) List(w http.ResponseWriter, r *http.Request) {
+	var req domain.ListCategoryRequest
+
+	if err := pkg.ParseQueryParam(r, &req); err != nil {
+		h.logger.Printf("[ERROR] parsing query params: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrParseQueryParam, nil, nil).HTTP(w)
+		return
+	}
+	req.Kind = h.kind
+
+	h.usecase.List(r.Context(), &req).HTTP(w)
+}
+
+func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateCategory
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidJson, nil, nil).HTTP(w)
+		return
+	}
+
+	validationErr := validator.ValidateRequest(&req)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+		pkg.NewResponse(http.StatusUnprocessableEntity, constant.ErrValidation, errResponse, nil).HTTP(w)
+		return
+	}
+	req.Kind = h.kind
+
+	h.usecase.Create(r.Context(), &req).HTTP(w)
+}
+
+func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req domain.UpdateCategory
+
+	parsedID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Printf("[ERROR] uuid.Parse - invalid UUID format: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidJson, nil, nil).HTTP(w)
+		return
+	}
+
+	validationErr := validator.ValidateRequest(&req)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+		pkg.NewResponse(http.StatusUnprocessableEntity, constant.ErrValidation, errResponse, nil).HTTP(w)
+		return
+	}
+	req.ID = parsedID
+	req.Kind = h.kind
+
+	h.usecase.Update(r.Context(), &req).HTTP(w)
+}
+
+func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	parsedID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Printf("[ERROR] uuid.Parse - invalid UUID format: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.Delete(r.Context(), h.kind, parsedID).HTTP(w)
+}
+
+func (h *CategoryHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	var req domain.MergeCategory
+
+	parsedID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Printf("[ERROR] uuid.Parse - invalid UUID format: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidJson, nil, nil).HTTP(w)
+		return
+	}
+
+	validationErr := validator.ValidateRequest(&req)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+		pkg.NewResponse(http.StatusUnprocessableEntity, constant.ErrValidation, errResponse, nil).HTTP(w)
+		return
+	}
+	req.SourceID = parsedID
+	req.Kind = h.kind
+
+	h.usecase.Merge(r.Context(), &req).HTTP(w)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/fazriegi/netbase-be/internal/delivery/http/middleware"
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/internal/usecase"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/fazriegi/netbase-be/pkg/validator"
+	"github.com/google/uuid"
+)
+
+type GoalHandler struct {
+	usecase usecase.GoalUsecase
+	logger  *log.Logger
+}
+
+func NewGoalHandler(mux *http.ServeMux, uc usecase.GoalUsecase, logger *log.Logger) {
+	h := &GoalHandler{
+		usecase: uc,
+		logger:  logger,
+	}
+
+	mux.Handle("GET /v1/goals", middleware.MiddlewareAuth(http.HandlerFunc(h.List)))
+	mux.Handle("GET /v1/goals/{id}", middleware.MiddlewareAuth(http.HandlerFunc(h.Get)))
+	mux.Handle("POST /v1/goals", middleware.MiddlewareAuth(http.HandlerFunc(h.Create)))
+	mux.Handle("PUT /v1/goals/{id}", middleware.MiddlewareAuth(http.HandlerFunc(h.Update)))
+	mux.Handle("DELETE /v1/goals/{id}", middleware.MiddlewareAuth(http.HandlerFunc(h.Delete)))
+}
+
+func (h *GoalHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.usecase.List(r.Context()).HTTP(w)
+}
+
+func (h *GoalHandler) Get(w http.ResponseWriter, r *http.Request) {
+	parsedID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Printf("[ERROR] uuid.Parse - invalid UUID format: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.Get(r.Context(), parsedID).HTTP(w)
+}
+
+func (h *GoalHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateGoal
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidJson, nil, nil).HTTP(w)
+		return
+	}
+
+	validationErr := validator.ValidateRequest(&req)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+		pkg.NewResponse(http.StatusUnprocessableEntity, constant.ErrValidation, errResponse, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.Create(r.Context(), &req).HTTP(w)
+}
+
+func (h *GoalHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateGoal
+
+	parsedID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Printf("[ERROR] uuid.Parse - invalid UUID format: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidJson, nil, nil).HTTP(w)
+		return
+	}
+
+	validationErr := validator.ValidateRequest(&req)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+		pkg.NewResponse(http.StatusUnprocessableEntity, constant.ErrValidation, errResponse, nil).HTTP(w)
+		return
+	}
+	req.ID = parsedID
+
+	h.usecase.Update(r.Context(), &req).HTTP(w)
+}
+
+func (h *GoalHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	parsedID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Printf("[ERROR] uuid.Parse - invalid UUID format: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.Delete(r.Context(), parsedID).HTTP(w)
+}
@@ -7,9 +7,14 @@ import (
 	"strings"
 
 	"github.com/fazriegi/netbase-be/internal/delivery/http/middleware"
+	"github.com/fazriegi/netbase-be/internal/infrastructure/blob"
+	"github.com/fazriegi/netbase-be/internal/infrastructure/gold"
+	"github.com/fazriegi/netbase-be/internal/infrastructure/oidc"
+	"github.com/fazriegi/netbase-be/internal/infrastructure/pdf"
 	"github.com/fazriegi/netbase-be/internal/infrastructure/yahoo"
 	"github.com/fazriegi/netbase-be/internal/repository"
 	"github.com/fazriegi/netbase-be/internal/usecase"
+	"github.com/fazriegi/netbase-be/pkg/env"
 	"github.com/jmoiron/sqlx"
 
 	"github.com/rs/cors"
@@ -20,7 +25,11 @@ func New(db *sqlx.DB, logger *log.Logger) http.Handler {
 
 	// USER
 	userRepo := repository.NewUserRepository(db)
-	authUC := usecase.NewUserUsecase(logger, userRepo, txManager)
+	authUC := usecase.NewUserUsecase(logger, userRepo, txManager, usecase.LoginThrottleConfigFromEnv())
+
+	// OIDC
+	oidcRepo := repository.NewOIDCRepository(db)
+	oidcUC := usecase.NewOIDCUsecase(logger, oidc.ProvidersFromEnv(), oidcRepo, userRepo, txManager)
 
 	// ASSET
 	yahooProvider := yahoo.NewYahooProvider(os.Getenv("RAPID_API_KEY"))
@@ -35,17 +44,92 @@ func New(db *sqlx.DB, logger *log.Logger) http.Handler {
 	networthRepo := repository.NewNetworthRepository(db)
 	networthUC := usecase.NewNetworthUsecase(logger, networthRepo)
 
+	// CATEGORY
+	categoryRepo := repository.NewCategoryRepository(db)
+	categoryUC := usecase.NewCategoryUsecase(logger, categoryRepo, txManager)
+
+	// TAG
+	tagRepo := repository.NewTagRepository(db)
+	tagUC := usecase.NewTagUsecase(logger, tagRepo, txManager)
+
+	// CATEGORIZATION RULE
+	ruleRepo := repository.NewRuleRepository(db)
+	ruleUC := usecase.NewRuleUsecase(logger, ruleRepo, categoryRepo, assetRepo)
+
+	// ATTACHMENT
+	blobStore := blob.NewLocalStore(env.GetString("ATTACHMENT_DIR", "storage/attachments"))
+	attachmentConfig := usecase.AttachmentConfigFromEnv()
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	attachmentUC := usecase.NewAttachmentUsecase(logger, attachmentRepo, blobStore, txManager, attachmentConfig)
+
 	// TRANSACTION
 	transactionRepo := repository.NewTransactionRepository(db)
-	transactionUC := usecase.NewTransactionUsecase(logger, transactionRepo, txManager, assetRepo, liabilityRepo)
+	transactionUC := usecase.NewTransactionUsecase(logger, transactionRepo, txManager, assetRepo, liabilityRepo, tagRepo, ruleRepo, attachmentRepo, blobStore)
+
+	// SEARCH
+	searchRepo := repository.NewSearchRepository(db)
+	searchUC := usecase.NewSearchUsecase(logger, searchRepo)
+
+	// REPORT
+	reportRepo := repository.NewReportRepository(db)
+	reportUC := usecase.NewReportUsecase(logger, reportRepo, pdf.NewStatementRenderer())
+
+	// INSIGHT
+	insightRepo := repository.NewInsightRepository(db)
+	insightUC := usecase.NewInsightUsecase(logger, insightRepo, txManager, usecase.InsightConfigFromEnv())
+
+	// SAVINGS GOAL
+	goalRepo := repository.NewGoalRepository(db)
+	goalUC := usecase.NewGoalUsecase(logger, goalRepo, assetRepo, txManager, usecase.GoalConfigFromEnv())
+
+	// ALLOCATION
+	allocationRepo := repository.NewAllocationRepository(db)
+	allocationUC := usecase.NewAllocationUsecase(logger, allocationRepo, networthRepo, categoryRepo, txManager)
+
+	// ZAKAT
+	zakatRepo := repository.NewZakatRepository(db)
+	goldPrice := gold.NewYahooProvider(yahooProvider, gold.NewStaticProvider(os.Getenv("GOLD_PRICE_PER_GRAM")), logger)
+	zakatUC := usecase.NewZakatUsecase(logger, zakatRepo, goldPrice)
+
+	// TAX
+	taxRepo := repository.NewTaxRepository(db)
+	taxUC := usecase.NewTaxUsecase(logger, taxRepo)
+
+	// SUBSCRIPTION
+	subscriptionRepo := repository.NewSubscriptionRepository(db)
+	subscriptionUC := usecase.NewSubscriptionUsecase(logger, subscriptionRepo, usecase.SubscriptionConfigFromEnv())
+
+	// PERSONAL ACCESS TOKEN
+	tokenRepo := repository.NewPersonalAccessTokenRepository(db)
+	tokenUC := usecase.NewTokenUsecase(logger, tokenRepo)
+	middleware.UsePersonalAccessTokens(tokenUC)
+
+	// WORKSPACE
+	workspaceRepo := repository.NewWorkspaceRepository(db)
+	workspaceUC := usecase.NewWorkspaceUsecase(logger, workspaceRepo, userRepo, txManager)
 
 	mux := http.NewServeMux()
 
 	NewUserHandler(mux, authUC, logger)
+	NewOIDCHandler(mux, oidcUC, logger, os.Getenv("OIDC_SUCCESS_REDIRECT_URL"))
 	NewAssetHandler(mux, assetUC, logger)
 	NewLiabilityHandler(mux, liabilityUC, logger)
 	NewNetworthHandler(mux, networthUC, logger)
 	NewTransactionHandler(mux, transactionUC, logger)
+	NewCategoryHandler(mux, categoryUC, logger)
+	NewTagHandler(mux, tagUC, logger)
+	NewRuleHandler(mux, ruleUC, logger)
+	NewAttachmentHandler(mux, attachmentUC, logger, attachmentConfig.MaxSize)
+	NewSearchHandler(mux, searchUC, logger)
+	NewReportHandler(mux, reportUC, logger)
+	NewInsightHandler(mux, insightUC, logger)
+	NewGoalHandler(mux, goalUC, logger)
+	NewAllocationHandler(mux, allocationUC, logger)
+	NewZakatHandler(mux, zakatUC, logger)
+	NewTaxHandler(mux, taxUC, logger)
+	NewSubscriptionHandler(mux, subscriptionUC, logger)
+	NewTokenHandler(mux, tokenUC, logger)
+	NewWorkspaceHandler(mux, workspaceUC, logger)
 
 	origin := os.Getenv("ALLOWED_ORIGIN")
 	if origin == "" {
@@ -55,7 +139,7 @@ func New(db *sqlx.DB, logger *log.Logger) http.Handler {
 	c := cors.New(cors.Options{
 		AllowedOrigins:   strings.Split(origin, ","),
 		AllowedMethods:   []string{"POST", "GET", "OPTIONS", "PUT", "DELETE"},
-		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-Requested-With"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-Requested-With", "X-Workspace-ID"},
 		AllowCredentials: true,
 	})
 
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/fazriegi/netbase-be/internal/delivery/http/middleware"
+	"github.com/fazriegi/netbase-be/internal/usecase"
+)
+
+type InsightHandler struct {
+	usecase usecase.InsightUsecase
+	logger  *log.Logger
+}
+
+func NewInsightHandler(mux *http.ServeMux, uc usecase.InsightUsecase, logger *log.Logger) {
+	h := &InsightHandler{
+		usecase: uc,
+		logger:  logger,
+	}
+
+	mux.Handle("GET /v1/insights", middleware.MiddlewareAuth(http.HandlerFunc(h.List)))
+}
+
+func (h *InsightHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.usecase.List(r.Context()).HTTP(w)
+}
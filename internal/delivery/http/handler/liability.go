@@ -25,7 +25,6 @@ func NewLiabilityHandler(mux *http.ServeMux, uc usecase.LiabilityUsecase, logger
 		logger:  logger,
 	}
 
-	mux.Handle("GET /v1/liabilities/categories", middleware.MiddlewareAuth(http.HandlerFunc(handler.ListCategory)))
 	mux.Handle("POST /v1/liabilities", middleware.MiddlewareAuth(http.HandlerFunc(handler.Create)))
 	mux.Handle("GET /v1/liabilities", middleware.MiddlewareAuth(http.HandlerFunc(handler.List)))
 	mux.Handle("GET /v1/liabilities/{id}", middleware.MiddlewareAuth(http.HandlerFunc(handler.GetByID)))
@@ -33,11 +32,6 @@ func NewLiabilityHandler(mux *http.ServeMux, uc usecase.LiabilityUsecase, logger
 	mux.Handle("DELETE /v1/liabilities/{id}", middleware.MiddlewareAuth(http.HandlerFunc(handler.Delete)))
 }
 
-func (h *LiabilityHandler) ListCategory(w http.ResponseWriter, r *http.Request) {
-	response := h.usecase.ListCategory(r.Context())
-	response.HTTP(w)
-}
-
 func (h *LiabilityHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req domain.CreateLiability
 
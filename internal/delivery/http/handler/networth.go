@@ -1,11 +1,16 @@
 package handler
 
 import (
+	"encoding/json"
 	"log"
 	"net/http"
 
 	"github.com/fazriegi/netbase-be/internal/delivery/http/middleware"
+	"github.com/fazriegi/netbase-be/internal/domain"
 	"github.com/fazriegi/netbase-be/internal/usecase"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/fazriegi/netbase-be/pkg/validator"
 )
 
 type NetworthHandler struct {
@@ -20,8 +25,34 @@ func NewNetworthHandler(mux *http.ServeMux, uc usecase.NetworthUsecase, logger *
 	}
 
 	mux.Handle("GET /v1/net-worth/current", middleware.MiddlewareAuth(http.HandlerFunc(handler.GetCurrent)))
+	mux.Handle("GET /v1/net-worth/health", middleware.MiddlewareAuth(http.HandlerFunc(handler.GetHealth)))
+	mux.Handle("POST /v1/net-worth/forecast", middleware.MiddlewareAuth(http.HandlerFunc(handler.Forecast)))
 }
 
 func (h *NetworthHandler) GetCurrent(w http.ResponseWriter, r *http.Request) {
 	h.usecase.GetCurrent(r.Context()).HTTP(w)
 }
+
+func (h *NetworthHandler) GetHealth(w http.ResponseWriter, r *http.Request) {
+	h.usecase.GetHealth(r.Context()).HTTP(w)
+}
+
+func (h *NetworthHandler) Forecast(w http.ResponseWriter, r *http.Request) {
+	var req domain.ForecastRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidJson, nil, nil).HTTP(w)
+		return
+	}
+
+	validationErr := validator.ValidateRequest(&req)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+		pkg.NewResponse(http.StatusUnprocessableEntity, constant.ErrValidation, errResponse, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.Forecast(r.Context(), &req).HTTP(w)
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/internal/usecase"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+)
+
+type OIDCHandler struct {
+	usecase         usecase.OIDCUsecase
+	logger          *log.Logger
+	successRedirect string
+}
+
+func NewOIDCHandler(mux *http.ServeMux, uc usecase.OIDCUsecase, logger *log.Logger, successRedirect string) {
+	h := &OIDCHandler{
+		usecase:         uc,
+		logger:          logger,
+		successRedirect: successRedirect,
+	}
+
+	mux.HandleFunc("GET /v1/auth/oidc/{provider}/login", h.Login)
+	mux.HandleFunc("GET /v1/auth/oidc/{provider}/callback", h.Callback)
+}
+
+const oidcStateCookie = "oidc_state"
+
+func hashOIDCState(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(sum[:])
+}
+
+// setOIDCStateCookie ties the login state to the browser that started the flow. An empty
+// state clears the cookie.
+func setOIDCStateCookie(w http.ResponseWriter, state string, expiresAt time.Time) {
+	cookie := &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    hashOIDCState(state),
+		Path:     "/v1/auth/oidc/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+		Expires:  expiresAt,
+	}
+	if state == "" {
+		cookie.Value = ""
+		cookie.MaxAge = -1
+		cookie.Expires = time.Time{}
+	}
+
+	http.SetCookie(w, cookie)
+}
+
+func (h *OIDCHandler) Login(w http.ResponseWriter, r *http.Request) {
+	response := h.usecase.Login(r.Context(), r.PathValue("provider"))
+	if response.Code != http.StatusFound {
+		response.HTTP(w)
+		return
+	}
+
+	data, ok := response.Data.(map[string]any)
+	if !ok {
+		h.logger.Printf("[ERROR] failed to convert data")
+		pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil).HTTP(w)
+		return
+	}
+
+	expiresAt, _ := data["expires_at"].(time.Time)
+	setOIDCStateCookie(w, fmt.Sprint(data["state"]), expiresAt)
+
+	http.Redirect(w, r, fmt.Sprint(data["redirect_url"]), http.StatusFound)
+}
+
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if providerErr := query.Get("error"); providerErr != "" {
+		h.logger.Printf("[ERROR] OIDC provider returned error: %s %s", providerErr, query.Get("error_description"))
+		pkg.NewResponse(http.StatusBadRequest, "Login was cancelled or rejected by the provider", nil, nil).HTTP(w)
+		return
+	}
+
+	if query.Get("code") == "" || query.Get("state") == "" {
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+		return
+	}
+
+	// the state must come back to the browser that started the login, otherwise an
+	// attacker could plant their own code and state on a victim (login CSRF)
+	cookie, err := r.Cookie(oidcStateCookie)
+	setOIDCStateCookie(w, "", time.Time{})
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(hashOIDCState(query.Get("state")))) != 1 {
+		pkg.NewResponse(http.StatusBadRequest, "Invalid or expired login state", nil, nil).HTTP(w)
+		return
+	}
+
+	response := h.usecase.Callback(r.Context(), &domain.OIDCCallbackRequest{
+		Provider:   r.PathValue("provider"),
+		Code:       query.Get("code"),
+		State:      query.Get("state"),
+		RemoteAddr: pkg.ClientIP(r),
+	})
+	if response.Data != nil {
+		data, ok := response.Data.(map[string]any)
+		if !ok {
+			h.logger.Printf("[ERROR] failed to convert data")
+			pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil).HTTP(w)
+			return
+		}
+
+		setAuthCookies(w, fmt.Sprint(data["access_token"]), fmt.Sprint(data["refresh_token"]))
+
+		delete(data, "access_token")
+		delete(data, "refresh_token")
+
+		if h.successRedirect != "" {
+			http.Redirect(w, r, h.successRedirect, http.StatusFound)
+			return
+		}
+	}
+
+	response.HTTP(w)
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/internal/usecase"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+)
+
+// memoryOIDCRepo keeps login states in memory. Identities aren't needed since every
+// test is rejected before the user is resolved.
+type memoryOIDCRepo struct {
+	domain.OIDCRepository
+
+	mu     sync.Mutex
+	states map[string]*domain.OIDCLoginState
+}
+
+func (r *memoryOIDCRepo) InsertState(ctx context.Context, data *domain.OIDCLoginState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states[data.State] = data
+	return nil
+}
+
+func (r *memoryOIDCRepo) ConsumeState(ctx context.Context, state string) (*domain.OIDCLoginState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	loginState, ok := r.states[state]
+	if !ok {
+		return nil, errors.New(constant.ErrNotFound)
+	}
+	delete(r.states, state)
+	return loginState, nil
+}
+
+// stubProvider returns a fixed nonce from Exchange, standing in for a provider that
+// issued the ID token to another login.
+type stubProvider struct {
+	nonce string
+}
+
+func (p *stubProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	return "https://provider.test/authorize?" + url.Values{"state": {state}, "nonce": {nonce}}.Encode(), nil
+}
+
+func (p *stubProvider) Exchange(ctx context.Context, code, codeVerifier string) (*domain.OIDCClaims, error) {
+	return &domain.OIDCClaims{Subject: "subject-1", Nonce: p.nonce}, nil
+}
+
+func newTestOIDCHandler(provider domain.OIDCProvider) (http.Handler, *memoryOIDCRepo) {
+	repo := &memoryOIDCRepo{states: map[string]*domain.OIDCLoginState{}}
+	logger := log.New(io.Discard, "", 0)
+	uc := usecase.NewOIDCUsecase(logger, map[string]domain.OIDCProvider{"mock": provider}, repo, nil, nil)
+
+	mux := http.NewServeMux()
+	NewOIDCHandler(mux, uc, logger, "")
+	return mux, repo
+}
+
+// startLogin runs the login endpoint and returns the state sent to the provider
+// together with the state cookie set on the browser.
+func startLogin(t *testing.T, h http.Handler) (string, *http.Cookie) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/v1/auth/oidc/mock/login", nil))
+	if rec.Code != http.StatusFound {
+		t.Fatalf("login returned %d, want 302", rec.Code)
+	}
+
+	location, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == oidcStateCookie {
+			if !c.HttpOnly || !c.Secure || c.SameSite != http.SameSiteLaxMode {
+				t.Errorf("state cookie %+v isn't HttpOnly, Secure and SameSite=Lax", c)
+			}
+			return location.Query().Get("state"), c
+		}
+	}
+
+	t.Fatal("login didn't set the state cookie")
+	return "", nil
+}
+
+func callback(h http.Handler, state string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/v1/auth/oidc/mock/callback?"+url.Values{"code": {"code-1"}, "state": {state}}.Encode(), nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestOIDCCallbackRejectsStateFromAnotherBrowser(t *testing.T) {
+	h, repo := newTestOIDCHandler(&stubProvider{})
+
+	attackerState, _ := startLogin(t, h)
+	_, victimCookie := startLogin(t, h)
+
+	if rec := callback(h, attackerState, victimCookie); rec.Code != http.StatusBadRequest {
+		t.Errorf("callback with another browser's state returned %d, want 400", rec.Code)
+	}
+	if rec := callback(h, attackerState, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("callback without the state cookie returned %d, want 400", rec.Code)
+	}
+
+	if _, ok := repo.states[attackerState]; !ok {
+		t.Error("a rejected callback consumed the login state")
+	}
+}
+
+func TestOIDCCallbackRejectsReusedState(t *testing.T) {
+	h, _ := newTestOIDCHandler(&stubProvider{})
+
+	state, cookie := startLogin(t, h)
+	callback(h, state, cookie)
+
+	if rec := callback(h, state, cookie); rec.Code != http.StatusBadRequest {
+		t.Errorf("callback with a consumed state returned %d, want 400", rec.Code)
+	}
+}
+
+func TestOIDCCallbackRejectsNonceMismatch(t *testing.T) {
+	h, _ := newTestOIDCHandler(&stubProvider{nonce: "nonce-of-another-login"})
+
+	state, cookie := startLogin(t, h)
+	rec := callback(h, state, cookie)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("callback with a foreign nonce returned %d, want 401", rec.Code)
+	}
+
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == "access_token" {
+			t.Error("callback with a foreign nonce set a session cookie")
+		}
+		if c.Name == oidcStateCookie && c.MaxAge >= 0 {
+			t.Error("callback didn't clear the state cookie")
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/fazriegi/netbase-be/internal/delivery/http/middleware"
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/internal/usecase"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+)
+
+type ReportHandler struct {
+	usecase usecase.ReportUsecase
+	logger  *log.Logger
+}
+
+func NewReportHandler(mux *http.ServeMux, uc usecase.ReportUsecase, logger *log.Logger) {
+	h := &ReportHandler{
+		usecase: uc,
+		logger:  logger,
+	}
+
+	mux.Handle("GET /v1/reports/cashflow", middleware.MiddlewareAuth(http.HandlerFunc(h.GetCashflow)))
+	mux.Handle("GET /v1/reports/income-statement", middleware.MiddlewareAuth(http.HandlerFunc(h.GetIncomeStatement)))
+	mux.Handle("GET /v1/reports/balance-sheet", middleware.MiddlewareAuth(http.HandlerFunc(h.GetBalanceSheet)))
+	mux.Handle("GET /v1/reports/comparison", middleware.MiddlewareAuth(http.HandlerFunc(h.GetComparison)))
+}
+
+// writeReport sends a rendered report as a download and anything else as JSON.
+func (h *ReportHandler) writeReport(w http.ResponseWriter, response pkg.Response) {
+	file, isFile := response.Data.(*domain.ReportFile)
+	if response.Code != http.StatusOK || !isFile {
+		response.HTTP(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(file.Content)))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.FileName}))
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(file.Content); err != nil {
+		h.logger.Printf("[ERROR] write report: %s", err.Error())
+	}
+}
+
+func (h *ReportHandler) GetCashflow(w http.ResponseWriter, r *http.Request) {
+	var req domain.CashflowRequest
+
+	if err := pkg.ParseQueryParam(r, &req); err != nil {
+		h.logger.Printf("[ERROR] parsing query params: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrParseQueryParam, nil, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.GetCashflow(r.Context(), &req).HTTP(w)
+}
+
+func (h *ReportHandler) GetIncomeStatement(w http.ResponseWriter, r *http.Request) {
+	var req domain.StatementRequest
+
+	if err := pkg.ParseQueryParam(r, &req); err != nil {
+		h.logger.Printf("[ERROR] parsing query params: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrParseQueryParam, nil, nil).HTTP(w)
+		return
+	}
+
+	h.writeReport(w, h.usecase.GetIncomeStatement(r.Context(), &req))
+}
+
+func (h *ReportHandler) GetBalanceSheet(w http.ResponseWriter, r *http.Request) {
+	var req domain.StatementRequest
+
+	if err := pkg.ParseQueryParam(r, &req); err != nil {
+		h.logger.Printf("[ERROR] parsing query params: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrParseQueryParam, nil, nil).HTTP(w)
+		return
+	}
+
+	h.writeReport(w, h.usecase.GetBalanceSheet(r.Context(), &req))
+}
+
+func (h *ReportHandler) GetComparison(w http.ResponseWriter, r *http.Request) {
+	var req domain.ComparisonRequest
+
+	if err := pkg.ParseQueryParam(r, &req); err != nil {
+		h.logger.Printf("[ERROR] parsing query params: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrParseQueryParam, nil, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.GetComparison(r.Context(), &req).HTTP(w)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/fazriegi/netbase-be/internal/delivery/http/middleware"
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/internal/usecase"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/fazriegi/netbase-be/pkg/validator"
+	"github.com/google/uuid"
+)
+
+type RuleHandler struct {
+	usecase usecase.RuleUsecase
+	logger  *log.Logger
+}
+
+func NewRuleHandler(mux *http.ServeMux, uc usecase.RuleUsecase, logger *log.Logger) {
+	h := &RuleHandler{
+		usecase: uc,
+		logger:  logger,
+	}
+
+	mux.Handle("GET /v1/transactions/rules", middleware.MiddlewareAuth(http.HandlerFunc(h.List)))
+	mux.Handle("POST /v1/transactions/rules", middleware.MiddlewareAuth(http.HandlerFunc(h.Create)))
+	mux.Handle("PUT /v1/transactions/rules/{id}", middleware.MiddlewareAuth(http.HandlerFunc(h.Update)))
+	mux.Handle("DELETE /v1/transactions/rules/{id}", middleware.MiddlewareAuth(http.HandlerFunc(h.Delete)))
+}
+
+func (h *RuleHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.usecase.List(r.Context()).HTTP(w)
+}
+
+func (h *RuleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateRule
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidJson, nil, nil).HTTP(w)
+		return
+	}
+
+	validationErr := validator.ValidateRequest(&req)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+		pkg.NewResponse(http.StatusUnprocessableEntity, constant.ErrValidation, errResponse, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.Create(r.Context(), &req).HTTP(w)
+}
+
+func (h *RuleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateRule
+
+	parsedID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Printf("[ERROR] uuid.Parse - invalid UUID format: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidJson, nil, nil).HTTP(w)
+		return
+	}
+
+	validationErr := validator.ValidateRequest(&req)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+		pkg.NewResponse(http.StatusUnprocessableEntity, constant.ErrValidation, errResponse, nil).HTTP(w)
+		return
+	}
+	req.ID = parsedID
+
+	h.usecase.Update(r.Context(), &req).HTTP(w)
+}
+
+func (h *RuleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	parsedID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Printf("[ERROR] uuid.Parse - invalid UUID format: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.Delete(r.Context(), parsedID).HTTP(w)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/fazriegi/netbase-be/internal/delivery/http/middleware"
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/internal/usecase"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+)
+
+type SearchHandler struct {
+	usecase usecase.SearchUsecase
+	logger  *log.Logger
+}
+
+func NewSearchHandler(mux *http.ServeMux, uc usecase.SearchUsecase, logger *log.Logger) {
+	h := &SearchHandler{
+		usecase: uc,
+		logger:  logger,
+	}
+
+	mux.Handle("GET /v1/search", middleware.MiddlewareAuth(http.HandlerFunc(h.Search)))
+}
+
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	var req domain.SearchRequest
+
+	if err := pkg.ParseQueryParam(r, &req); err != nil {
+		h.logger.Printf("[ERROR] parsing query params: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrParseQueryParam, nil, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.Search(r.Context(), &req).HTTP(w)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/fazriegi/netbase-be/internal/delivery/http/middleware"
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/internal/usecase"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/fazriegi/netbase-be/pkg/validator"
+	"github.com/google/uuid"
+)
+
+type SubscriptionHandler struct {
+	usecase usecase.SubscriptionUsecase
+	logger  *log.Logger
+}
+
+func NewSubscriptionHandler(mux *http.ServeMux, uc usecase.SubscriptionUsecase, logger *log.Logger) {
+	h := &SubscriptionHandler{
+		usecase: uc,
+		logger:  logger,
+	}
+
+	mux.Handle("GET /v1/subscriptions", middleware.MiddlewareAuth(http.HandlerFunc(h.List)))
+	mux.Handle("GET /v1/subscriptions/detected", middleware.MiddlewareAuth(http.HandlerFunc(h.Detect)))
+	mux.Handle("POST /v1/subscriptions/confirm", middleware.MiddlewareAuth(http.HandlerFunc(h.Confirm)))
+	mux.Handle("POST /v1/subscriptions/dismiss", middleware.MiddlewareAuth(http.HandlerFunc(h.Dismiss)))
+	mux.Handle("DELETE /v1/subscriptions/{id}", middleware.MiddlewareAuth(http.HandlerFunc(h.Delete)))
+}
+
+func (h *SubscriptionHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.usecase.List(r.Context()).HTTP(w)
+}
+
+func (h *SubscriptionHandler) Detect(w http.ResponseWriter, r *http.Request) {
+	h.usecase.Detect(r.Context()).HTTP(w)
+}
+
+func (h *SubscriptionHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, domain.SubscriptionConfirmed)
+}
+
+func (h *SubscriptionHandler) Dismiss(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, domain.SubscriptionDismissed)
+}
+
+func (h *SubscriptionHandler) decide(w http.ResponseWriter, r *http.Request, status string) {
+	var req domain.DecideSubscription
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidJson, nil, nil).HTTP(w)
+		return
+	}
+
+	validationErr := validator.ValidateRequest(&req)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+		pkg.NewResponse(http.StatusUnprocessableEntity, constant.ErrValidation, errResponse, nil).HTTP(w)
+		return
+	}
+
+	req.Status = status
+	h.usecase.Decide(r.Context(), &req).HTTP(w)
+}
+
+func (h *SubscriptionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	parsedID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Printf("[ERROR] uuid.Parse - invalid UUID format: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.Delete(r.Context(), parsedID).HTTP(w)
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/fazriegi/netbase-be/internal/delivery/http/middleware"
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/internal/usecase"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/fazriegi/netbase-be/pkg/validator"
+	"github.com/google/uuid"
+)
+
+type TagHandler struct {
+	usecase usecase.TagUsecase
+	logger  *log.Logger
+}
+
+func NewTagHandler(mux *http.ServeMux, uc usecase.TagUsecase, logger *log.Logger) {
+	h := &TagHandler{
+		usecase: uc,
+		logger:  logger,
+	}
+
+	mux.Handle("GET /v1/transactions/tags", middleware.MiddlewareAuth(http.HandlerFunc(h.List)))
+	mux.Handle("PUT /v1/transactions/tags/{id}", middleware.MiddlewareAuth(http.HandlerFunc(h.Rename)))
+	mux.Handle("DELETE /v1/transactions/tags/{id}", middleware.MiddlewareAuth(http.HandlerFunc(h.Delete)))
+	mux.Handle("POST /v1/transactions/tags/{id}/merge", middleware.MiddlewareAuth(http.HandlerFunc(h.Merge)))
+}
+
+func (h *TagHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.usecase.List(r.Context()).HTTP(w)
+}
+
+func (h *TagHandler) Rename(w http.ResponseWriter, r *http.Request) {
+	var req domain.UpdateTag
+
+	parsedID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Printf("[ERROR] uuid.Parse - invalid UUID format: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidJson, nil, nil).HTTP(w)
+		return
+	}
+
+	validationErr := validator.ValidateRequest(&req)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+		pkg.NewResponse(http.StatusUnprocessableEntity, constant.ErrValidation, errResponse, nil).HTTP(w)
+		return
+	}
+	req.ID = parsedID
+
+	h.usecase.Rename(r.Context(), &req).HTTP(w)
+}
+
+func (h *TagHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	var req domain.MergeTag
+
+	parsedID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Printf("[ERROR] uuid.Parse - invalid UUID format: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidJson, nil, nil).HTTP(w)
+		return
+	}
+
+	validationErr := validator.ValidateRequest(&req)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+		pkg.NewResponse(http.StatusUnprocessableEntity, constant.ErrValidation, errResponse, nil).HTTP(w)
+		return
+	}
+	req.SourceID = parsedID
+
+	h.usecase.Merge(r.Context(), &req).HTTP(w)
+}
+
+func (h *TagHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	parsedID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Printf("[ERROR] uuid.Parse - invalid UUID format: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.Delete(r.Context(), parsedID).HTTP(w)
+}
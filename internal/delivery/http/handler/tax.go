@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/fazriegi/netbase-be/internal/delivery/http/middleware"
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/internal/usecase"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+)
+
+type TaxHandler struct {
+	usecase usecase.TaxUsecase
+	logger  *log.Logger
+}
+
+func NewTaxHandler(mux *http.ServeMux, uc usecase.TaxUsecase, logger *log.Logger) {
+	h := &TaxHandler{
+		usecase: uc,
+		logger:  logger,
+	}
+
+	mux.Handle("GET /v1/tax/income-estimate", middleware.MiddlewareAuth(http.HandlerFunc(h.EstimateIncomeTax)))
+}
+
+func (h *TaxHandler) EstimateIncomeTax(w http.ResponseWriter, r *http.Request) {
+	var req domain.TaxEstimateRequest
+
+	if err := pkg.ParseQueryParam(r, &req); err != nil {
+		h.logger.Printf("[ERROR] parsing query params: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrParseQueryParam, nil, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.EstimateIncomeTax(r.Context(), &req).HTTP(w)
+}
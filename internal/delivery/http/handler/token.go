@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/fazriegi/netbase-be/internal/delivery/http/middleware"
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/internal/usecase"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/fazriegi/netbase-be/pkg/validator"
+	"github.com/google/uuid"
+)
+
+type TokenHandler struct {
+	usecase usecase.TokenUsecase
+	logger  *log.Logger
+}
+
+func NewTokenHandler(mux *http.ServeMux, uc usecase.TokenUsecase, logger *log.Logger) {
+	h := &TokenHandler{
+		usecase: uc,
+		logger:  logger,
+	}
+
+	mux.Handle("GET /v1/tokens", middleware.MiddlewareAuth(http.HandlerFunc(h.List)))
+	mux.Handle("POST /v1/tokens", middleware.MiddlewareAuth(http.HandlerFunc(h.Create)))
+	mux.Handle("DELETE /v1/tokens/{id}", middleware.MiddlewareAuth(http.HandlerFunc(h.Delete)))
+}
+
+func (h *TokenHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.usecase.List(r.Context()).HTTP(w)
+}
+
+func (h *TokenHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreatePersonalAccessToken
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidJson, nil, nil).HTTP(w)
+		return
+	}
+
+	validationErr := validator.ValidateRequest(&req)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+		pkg.NewResponse(http.StatusUnprocessableEntity, constant.ErrValidation, errResponse, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.Create(r.Context(), &req).HTTP(w)
+}
+
+func (h *TokenHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		h.logger.Printf("[ERROR] uuid.Parse - invalid UUID format: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.Delete(r.Context(), parsedID).HTTP(w)
+}
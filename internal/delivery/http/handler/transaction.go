@@ -27,14 +27,14 @@ func NewTransactionHandler(mux *http.ServeMux, uc usecase.TransactionUsecase, lo
 
 	mux.Handle("GET /v1/transactions", middleware.MiddlewareAuth(http.HandlerFunc(h.List)))
 	mux.Handle("GET /v1/transactions/summary", middleware.MiddlewareAuth(http.HandlerFunc(h.GetSummary)))
+	mux.Handle("GET /v1/transactions/summary/tags", middleware.MiddlewareAuth(http.HandlerFunc(h.GetTagSummary)))
 	mux.Handle("GET /v1/transactions/{id}", middleware.MiddlewareAuth(http.HandlerFunc(h.GetByID)))
 	mux.Handle("POST /v1/transactions", middleware.MiddlewareAuth(http.HandlerFunc(h.Create)))
+	mux.Handle("POST /v1/transactions/bulk", middleware.MiddlewareAuth(http.HandlerFunc(h.Bulk)))
 	mux.Handle("PUT /v1/transactions/{id}", middleware.MiddlewareAuth(http.HandlerFunc(h.Update)))
 	mux.Handle("DELETE /v1/transactions/{id}", middleware.MiddlewareAuth(http.HandlerFunc(h.Delete)))
-
-	mux.Handle("GET /v1/transactions/categories", middleware.MiddlewareAuth(http.HandlerFunc(h.ListCategory)))
-	mux.Handle("POST /v1/transactions/categories", middleware.MiddlewareAuth(http.HandlerFunc(h.CreateCategory)))
-	mux.Handle("DELETE /v1/transactions/categories/{id}", middleware.MiddlewareAuth(http.HandlerFunc(h.DeleteCategory)))
+	mux.Handle("GET /v1/transactions/rules/{id}/preview", middleware.MiddlewareAuth(http.HandlerFunc(h.PreviewRule)))
+	mux.Handle("POST /v1/transactions/rules/{id}/apply", middleware.MiddlewareAuth(http.HandlerFunc(h.ApplyRule)))
 }
 
 func (h *TransactionHandler) List(w http.ResponseWriter, r *http.Request) {
@@ -61,6 +61,18 @@ func (h *TransactionHandler) GetSummary(w http.ResponseWriter, r *http.Request)
 	h.usecase.GetSummary(r.Context(), &req).HTTP(w)
 }
 
+func (h *TransactionHandler) GetTagSummary(w http.ResponseWriter, r *http.Request) {
+	var req domain.ListTransactionRequest
+
+	if err := pkg.ParseQueryParam(r, &req); err != nil {
+		h.logger.Printf("[ERROR] parsing query params: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrParseQueryParam, nil, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.GetTagSummary(r.Context(), &req).HTTP(w)
+}
+
 func (h *TransactionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	parsedID, err := uuid.Parse(id)
@@ -133,20 +145,8 @@ func (h *TransactionHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	h.usecase.Delete(r.Context(), parsedID).HTTP(w)
 }
 
-func (h *TransactionHandler) ListCategory(w http.ResponseWriter, r *http.Request) {
-	var req domain.ListCategoryRequest
-
-	if err := pkg.ParseQueryParam(r, &req); err != nil {
-		h.logger.Printf("[ERROR] parsing query params: %s", err.Error())
-		pkg.NewResponse(http.StatusBadRequest, constant.ErrParseQueryParam, nil, nil).HTTP(w)
-		return
-	}
-
-	h.usecase.ListCategory(r.Context(), &req).HTTP(w)
-}
-
-func (h *TransactionHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
-	var req domain.Category
+func (h *TransactionHandler) Bulk(w http.ResponseWriter, r *http.Request) {
+	var req domain.BulkTransactionRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidJson, nil, nil).HTTP(w)
@@ -162,17 +162,27 @@ func (h *TransactionHandler) CreateCategory(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	h.usecase.CreateCategory(r.Context(), &req).HTTP(w)
+	h.usecase.Bulk(r.Context(), &req).HTTP(w)
 }
 
-func (h *TransactionHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
-	id := r.PathValue("id")
-	parsedID, err := uuid.Parse(id)
+func (h *TransactionHandler) PreviewRule(w http.ResponseWriter, r *http.Request) {
+	parsedID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Printf("[ERROR] uuid.Parse - invalid UUID format: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.PreviewRule(r.Context(), parsedID).HTTP(w)
+}
+
+func (h *TransactionHandler) ApplyRule(w http.ResponseWriter, r *http.Request) {
+	parsedID, err := uuid.Parse(r.PathValue("id"))
 	if err != nil {
 		h.logger.Printf("[ERROR] uuid.Parse - invalid UUID format: %s", err.Error())
 		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
 		return
 	}
 
-	h.usecase.DeleteCategory(r.Context(), parsedID).HTTP(w)
+	h.usecase.ApplyRule(r.Context(), parsedID).HTTP(w)
 }
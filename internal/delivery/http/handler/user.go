@@ -12,6 +12,7 @@ import (
 	"github.com/fazriegi/netbase-be/internal/usecase"
 	"github.com/fazriegi/netbase-be/pkg"
 	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/fazriegi/netbase-be/pkg/token"
 	"github.com/fazriegi/netbase-be/pkg/validator"
 )
 
@@ -30,11 +31,12 @@ func NewUserHandler(mux *http.ServeMux, uc usecase.UserUsecase, logger *log.Logg
 	mux.HandleFunc("POST /v1/login", handler.Login)
 	mux.HandleFunc("POST /v1/refresh_token", handler.RefreshToken)
 	mux.HandleFunc("POST /v1/logout", handler.Logout)
+	mux.HandleFunc("GET /.well-known/jwks.json", handler.JWKS)
 
 	mux.Handle("GET /v1/profile", middleware.MiddlewareAuth(http.HandlerFunc(handler.Profile)))
 }
 
-func (h *UserHandler) setAuthCookies(w http.ResponseWriter, accessToken, refreshToken string) {
+func setAuthCookies(w http.ResponseWriter, accessToken, refreshToken string) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     "access_token",
 		Value:    accessToken,
@@ -98,7 +100,7 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	req.RemoteAddr = r.RemoteAddr
+	req.RemoteAddr = pkg.ClientIP(r)
 
 	response := h.usecase.Login(r.Context(), &req)
 	if response.Data != nil {
@@ -110,7 +112,7 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		h.setAuthCookies(w, fmt.Sprint(data["access_token"]), fmt.Sprint(data["refresh_token"]))
+		setAuthCookies(w, fmt.Sprint(data["access_token"]), fmt.Sprint(data["refresh_token"]))
 
 		delete(data, "access_token")
 		delete(data, "refresh_token")
@@ -131,7 +133,7 @@ func (h *UserHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := h.usecase.RefreshToken(r.Context(), cookie.Value, r.RemoteAddr)
+	response := h.usecase.RefreshToken(r.Context(), cookie.Value, pkg.ClientIP(r))
 	if response.Data != nil {
 		data, ok := response.Data.(map[string]any)
 		if !ok {
@@ -141,7 +143,7 @@ func (h *UserHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		h.setAuthCookies(w, fmt.Sprint(data["access_token"]), fmt.Sprint(data["refresh_token"]))
+		setAuthCookies(w, fmt.Sprint(data["access_token"]), fmt.Sprint(data["refresh_token"]))
 
 		delete(data, "access_token")
 		delete(data, "refresh_token")
@@ -150,13 +152,13 @@ func (h *UserHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *UserHandler) Profile(w http.ResponseWriter, r *http.Request) {
-	cookie, err := r.Cookie("access_token")
-	if err != nil {
-		pkg.NewResponse(http.StatusUnauthorized, constant.ErrInvalidToken, nil, nil).HTTP(w)
-		return
+	// MiddlewareAuth already authenticated the request, either by cookie or by bearer token
+	var accessToken string
+	if cookie, err := r.Cookie("access_token"); err == nil {
+		accessToken = cookie.Value
 	}
 
-	response := h.usecase.Profile(r.Context(), cookie.Value)
+	response := h.usecase.Profile(r.Context(), accessToken)
 
 	response.HTTP(w)
 }
@@ -181,7 +183,14 @@ func (h *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
 
 	response := h.usecase.Logout(r.Context(), accessToken.Value, refreshToken.Value)
 
-	h.setAuthCookies(w, "", "")
+	setAuthCookies(w, "", "")
 
 	response.HTTP(w)
 }
+
+// JWKS publishes the public keys used to verify access tokens, in the standard JWK Set format.
+func (h *UserHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(token.PublicJWKS())
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/fazriegi/netbase-be/internal/delivery/http/middleware"
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/internal/usecase"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/fazriegi/netbase-be/pkg/validator"
+	"github.com/google/uuid"
+)
+
+type WorkspaceHandler struct {
+	usecase usecase.WorkspaceUsecase
+	logger  *log.Logger
+}
+
+func NewWorkspaceHandler(mux *http.ServeMux, uc usecase.WorkspaceUsecase, logger *log.Logger) {
+	h := &WorkspaceHandler{
+		usecase: uc,
+		logger:  logger,
+	}
+
+	mux.Handle("GET /v1/workspaces", middleware.MiddlewareAuth(http.HandlerFunc(h.List)))
+	mux.Handle("POST /v1/workspaces", middleware.MiddlewareAuth(http.HandlerFunc(h.Create)))
+	mux.Handle("PUT /v1/workspaces/{id}", middleware.MiddlewareAuth(http.HandlerFunc(h.Update)))
+	mux.Handle("DELETE /v1/workspaces/{id}", middleware.MiddlewareAuth(http.HandlerFunc(h.Delete)))
+
+	mux.Handle("GET /v1/workspaces/{id}/members", middleware.MiddlewareAuth(http.HandlerFunc(h.ListMembers)))
+	mux.Handle("PUT /v1/workspaces/{id}/members/{user_id}", middleware.MiddlewareAuth(http.HandlerFunc(h.UpdateMember)))
+	mux.Handle("DELETE /v1/workspaces/{id}/members/{user_id}", middleware.MiddlewareAuth(http.HandlerFunc(h.RemoveMember)))
+	mux.Handle("POST /v1/workspaces/{id}/invitations", middleware.MiddlewareAuth(http.HandlerFunc(h.InviteMember)))
+
+	mux.Handle("GET /v1/workspaces/invitations", middleware.MiddlewareAuth(http.HandlerFunc(h.ListInvitations)))
+	mux.Handle("POST /v1/workspaces/invitations/{id}/accept", middleware.MiddlewareAuth(http.HandlerFunc(h.AcceptInvitation)))
+	mux.Handle("POST /v1/workspaces/invitations/{id}/decline", middleware.MiddlewareAuth(http.HandlerFunc(h.DeclineInvitation)))
+}
+
+func (h *WorkspaceHandler) pathUUID(w http.ResponseWriter, r *http.Request, name string) (uuid.UUID, bool) {
+	parsedID, err := uuid.Parse(r.PathValue(name))
+	if err != nil {
+		h.logger.Printf("[ERROR] uuid.Parse - invalid UUID format: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+		return uuid.Nil, false
+	}
+	return parsedID, true
+}
+
+func (h *WorkspaceHandler) decode(w http.ResponseWriter, r *http.Request, req any) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidJson, nil, nil).HTTP(w)
+		return false
+	}
+
+	validationErr := validator.ValidateRequest(req)
+	if len(validationErr) > 0 {
+		errResponse := map[string]any{
+			"errors": validationErr,
+		}
+		pkg.NewResponse(http.StatusUnprocessableEntity, constant.ErrValidation, errResponse, nil).HTTP(w)
+		return false
+	}
+
+	return true
+}
+
+func (h *WorkspaceHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.usecase.List(r.Context()).HTTP(w)
+}
+
+func (h *WorkspaceHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateWorkspace
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	h.usecase.Create(r.Context(), &req).HTTP(w)
+}
+
+func (h *WorkspaceHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.CreateWorkspace
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	req.ID = id
+	h.usecase.Update(r.Context(), &req).HTTP(w)
+}
+
+func (h *WorkspaceHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	h.usecase.Delete(r.Context(), id).HTTP(w)
+}
+
+func (h *WorkspaceHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	h.usecase.ListMembers(r.Context(), id).HTTP(w)
+}
+
+func (h *WorkspaceHandler) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	userID, ok := h.pathUUID(w, r, "user_id")
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateWorkspaceMember
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	req.WorkspaceID = id
+	req.UserID = userID
+	h.usecase.UpdateMember(r.Context(), &req).HTTP(w)
+}
+
+func (h *WorkspaceHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	userID, ok := h.pathUUID(w, r, "user_id")
+	if !ok {
+		return
+	}
+
+	h.usecase.RemoveMember(r.Context(), id, userID).HTTP(w)
+}
+
+func (h *WorkspaceHandler) InviteMember(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.InviteWorkspaceMember
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	req.WorkspaceID = id
+	h.usecase.InviteMember(r.Context(), &req).HTTP(w)
+}
+
+func (h *WorkspaceHandler) ListInvitations(w http.ResponseWriter, r *http.Request) {
+	h.usecase.ListInvitations(r.Context()).HTTP(w)
+}
+
+func (h *WorkspaceHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	h.usecase.RespondInvitation(r.Context(), id, true).HTTP(w)
+}
+
+func (h *WorkspaceHandler) DeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	h.usecase.RespondInvitation(r.Context(), id, false).HTTP(w)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/fazriegi/netbase-be/internal/delivery/http/middleware"
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/internal/usecase"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+)
+
+type ZakatHandler struct {
+	usecase usecase.ZakatUsecase
+	logger  *log.Logger
+}
+
+func NewZakatHandler(mux *http.ServeMux, uc usecase.ZakatUsecase, logger *log.Logger) {
+	h := &ZakatHandler{
+		usecase: uc,
+		logger:  logger,
+	}
+
+	mux.Handle("GET /v1/zakat", middleware.MiddlewareAuth(http.HandlerFunc(h.Estimate)))
+}
+
+func (h *ZakatHandler) Estimate(w http.ResponseWriter, r *http.Request) {
+	var req domain.ZakatRequest
+
+	if err := pkg.ParseQueryParam(r, &req); err != nil {
+		h.logger.Printf("[ERROR] parsing query params: %s", err.Error())
+		pkg.NewResponse(http.StatusBadRequest, constant.ErrParseQueryParam, nil, nil).HTTP(w)
+		return
+	}
+
+	h.usecase.Estimate(r.Context(), &req).HTTP(w)
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"strings"
 
 	"github.com/fazriegi/netbase-be/pkg"
 	"github.com/fazriegi/netbase-be/pkg/constant"
@@ -10,15 +11,54 @@ import (
 	"github.com/google/uuid"
 )
 
+type PATAuthenticator interface {
+	Authenticate(ctx context.Context, raw string) (userID uuid.UUID, scopes []string, err error)
+}
+
+var patAuthenticator PATAuthenticator
+
+// UsePersonalAccessTokens enables personal access tokens as bearer credentials in MiddlewareAuth.
+func UsePersonalAccessTokens(a PATAuthenticator) {
+	patAuthenticator = a
+}
+
 func MiddlewareAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		cookie, err := r.Cookie("access_token")
-		if err != nil || cookie.Value == "" {
+		rawToken := bearerToken(r)
+		if rawToken == "" {
+			cookie, err := r.Cookie("access_token")
+			if err == nil {
+				rawToken = cookie.Value
+			}
+		}
+
+		if rawToken == "" {
 			pkg.NewResponse(http.StatusUnauthorized, constant.ErrInvalidToken, nil, nil).HTTP(w)
 			return
 		}
 
-		claims, err := token.ValidateToken(cookie.Value)
+		if token.IsPAT(rawToken) {
+			if patAuthenticator == nil {
+				pkg.NewResponse(http.StatusUnauthorized, constant.ErrInvalidToken, nil, nil).HTTP(w)
+				return
+			}
+
+			userID, scopes, err := patAuthenticator.Authenticate(r.Context(), rawToken)
+			if err != nil {
+				pkg.NewResponse(http.StatusUnauthorized, constant.ErrInvalidToken, nil, nil).HTTP(w)
+				return
+			}
+
+			if !token.ScopeAllows(scopes, resourceFromPath(r.URL.Path), accessFromRequest(r)) {
+				pkg.NewResponse(http.StatusForbidden, constant.ErrNotAuthorized, nil, nil).HTTP(w)
+				return
+			}
+
+			withUser(w, r.WithContext(context.WithValue(r.Context(), "pat_scopes", scopes)), next, userID)
+			return
+		}
+
+		claims, err := token.ValidateAccessToken(rawToken)
 		if err != nil {
 			pkg.NewResponse(http.StatusUnauthorized, constant.ErrInvalidToken, nil, nil).HTTP(w)
 			return
@@ -30,7 +70,61 @@ func MiddlewareAuth(next http.Handler) http.Handler {
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), "user_id", parsedUserID)
-		next.ServeHTTP(w, r.WithContext(ctx))
+		withUser(w, r, next, parsedUserID)
 	})
 }
+
+// withUser stores the authenticated user, and the workspace selected through the
+// X-Workspace-ID header if any, in the request context. Requests authenticated
+// with a personal access token also carry its scopes as "pat_scopes", for
+// endpoints that return data of more than one resource.
+func withUser(w http.ResponseWriter, r *http.Request, next http.Handler, userID uuid.UUID) {
+	ctx := context.WithValue(r.Context(), "user_id", userID)
+
+	if header := r.Header.Get("X-Workspace-ID"); header != "" {
+		workspaceID, err := uuid.Parse(header)
+		if err != nil {
+			pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidParam, nil, nil).HTTP(w)
+			return
+		}
+		ctx = context.WithValue(ctx, "workspace_id", workspaceID)
+	}
+
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+func bearerToken(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	if len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
+
+// resourceFromPath maps "/v1/assets/123" to "assets".
+func resourceFromPath(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+// readOnlyPosts are POST endpoints that only compute from the request body
+// and change nothing, so read scope is enough for them. Rule previews are GET.
+var readOnlyPosts = map[string]bool{
+	"/v1/net-worth/forecast": true,
+}
+
+// accessFromRequest maps reads to read scope and anything else to write scope.
+func accessFromRequest(r *http.Request) string {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return token.ScopeRead
+	case http.MethodPost:
+		if readOnlyPosts[strings.TrimSuffix(r.URL.Path, "/")] {
+			return token.ScopeRead
+		}
+	}
+	return token.ScopeWrite
+}
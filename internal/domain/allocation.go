@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	AllocationWithin = "within"
+	AllocationOver   = "over"
+	AllocationUnder  = "under"
+
+	AllocationBuy  = "buy"
+	AllocationSell = "sell"
+	AllocationHold = "hold"
+)
+
+// AllocationTarget is the share of the portfolio meant for an asset category,
+// or for every category of a base type without a target of its own.
+type AllocationTarget struct {
+	ID               uuid.UUID       `db:"id" json:"id"`
+	WorkspaceID      uuid.UUID       `db:"workspace_id" json:"workspace_id"`
+	CategoryID       *uuid.UUID      `db:"category_id" json:"category_id"`
+	BaseType         *string         `db:"base_type" json:"base_type"`
+	Name             string          `db:"name" json:"name"`
+	TargetPercent    decimal.Decimal `db:"target_percent" json:"target_percent"`
+	TolerancePercent decimal.Decimal `db:"tolerance_percent" json:"tolerance_percent"` // allowed drift, in percentage points
+	CreatedAt        time.Time       `db:"created_at" json:"created_at"`
+}
+
+// SetAllocationTargets replaces all targets. They must add up to 100; an
+// empty list removes them.
+type SetAllocationTargets struct {
+	UserID  uuid.UUID
+	Targets []SetAllocationTarget `json:"targets" validate:"required,max=50,dive"`
+}
+
+type SetAllocationTarget struct {
+	CategoryID       *uuid.UUID       `json:"category_id"`
+	BaseType         string           `json:"base_type" validate:"omitempty,oneof=liquid investment physical"`
+	TargetPercent    *decimal.Decimal `json:"target_percent" validate:"required"`
+	TolerancePercent *decimal.Decimal `json:"tolerance_percent"` // defaults to 5
+}
+
+// RebalanceRequest suggests trades after investing NewMoney. With OnlyBuy
+// nothing is sold and the new money goes to the holdings furthest below target.
+type RebalanceRequest struct {
+	UserID   uuid.UUID
+	NewMoney decimal.Decimal `query:"new_money"`
+	OnlyBuy  bool            `query:"only_buy"`
+}
+
+type AllocationBucket struct {
+	CategoryID       *uuid.UUID      `json:"category_id"`
+	BaseType         *string         `json:"base_type"`
+	Name             string          `json:"name"`
+	TargetPercent    decimal.Decimal `json:"target_percent"`
+	TolerancePercent decimal.Decimal `json:"tolerance_percent"`
+	CurrentValue     decimal.Decimal `json:"current_value"`
+	CurrentPercent   decimal.Decimal `json:"current_percent"`
+	Drift            decimal.Decimal `json:"drift"` // current minus target, in percentage points
+	Status           string          `json:"status"`
+	TargetValue      decimal.Decimal `json:"target_value"` // after the new money
+	Action           string          `json:"action"`
+	Amount           decimal.Decimal `json:"amount"`
+	AfterPercent     decimal.Decimal `json:"after_percent"`
+}
+
+// AllocationExcluded is a held category no target applies to, left out of
+// the portfolio.
+type AllocationExcluded struct {
+	CategoryID uuid.UUID       `json:"category_id"`
+	Name       string          `json:"name"`
+	BaseType   string          `json:"base_type"`
+	Value      decimal.Decimal `json:"value"`
+}
+
+type Rebalance struct {
+	PortfolioValue decimal.Decimal      `json:"portfolio_value"`
+	NewMoney       decimal.Decimal      `json:"new_money"`
+	OnlyBuy        bool                 `json:"only_buy"`
+	NeedsRebalance bool                 `json:"needs_rebalance"` // some bucket drifted out of its band
+	Buckets        []AllocationBucket   `json:"buckets"`
+	Excluded       []AllocationExcluded `json:"excluded"`
+}
+
+type AllocationRepository interface {
+	ListTargets(ctx context.Context, userID uuid.UUID) (*[]AllocationTarget, error)
+	ReplaceTargets(ctx context.Context, req *SetAllocationTargets) error
+}
@@ -12,6 +12,7 @@ import (
 type Asset struct {
 	ID           uuid.UUID       `db:"id" json:"id"`
 	UserId       uuid.UUID       `db:"user_id" json:"user_id"`
+	WorkspaceID  uuid.UUID       `db:"workspace_id" json:"workspace_id"`
 	CategoryID   uuid.UUID       `db:"category_id" json:"category_id"`
 	Category     string          `db:"category" json:"category"`
 	CategoryType string          `db:"category_type" json:"category_type"`
@@ -25,6 +26,7 @@ type Asset struct {
 type AssetDB struct {
 	ID           uuid.UUID       `db:"id"`
 	UserId       uuid.UUID       `db:"user_id"`
+	WorkspaceID  uuid.UUID       `db:"workspace_id"`
 	CategoryID   uuid.UUID       `db:"category_id"`
 	Name         string          `db:"name"`
 	CurrentValue decimal.Decimal `db:"current_value"`
@@ -50,10 +52,6 @@ type ListAssetResponse struct {
 	IsActive     bool            `json:"is_active"`
 }
 
-type ListAssetCategoryResponse struct {
-	Categories *[]Category `json:"categories"`
-}
-
 type GetAssetByIDResponse struct {
 	ID           uuid.UUID       `json:"id"`
 	CategoryID   uuid.UUID       `json:"category_id"`
@@ -87,8 +85,7 @@ type UpdateAsset struct {
 }
 
 type AssetRepository interface {
-	ListAsset(ctx context.Context, req *ListAssetRequest) (*[]Asset, int, error)
-	ListCategory(ctx context.Context, userId uuid.UUID) (*[]Category, error)
+	ListAsset(ctx context.Context, req *ListAssetRequest) (*[]Asset, pkg.Page, error)
 	GetByID(ctx context.Context, id, userId uuid.UUID) (*Asset, error)
 	Delete(ctx context.Context, id, userId uuid.UUID) error
 	Insert(ctx context.Context, data *AssetDB) error
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Attachment struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	TransactionID uuid.UUID `db:"transaction_id" json:"transaction_id"`
+	UserID        uuid.UUID `db:"user_id" json:"user_id"`
+	FileName      string    `db:"file_name" json:"file_name"`
+	ContentType   string    `db:"content_type" json:"content_type"`
+	SizeBytes     int64     `db:"size_bytes" json:"size_bytes"`
+	StorageKey    string    `db:"storage_key" json:"-"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+}
+
+type UploadAttachment struct {
+	UserID        uuid.UUID
+	TransactionID uuid.UUID
+	FileName      string
+	Size          int64
+	Content       io.Reader
+}
+
+// AttachmentContent is a stored attachment opened for download. The caller
+// closes Content.
+type AttachmentContent struct {
+	Attachment
+	Content io.ReadCloser
+}
+
+// AttachmentConfig limits what can be uploaded as an attachment.
+type AttachmentConfig struct {
+	MaxSize      int64
+	AllowedTypes []string
+}
+
+// BlobStore keeps the content of attachments, addressed by a storage key.
+type BlobStore interface {
+	Put(ctx context.Context, key string, content io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type AttachmentRepository interface {
+	ListByTransaction(ctx context.Context, transactionID, userID uuid.UUID) (*[]Attachment, error)
+	GetByID(ctx context.Context, id, transactionID, userID uuid.UUID, write bool) (*Attachment, error)
+	Insert(ctx context.Context, data *Attachment) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
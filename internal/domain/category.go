@@ -1,15 +1,93 @@
 package domain
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
 
+const (
+	CategoryKindAsset       = "asset"
+	CategoryKindLiability   = "liability"
+	CategoryKindTransaction = "transaction"
+)
+
+// CategoryBaseTypes lists the base types allowed for each category kind.
+var CategoryBaseTypes = map[string][]string{
+	CategoryKindAsset:       {"liquid", "investment", "physical"},
+	CategoryKindLiability:   {"short_term", "long_term"},
+	CategoryKindTransaction: {"income", "expense"},
+}
+
+// CategoryHierarchical reports whether categories of the kind can be nested.
+func CategoryHierarchical(kind string) bool {
+	return kind == CategoryKindTransaction
+}
+
 type Category struct {
-	Id       uuid.UUID `db:"id" json:"id"`
-	UserID   uuid.UUID `db:"user_id" json:"-"`
-	Name     string    `db:"name" json:"name"`
-	BaseType string    `db:"base_type" json:"base_type"`
+	Id         uuid.UUID  `db:"id" json:"id"`
+	UserID     uuid.UUID  `db:"user_id" json:"-"`
+	ParentID   *uuid.UUID `db:"parent_id" json:"parent_id"`
+	Name       string     `db:"name" json:"name"`
+	BaseType   string     `db:"base_type" json:"base_type"`
+	IsArchived bool       `db:"is_archived" json:"is_archived"`
+	// IsZakatable is set on asset categories and IsTaxable on income categories
+	IsZakatable *bool      `db:"is_zakatable" json:"is_zakatable,omitempty"`
+	IsTaxable   *bool      `db:"is_taxable" json:"is_taxable,omitempty"`
+	Children    []Category `db:"-" json:"children,omitempty"`
+}
+
+type ListCategoryRequest struct {
+	UserID          uuid.UUID
+	Kind            string
+	BaseType        string `query:"base_type"`
+	Search          string `query:"search"`
+	IncludeArchived bool   `query:"include_archived"`
+	Flat            bool   `query:"flat"` // hierarchical kinds are returned as a tree unless set
+}
+
+type CreateCategory struct {
+	UserID   uuid.UUID
+	Kind     string
+	ParentID *uuid.UUID `json:"parent_id"`
+	Name     string     `json:"name" validate:"required,max=255"`
+	BaseType string     `json:"base_type" validate:"required"`
+}
+
+type UpdateCategory struct {
+	UserID     uuid.UUID
+	Kind       string
+	ID         uuid.UUID
+	ParentID   *uuid.UUID `json:"parent_id"`
+	Name       string     `json:"name" validate:"required,max=255"`
+	IsArchived *bool      `json:"is_archived" validate:"required"`
+	// nil keeps the current flag; is_zakatable only applies to asset and
+	// is_taxable to transaction categories
+	IsZakatable *bool `json:"is_zakatable"`
+	IsTaxable   *bool `json:"is_taxable"`
+}
+
+type MergeCategory struct {
+	UserID   uuid.UUID
+	Kind     string
+	SourceID uuid.UUID
+	TargetID uuid.UUID `json:"target_id" validate:"required"`
+}
+
+type MergeCategoryResponse struct {
+	Target    Category `json:"target"`
+	MovedRows int64    `json:"moved_rows"`
+}
+
+type CategoryRepository interface {
+	List(ctx context.Context, req *ListCategoryRequest) (*[]Category, error)
+	GetByID(ctx context.Context, kind string, id, userID uuid.UUID) (*Category, error)
+	Insert(ctx context.Context, req *CreateCategory) (*Category, error)
+	Update(ctx context.Context, req *UpdateCategory) (*Category, error)
+	Delete(ctx context.Context, kind string, id, userID uuid.UUID) error
+	IsDescendant(ctx context.Context, kind string, id, ancestorID uuid.UUID) (bool, error)
+	Merge(ctx context.Context, kind string, sourceID, targetID uuid.UUID) (int64, error)
 }
 
 type LiquidAsset struct {
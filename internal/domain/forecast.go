@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ForecastEventOneOff    = "one_off"
+	ForecastEventRecurring = "recurring"
+	ForecastEventPurchase  = "purchase"
+)
+
+// ForecastRequest projects net worth Months ahead. Rates are annual
+// percentages; anything left out falls back to the defaults of the base type.
+type ForecastRequest struct {
+	UserID              uuid.UUID
+	Months              int              `json:"months" validate:"required,min=1,max=600"`
+	IncomeGrowth        decimal.Decimal  `json:"income_growth"`
+	ExpenseInflation    decimal.Decimal  `json:"expense_inflation"`
+	Returns             []ForecastReturn `json:"returns" validate:"omitempty,max=50,dive"`
+	AmortizeLiabilities *bool            `json:"amortize_liabilities"` // defaults to true
+	Events              []ForecastEvent  `json:"events" validate:"omitempty,max=50,dive"`
+	Simulations         int              `json:"simulations" validate:"omitempty,min=100,max=5000"` // Monte Carlo runs, 0 skips them
+	Seed                *uint64          `json:"seed"`                                              // defaults to 1, so the same request gets the same percentiles
+}
+
+// ForecastReturn is the expected return of an asset category, or of every
+// category of a base type when CategoryID is empty.
+type ForecastReturn struct {
+	CategoryID   *uuid.UUID       `json:"category_id"`
+	BaseType     string           `json:"base_type" validate:"omitempty,oneof=liquid investment physical"`
+	AnnualReturn decimal.Decimal  `json:"annual_return"`
+	Volatility   *decimal.Decimal `json:"volatility"` // annual standard deviation, for Monte Carlo
+}
+
+// ForecastEvent is a what-if in month Month of the horizon. A one-off or
+// recurring event moves Amount (negative for an outflow) into savings once or
+// every month from then on. A purchase buys an asset worth Amount, financing
+// LoanAmount over LoanTenor months and paying the rest from savings.
+type ForecastEvent struct {
+	Month            int              `json:"month" validate:"required,min=1"`
+	Name             string           `json:"name" validate:"required,max=255"`
+	Type             string           `json:"type" validate:"required,oneof=one_off recurring purchase"`
+	Amount           decimal.Decimal  `json:"amount"`
+	AnnualReturn     *decimal.Decimal `json:"annual_return"` // purchase: appreciation of the asset, negative to depreciate
+	LoanAmount       decimal.Decimal  `json:"loan_amount"`
+	LoanInterestRate decimal.Decimal  `json:"loan_interest_rate"`
+	LoanTenor        int              `json:"loan_tenor" validate:"omitempty,min=1,max=600"`
+}
+
+// AssetCategoryBalance is the value of the active assets in one category.
+type AssetCategoryBalance struct {
+	CategoryID uuid.UUID       `db:"category_id"`
+	Name       string          `db:"name"`
+	BaseType   string          `db:"base_type"`
+	Value      decimal.Decimal `db:"value"`
+}
+
+// LiabilityBalance is an outstanding liability with the repayment terms kept
+// in its details, zero when not set.
+type LiabilityBalance struct {
+	ID                 uuid.UUID       `db:"id"`
+	Name               string          `db:"name"`
+	BaseType           string          `db:"base_type"`
+	RemainingBalance   decimal.Decimal `db:"remaining_balance"`
+	MonthlyInstallment decimal.Decimal `db:"monthly_installment"`
+	InterestRatePA     decimal.Decimal `db:"interest_rate_pa"`
+}
+
+type ForecastBaseline struct {
+	TotalAssets      decimal.Decimal `json:"total_assets"`
+	TotalLiabilities decimal.Decimal `json:"total_liabilities"`
+	NetWorth         decimal.Decimal `json:"net_worth"`
+	AverageIncome    decimal.Decimal `json:"average_monthly_income"`
+	AverageExpense   decimal.Decimal `json:"average_monthly_expense"`
+	HistoryFrom      string          `json:"history_from"`
+	HistoryTo        string          `json:"history_to"`
+}
+
+type ForecastPercentiles struct {
+	P10 decimal.Decimal `json:"p10"`
+	P50 decimal.Decimal `json:"p50"`
+	P90 decimal.Decimal `json:"p90"`
+}
+
+type ForecastMonth struct {
+	Month            int                  `json:"month"`
+	Date             string               `json:"date"` // last day of the month
+	Income           decimal.Decimal      `json:"income"`
+	Expense          decimal.Decimal      `json:"expense"`
+	Savings          decimal.Decimal      `json:"savings"` // accumulated since today
+	TotalAssets      decimal.Decimal      `json:"total_assets"`
+	TotalLiabilities decimal.Decimal      `json:"total_liabilities"`
+	NetWorth         decimal.Decimal      `json:"net_worth"`
+	Events           []string             `json:"events"`
+	Percentiles      *ForecastPercentiles `json:"percentiles,omitempty"` // net worth across the simulations
+}
+
+type Forecast struct {
+	Baseline    ForecastBaseline `json:"baseline"`
+	Simulations int              `json:"simulations"`
+	Seed        uint64           `json:"seed"`
+	Projection  []ForecastMonth  `json:"projection"`
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	GoalCompleted       = "completed"
+	GoalOnTrack         = "on_track"
+	GoalBehind          = "behind"
+	GoalNoContributions = "no_contributions"
+)
+
+// GoalConfig holds how far back contributions are averaged for the projection.
+type GoalConfig struct {
+	ContributionMonths int
+}
+
+// SavingsGoal is an amount to save by a target date. The current value of its
+// linked assets counts toward it.
+type SavingsGoal struct {
+	ID           uuid.UUID       `db:"id" json:"id"`
+	WorkspaceID  uuid.UUID       `db:"workspace_id" json:"workspace_id"`
+	UserID       uuid.UUID       `db:"user_id" json:"user_id"`
+	Name         string          `db:"name" json:"name"`
+	TargetAmount decimal.Decimal `db:"target_amount" json:"target_amount"`
+	TargetDate   time.Time       `db:"target_date" json:"target_date"`
+	CreatedAt    time.Time       `db:"created_at" json:"created_at"`
+	Assets       []GoalAsset     `db:"-" json:"assets"`
+	Progress     *GoalProgress   `db:"-" json:"progress"`
+}
+
+type GoalAsset struct {
+	GoalID       uuid.UUID       `db:"goal_id" json:"-"`
+	AssetID      uuid.UUID       `db:"asset_id" json:"asset_id"`
+	Name         string          `db:"name" json:"name"`
+	CurrentValue decimal.Decimal `db:"current_value" json:"current_value"`
+}
+
+// GoalContribution is the net amount moved into the linked assets of a goal
+// between two dates. FirstMonth is the first month with any contribution.
+type GoalContribution struct {
+	GoalID     uuid.UUID       `db:"goal_id"`
+	Amount     decimal.Decimal `db:"amount"`
+	FirstMonth time.Time       `db:"first_month"`
+}
+
+type GoalProgress struct {
+	Status                  string          `json:"status"`
+	CurrentAmount           decimal.Decimal `json:"current_amount"`
+	RemainingAmount         decimal.Decimal `json:"remaining_amount"`
+	ProgressPercent         decimal.Decimal `json:"progress_percent"`
+	MonthsLeft              int             `json:"months_left"`
+	RequiredMonthly         decimal.Decimal `json:"required_monthly_contribution"`
+	AverageMonthly          decimal.Decimal `json:"average_monthly_contribution"`
+	ContributionFrom        string          `json:"contribution_from"`
+	ContributionTo          string          `json:"contribution_to"`
+	ProjectedCompletionDate *string         `json:"projected_completion_date"` // nil while nothing is being saved
+}
+
+type CreateGoal struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	Name         string           `json:"name" validate:"required,max=255"`
+	TargetAmount *decimal.Decimal `json:"target_amount" validate:"required"`
+	TargetDate   string           `json:"target_date" validate:"required"`
+	AssetIDs     []uuid.UUID      `json:"asset_ids" validate:"required,min=1,max=20"`
+}
+
+type GoalRepository interface {
+	List(ctx context.Context, userID uuid.UUID) (*[]SavingsGoal, error)
+	GetByID(ctx context.Context, id, userID uuid.UUID, write bool) (*SavingsGoal, error)
+	ListAssets(ctx context.Context, goalIDs []uuid.UUID) (*[]GoalAsset, error)
+	ListContributions(ctx context.Context, goalIDs []uuid.UUID, from, to time.Time) (*[]GoalContribution, error)
+	Insert(ctx context.Context, req *CreateGoal, targetDate time.Time) (uuid.UUID, error)
+	Update(ctx context.Context, req *CreateGoal, targetDate time.Time) error
+	SetAssets(ctx context.Context, goalID uuid.UUID, assetIDs []uuid.UUID) error
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+}
@@ -0,0 +1,11 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+)
+
+type GoldPriceProvider interface {
+	FetchPricePerGram(ctx context.Context) (decimal.Decimal, error)
+}
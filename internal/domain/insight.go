@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	InsightCategorySpike = "category_spike"
+	InsightLargeExpense  = "large_expense"
+	InsightAssetDrop     = "asset_drop"
+)
+
+// InsightConfig holds the thresholds of the anomaly detection.
+type InsightConfig struct {
+	// a category spikes when its spend this month is SpikeStdDev standard
+	// deviations above its average over the TrailingMonths before, given at
+	// least MinHistoryMonths of those had spend
+	TrailingMonths   int
+	MinHistoryMonths int
+	SpikeStdDev      float64
+
+	// an expense of the last LargeExpenseDays is large when it is
+	// LargeExpenseStdDev standard deviations above the expenses of the
+	// ExpenseLookbackDays before, given at least MinHistoryExpenses of them,
+	// and one-off when nothing in its category came close in that time
+	ExpenseLookbackDays int
+	MinHistoryExpenses  int
+	LargeExpenseStdDev  float64
+	LargeExpenseDays    int
+
+	// a liquid asset drops when it lost AssetDropPercent of its value within
+	// the last AssetDropDays
+	AssetDropDays    int
+	AssetDropPercent float64
+}
+
+// Insight is a precomputed observation about unusual activity. Amount is the
+// unusual value and Baseline what it is compared with; Score is the number of
+// standard deviations above the baseline, or the drop in percent for an asset.
+type Insight struct {
+	ID          uuid.UUID       `db:"id" json:"id"`
+	WorkspaceID uuid.UUID       `db:"workspace_id" json:"-"`
+	Type        string          `db:"type" json:"type"`
+	SubjectID   *uuid.UUID      `db:"subject_id" json:"subject_id"`
+	SubjectName string          `db:"subject_name" json:"subject_name"`
+	Message     string          `db:"message" json:"message"`
+	Amount      decimal.Decimal `db:"amount" json:"amount"`
+	Baseline    decimal.Decimal `db:"baseline" json:"baseline"`
+	Score       decimal.Decimal `db:"score" json:"score"`
+	InsightDate time.Time       `db:"insight_date" json:"insight_date"`
+	CreatedAt   time.Time       `db:"created_at" json:"generated_at"`
+}
+
+// CategoryMonthSpend is the expense booked on a category in one month.
+type CategoryMonthSpend struct {
+	CategoryID   uuid.UUID       `db:"category_id"`
+	CategoryName string          `db:"category_name"`
+	Month        time.Time       `db:"month"`
+	Amount       decimal.Decimal `db:"amount"`
+}
+
+type ExpenseEntry struct {
+	ID              uuid.UUID       `db:"id"`
+	CategoryID      uuid.UUID       `db:"category_id"`
+	CategoryName    string          `db:"category_name"`
+	Amount          decimal.Decimal `db:"amount"`
+	TransactionDate time.Time       `db:"transaction_date"`
+	Notes           *string         `db:"notes"`
+}
+
+// AssetChange is the net effect of the transactions booked on a liquid asset
+// since a given date.
+type AssetChange struct {
+	AssetID      uuid.UUID       `db:"asset_id"`
+	Name         string          `db:"name"`
+	CurrentValue decimal.Decimal `db:"current_value"`
+	Change       decimal.Decimal `db:"change"`
+}
+
+type InsightRepository interface {
+	ListWorkspaceIDs(ctx context.Context) ([]uuid.UUID, error)
+	ListCategoryMonthSpend(ctx context.Context, workspaceID uuid.UUID, from time.Time) (*[]CategoryMonthSpend, error)
+	ListExpenses(ctx context.Context, workspaceID uuid.UUID, from time.Time) (*[]ExpenseEntry, error)
+	ListLiquidAssetChanges(ctx context.Context, workspaceID uuid.UUID, since time.Time) (*[]AssetChange, error)
+	Replace(ctx context.Context, workspaceID uuid.UUID, insights []Insight) error
+	List(ctx context.Context, userID uuid.UUID) (*[]Insight, error)
+}
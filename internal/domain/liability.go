@@ -12,6 +12,7 @@ import (
 type LiabilityDB struct {
 	ID               uuid.UUID       `db:"id"`
 	UserId           uuid.UUID       `db:"user_id"`
+	WorkspaceID      uuid.UUID       `db:"workspace_id"`
 	CategoryID       uuid.UUID       `db:"category_id"`
 	Name             string          `db:"name"`
 	PrincipalAmount  decimal.Decimal `db:"principal_amount"`
@@ -24,6 +25,7 @@ type LiabilityDB struct {
 type Liability struct {
 	ID               uuid.UUID       `db:"id" json:"id"`
 	UserId           uuid.UUID       `db:"user_id" json:"user_id"`
+	WorkspaceID      uuid.UUID       `db:"workspace_id" json:"workspace_id"`
 	CategoryID       uuid.UUID       `db:"category_id" json:"category_id"`
 	Category         string          `db:"category" json:"category"`
 	Name             string          `db:"name" json:"name"`
@@ -72,8 +74,7 @@ type GetLiabilityByIDResponse struct {
 }
 
 type LiabilityRepository interface {
-	ListCategory(ctx context.Context, userId uuid.UUID) (*[]Category, error)
-	List(ctx context.Context, req *ListLiabilityRequest) (*[]Liability, int, error)
+	List(ctx context.Context, req *ListLiabilityRequest) (*[]Liability, pkg.Page, error)
 	GetByID(ctx context.Context, id, userId uuid.UUID) (*Liability, error)
 	Delete(ctx context.Context, id, userId uuid.UUID) error
 	Insert(ctx context.Context, data *LiabilityDB) error
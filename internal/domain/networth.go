@@ -18,8 +18,56 @@ type Networth struct {
 	GrowthPercentage decimal.Decimal `db:"growth_percentage" json:"growth_percentage"`
 }
 
+// HealthInputs is what the financial health ratios are computed from: the
+// current balances and the income and expense of a window of full months.
+type HealthInputs struct {
+	LiquidAssets         decimal.Decimal `db:"liquid_assets" json:"liquid_assets"`
+	InvestmentAssets     decimal.Decimal `db:"investment_assets" json:"investment_assets"`
+	PhysicalAssets       decimal.Decimal `db:"physical_assets" json:"physical_assets"`
+	ShortTermLiabilities decimal.Decimal `db:"short_term_liabilities" json:"short_term_liabilities"`
+	LongTermLiabilities  decimal.Decimal `db:"long_term_liabilities" json:"long_term_liabilities"`
+	MonthlyInstallments  decimal.Decimal `db:"monthly_installments" json:"monthly_installments"`
+	Income               decimal.Decimal `db:"income" json:"-"`
+	Expense              decimal.Decimal `db:"expense" json:"-"`
+	ActiveMonths         int             `db:"active_months" json:"active_months"` // months of the window with any transaction
+	AverageIncome        decimal.Decimal `db:"-" json:"average_monthly_income"`
+	AverageExpense       decimal.Decimal `db:"-" json:"average_monthly_expense"`
+}
+
+const (
+	HealthGood        = "good"
+	HealthFair        = "fair"
+	HealthPoor        = "poor"
+	HealthUnavailable = "unavailable"
+)
+
+// HealthRatio is one personal finance ratio. Value and Score are nil when the
+// history doesn't allow computing it, e.g. a savings rate without income.
+type HealthRatio struct {
+	Key         string           `json:"key"`
+	Name        string           `json:"name"`
+	Value       *decimal.Decimal `json:"value"`
+	Unit        string           `json:"unit"`  // "months" or "percent"
+	Score       *decimal.Decimal `json:"score"` // 0 to 100
+	Status      string           `json:"status"`
+	Explanation string           `json:"explanation"`
+}
+
+// FinancialHealth scores the ratios that could be computed and averages them
+// into a composite score from 0 to 100.
+type FinancialHealth struct {
+	Score  decimal.Decimal `json:"score"`
+	Status string          `json:"status"`
+	From   string          `json:"from"`
+	To     string          `json:"to"`
+	Inputs HealthInputs    `json:"inputs"`
+	Ratios []HealthRatio   `json:"ratios"`
+}
+
 type NetworthRepository interface {
 	Calculate(ctx context.Context) error
 	GetCurrent(ctx context.Context, userId uuid.UUID) (*Networth, error)
+	GetHealthInputs(ctx context.Context, userID uuid.UUID, from, to time.Time) (*HealthInputs, error)
+	ListAssetCategoryBalances(ctx context.Context, userID uuid.UUID) (*[]AssetCategoryBalance, error)
+	ListLiabilityBalances(ctx context.Context, userID uuid.UUID) (*[]LiabilityBalance, error)
 }
-
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OIDCClaims are the ID token claims the login flow relies on.
+type OIDCClaims struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+	Nonce             string
+}
+
+type OIDCLoginState struct {
+	State        string    `db:"state"`
+	Provider     string    `db:"provider"`
+	Nonce        string    `db:"nonce"`
+	CodeVerifier string    `db:"code_verifier"`
+	ExpiresAt    time.Time `db:"expires_at"`
+}
+
+type UserIdentity struct {
+	UserID   uuid.UUID `db:"user_id"`
+	Provider string    `db:"provider"`
+	Subject  string    `db:"subject"`
+	Email    string    `db:"email"`
+}
+
+type OIDCCallbackRequest struct {
+	Provider   string
+	Code       string
+	State      string
+	RemoteAddr string
+}
+
+type OIDCProvider interface {
+	// AuthCodeURL builds the authorization endpoint URL for the authorization code + PKCE flow.
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange redeems the code and returns the claims of the verified ID token.
+	Exchange(ctx context.Context, code, codeVerifier string) (*OIDCClaims, error)
+}
+
+type OIDCRepository interface {
+	InsertState(ctx context.Context, data *OIDCLoginState) error
+	ConsumeState(ctx context.Context, state string) (*OIDCLoginState, error)
+	GetIdentity(ctx context.Context, provider, subject string) (*UserIdentity, error)
+	InsertIdentity(ctx context.Context, data *UserIdentity) error
+}
@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	CashflowGroupDay   = "day"
+	CashflowGroupWeek  = "week"
+	CashflowGroupMonth = "month"
+)
+
+// CashflowRequest takes every transaction list filter on top of its own
+// window, which replaces filter_type.
+type CashflowRequest struct {
+	ListTransactionRequest
+	From    string `query:"from"`     // YYYY-MM-DD
+	To      string `query:"to"`       // YYYY-MM-DD, inclusive
+	GroupBy string `query:"group_by"` // "day", "week", "month"
+	By      string `query:"by"`       // "category" adds a per category breakdown
+}
+
+// CashflowRow is one cell of the period x category matrix. Without a
+// category breakdown there is one row per period and base type.
+type CashflowRow struct {
+	Period       time.Time       `db:"period"`
+	CategoryID   *uuid.UUID      `db:"category_id"`
+	CategoryName *string         `db:"category_name"`
+	BaseType     *string         `db:"base_type"`
+	Amount       decimal.Decimal `db:"amount"`
+}
+
+type CashflowCategory struct {
+	CategoryID uuid.UUID       `json:"category_id"`
+	Name       string          `json:"name"`
+	BaseType   string          `json:"base_type"`
+	Amount     decimal.Decimal `json:"amount"`
+}
+
+// CashflowPeriod totals one period. SavingsRate is the share of income left
+// after expenses, in percent, and 0 for a period without income.
+type CashflowPeriod struct {
+	Period      string             `json:"period"` // first day of the period
+	Income      decimal.Decimal    `json:"income"`
+	Expense     decimal.Decimal    `json:"expense"`
+	Net         decimal.Decimal    `json:"net"`
+	SavingsRate decimal.Decimal    `json:"savings_rate"`
+	Categories  []CashflowCategory `json:"categories,omitempty"`
+}
+
+type CashflowReport struct {
+	From    string           `json:"from"`
+	To      string           `json:"to"`
+	GroupBy string           `json:"group_by"`
+	Periods []CashflowPeriod `json:"periods"`
+}
+
+const (
+	StatementPeriodMonth = "month"
+	StatementPeriodYear  = "year"
+
+	StatementFormatJSON = "json"
+	StatementFormatPDF  = "pdf"
+)
+
+type StatementRequest struct {
+	UserID uuid.UUID
+	Period string `query:"period"` // "month" or "year", income statement only
+	Date   string `query:"date"`   // YYYY-MM-DD, a day of the period or the balance sheet date, defaults to today
+	Format string `query:"format"` // "json" or "pdf"
+}
+
+// StatementRow is a category total of the income statement, or a single asset
+// or liability of the balance sheet with Kind set.
+type StatementRow struct {
+	Kind       string          `db:"kind"`
+	BaseType   string          `db:"base_type"`
+	CategoryID uuid.UUID       `db:"category_id"`
+	Category   string          `db:"category"`
+	Name       string          `db:"name"`
+	Amount     decimal.Decimal `db:"amount"`
+}
+
+type StatementLine struct {
+	Name     string          `json:"name"`
+	Category string          `json:"category,omitempty"`
+	Amount   decimal.Decimal `json:"amount"`
+}
+
+type StatementSection struct {
+	BaseType string          `json:"base_type"`
+	Name     string          `json:"name"`
+	Lines    []StatementLine `json:"lines"`
+	Total    decimal.Decimal `json:"total"`
+}
+
+type IncomeStatement struct {
+	From      string           `json:"from"`
+	To        string           `json:"to"`
+	Income    StatementSection `json:"income"`
+	Expense   StatementSection `json:"expense"`
+	NetIncome decimal.Decimal  `json:"net_income"`
+}
+
+// BalanceSheet groups assets by asset_base_type and liabilities by
+// liability_base_type, one section per type.
+type BalanceSheet struct {
+	Date             string             `json:"date"`
+	Assets           []StatementSection `json:"assets"`
+	Liabilities      []StatementSection `json:"liabilities"`
+	TotalAssets      decimal.Decimal    `json:"total_assets"`
+	TotalLiabilities decimal.Decimal    `json:"total_liabilities"`
+	NetWorth         decimal.Decimal    `json:"net_worth"`
+}
+
+const (
+	ComparePeriodMonth   = "month"
+	ComparePeriodQuarter = "quarter"
+	ComparePeriodYear    = "year"
+	ComparePeriodCustom  = "custom"
+
+	CompareWithPrevious = "previous"
+	CompareWithYearAgo  = "year_ago"
+)
+
+// ComparisonRequest picks the current period from Date, or From and To for a
+// custom one, and the period it is compared with from CompareDate, or
+// CompareFrom and CompareTo. Without those it is the period before, or the
+// same period a year earlier with Compare set to "year_ago".
+type ComparisonRequest struct {
+	UserID      uuid.UUID
+	Period      string `query:"period"`       // "month", "quarter", "year" or "custom", defaults to month
+	Date        string `query:"date"`         // YYYY-MM-DD, defaults to today
+	From        string `query:"from"`         // YYYY-MM-DD, custom only
+	To          string `query:"to"`           // YYYY-MM-DD, inclusive, custom only
+	Compare     string `query:"compare"`      // "previous" or "year_ago"
+	CompareDate string `query:"compare_date"` // YYYY-MM-DD
+	CompareFrom string `query:"compare_from"` // YYYY-MM-DD, custom only
+	CompareTo   string `query:"compare_to"`   // YYYY-MM-DD, inclusive, custom only
+}
+
+type ComparisonPeriod struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ComparisonValue is a figure of both periods. ChangePercent is relative to
+// the previous value and nil when that is zero.
+type ComparisonValue struct {
+	Current       decimal.Decimal  `json:"current"`
+	Previous      decimal.Decimal  `json:"previous"`
+	Change        decimal.Decimal  `json:"change"`
+	ChangePercent *decimal.Decimal `json:"change_percent"`
+}
+
+type ComparisonLine struct {
+	CategoryID uuid.UUID `json:"category_id"`
+	Name       string    `json:"name"`
+	ComparisonValue
+}
+
+// Comparison sets the cash flow of two periods and the balances at their ends
+// side by side. Categories holds the spend per expense category.
+type Comparison struct {
+	Current          ComparisonPeriod `json:"current"`
+	Previous         ComparisonPeriod `json:"previous"`
+	Income           ComparisonValue  `json:"income"`
+	Expense          ComparisonValue  `json:"expense"`
+	Net              ComparisonValue  `json:"net"`
+	TotalAssets      ComparisonValue  `json:"total_assets"`
+	TotalLiabilities ComparisonValue  `json:"total_liabilities"`
+	NetWorth         ComparisonValue  `json:"net_worth"`
+	Categories       []ComparisonLine `json:"categories"`
+	AssetCategories  []ComparisonLine `json:"asset_categories"`
+}
+
+// ReportFile is a rendered report, returned in place of JSON data.
+type ReportFile struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+type ReportRepository interface {
+	GetCashflow(ctx context.Context, req *CashflowRequest) (*[]CashflowRow, error)
+	GetIncomeStatement(ctx context.Context, req *ListTransactionRequest) (*[]StatementRow, error)
+	GetBalanceSheet(ctx context.Context, userID uuid.UUID, date time.Time) (*[]StatementRow, error)
+}
+
+type StatementRenderer interface {
+	IncomeStatement(w io.Writer, statement *IncomeStatement) error
+	BalanceSheet(w io.Writer, sheet *BalanceSheet) error
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	RuleMatchContains = "contains"
+	RuleMatchRegex    = "regex"
+)
+
+// CategorizationRule assigns a category, asset or tags to transactions matching
+// all of its conditions. Rules run in ascending priority.
+type CategorizationRule struct {
+	ID               uuid.UUID        `db:"id" json:"id"`
+	WorkspaceID      uuid.UUID        `db:"workspace_id" json:"workspace_id"`
+	UserID           uuid.UUID        `db:"user_id" json:"user_id"`
+	Name             string           `db:"name" json:"name"`
+	Priority         int              `db:"priority" json:"priority"`
+	IsActive         bool             `db:"is_active" json:"is_active"`
+	NotesPattern     *string          `db:"notes_pattern" json:"notes_pattern"`
+	NotesMatch       string           `db:"notes_match" json:"notes_match"`
+	MinAmount        *decimal.Decimal `db:"min_amount" json:"min_amount"`
+	MaxAmount        *decimal.Decimal `db:"max_amount" json:"max_amount"`
+	ConditionAssetID *uuid.UUID       `db:"condition_asset_id" json:"condition_asset_id"`
+	SetCategoryID    *uuid.UUID       `db:"set_category_id" json:"set_category_id"`
+	SetCategoryType  *string          `db:"set_category_type" json:"set_category_type"` // base type of SetCategoryID
+	SetAssetID       *uuid.UUID       `db:"set_asset_id" json:"set_asset_id"`
+	SetTags          pq.StringArray   `db:"set_tags" json:"set_tags"`
+	CreatedAt        time.Time        `db:"created_at" json:"created_at"`
+}
+
+type CreateRule struct {
+	ID               uuid.UUID
+	UserID           uuid.UUID
+	Name             string           `json:"name" validate:"required,max=255"`
+	Priority         int              `json:"priority"`
+	IsActive         *bool            `json:"is_active" validate:"required"`
+	NotesPattern     *string          `json:"notes_pattern" validate:"omitempty,max=255"`
+	NotesMatch       string           `json:"notes_match" validate:"omitempty,oneof=contains regex"`
+	MinAmount        *decimal.Decimal `json:"min_amount"`
+	MaxAmount        *decimal.Decimal `json:"max_amount"`
+	ConditionAssetID *uuid.UUID       `json:"condition_asset_id"`
+	SetCategoryID    *uuid.UUID       `json:"set_category_id"`
+	SetAssetID       *uuid.UUID       `json:"set_asset_id"`
+	SetTags          []string         `json:"set_tags" validate:"omitempty,max=20,dive,max=50"`
+}
+
+// RuleOutcome is what the matching rules assign to a transaction.
+// CategoryOwnerID is the owner of CategoryID, the creator of the rule.
+type RuleOutcome struct {
+	CategoryID      *uuid.UUID
+	CategoryOwnerID uuid.UUID
+	AssetID         *uuid.UUID
+	Tags            []string
+}
+
+type RulePreview struct {
+	TransactionID   uuid.UUID       `json:"transaction_id"`
+	TransactionDate time.Time       `json:"transaction_date"`
+	Notes           *string         `json:"notes"`
+	Amount          decimal.Decimal `json:"amount"`
+	CategoryID      uuid.UUID       `json:"category_id"`
+	NewCategoryID   uuid.UUID       `json:"new_category_id"`
+	AssetID         *uuid.UUID      `json:"asset_id"`
+	NewAssetID      *uuid.UUID      `json:"new_asset_id"`
+	Tags            []string        `json:"tags"`
+	NewTags         []string        `json:"new_tags"`
+}
+
+type RuleRepository interface {
+	List(ctx context.Context, userID uuid.UUID) (*[]CategorizationRule, error)
+	ListActive(ctx context.Context, userID uuid.UUID) (*[]CategorizationRule, error)
+	GetByID(ctx context.Context, id, userID uuid.UUID, write bool) (*CategorizationRule, error)
+	Insert(ctx context.Context, req *CreateRule) (uuid.UUID, error)
+	Update(ctx context.Context, req *CreateRule) error
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+}
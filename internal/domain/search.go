@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	SearchTypeTransaction = "transaction"
+	SearchTypeCategory    = "category"
+	SearchTypeAsset       = "asset"
+	SearchTypeLiability   = "liability"
+)
+
+var SearchTypes = []string{SearchTypeTransaction, SearchTypeCategory, SearchTypeAsset, SearchTypeLiability}
+
+// SearchTypeResources maps each result type to the token resource whose read
+// scope it needs. Categories go with transactions, which they mostly label.
+var SearchTypeResources = map[string]string{
+	SearchTypeTransaction: "transactions",
+	SearchTypeCategory:    "transactions",
+	SearchTypeAsset:       "assets",
+	SearchTypeLiability:   "liabilities",
+}
+
+type SearchRequest struct {
+	UserID uuid.UUID
+	Query  string `query:"q"`
+	Types  string `query:"types"` // comma separated, all types when empty
+	Limit  int    `query:"limit"`
+}
+
+// TypeList returns the requested result types, defaulting to all of them.
+func (r *SearchRequest) TypeList() []string {
+	if strings.TrimSpace(r.Types) == "" {
+		return SearchTypes
+	}
+
+	types := make([]string, 0, len(SearchTypes))
+	for _, t := range strings.Split(r.Types, ",") {
+		types = append(types, strings.ToLower(strings.TrimSpace(t)))
+	}
+	return types
+}
+
+// SearchResult is a single ranked hit. Highlight is HTML-escaped text with the
+// matched words wrapped in <mark> tags.
+type SearchResult struct {
+	Type      string           `db:"type" json:"type"`
+	ID        uuid.UUID        `db:"id" json:"id"`
+	Title     string           `db:"title" json:"title"`
+	Highlight string           `db:"highlight" json:"highlight"`
+	Rank      float64          `db:"rank" json:"rank"`
+	Amount    *decimal.Decimal `db:"amount" json:"amount,omitempty"`
+	Date      *time.Time       `db:"date" json:"date,omitempty"`
+}
+
+type SearchRepository interface {
+	Search(ctx context.Context, req *SearchRequest, tsQuery string) (*[]SearchResult, error)
+}
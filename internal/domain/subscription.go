@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	SubscriptionConfirmed = "confirmed"
+	SubscriptionDismissed = "dismissed"
+
+	CadenceWeekly    = "weekly"
+	CadenceBiweekly  = "biweekly"
+	CadenceMonthly   = "monthly"
+	CadenceQuarterly = "quarterly"
+	CadenceYearly    = "yearly"
+)
+
+// SubscriptionConfig holds the thresholds of the subscription detection.
+type SubscriptionConfig struct {
+	// expenses of the last LookbackDays are scanned; a pattern needs at least
+	// MinCharges of them within AmountTolerance percent of their median
+	LookbackDays    int
+	MinCharges      int
+	AmountTolerance float64
+}
+
+// SubscriptionCharge is an expense line a subscription may be charged as.
+type SubscriptionCharge struct {
+	CategoryID      uuid.UUID       `db:"category_id"`
+	CategoryName    string          `db:"category_name"`
+	Amount          decimal.Decimal `db:"amount"`
+	TransactionDate time.Time       `db:"transaction_date"`
+	Notes           *string         `db:"notes"`
+}
+
+// DetectedSubscription is a recurring pattern of expenses on one category
+// with the same normalized notes, Pattern, charged at a regular cadence.
+type DetectedSubscription struct {
+	CategoryID    uuid.UUID       `json:"category_id"`
+	CategoryName  string          `json:"category_name"`
+	Pattern       string          `json:"pattern"`
+	Name          string          `json:"name"`
+	Cadence       string          `json:"cadence"`
+	Charges       int             `json:"charges"`
+	AverageAmount decimal.Decimal `json:"average_amount"`
+	LastAmount    decimal.Decimal `json:"last_amount"`
+	FirstCharge   string          `json:"first_charge"`
+	LastCharge    string          `json:"last_charge"`
+	NextCharge    string          `json:"next_charge"`
+	AnnualCost    decimal.Decimal `json:"annual_cost"`
+}
+
+// Subscription is a detection the user confirmed, tracked as a recurring
+// item, or dismissed.
+type Subscription struct {
+	ID             uuid.UUID       `db:"id" json:"id"`
+	WorkspaceID    uuid.UUID       `db:"workspace_id" json:"workspace_id"`
+	CategoryID     uuid.UUID       `db:"category_id" json:"category_id"`
+	CategoryName   string          `db:"category_name" json:"category_name"`
+	Pattern        string          `db:"pattern" json:"pattern"`
+	Status         string          `db:"status" json:"status"`
+	Name           string          `db:"name" json:"name"`
+	Cadence        string          `db:"cadence" json:"cadence"`
+	Amount         decimal.Decimal `db:"amount" json:"amount"`
+	LastChargeDate time.Time       `db:"last_charge_date" json:"last_charge_date"`
+	NextCharge     string          `db:"-" json:"next_charge,omitempty"`
+	AnnualCost     decimal.Decimal `db:"-" json:"annual_cost"`
+	CreatedAt      time.Time       `db:"created_at" json:"created_at"`
+}
+
+// DecideSubscription confirms or dismisses the detection of CategoryID and
+// Pattern. Name renames a confirmed subscription.
+type DecideSubscription struct {
+	UserID     uuid.UUID
+	Status     string
+	CategoryID *uuid.UUID `json:"category_id" validate:"required"`
+	Pattern    string     `json:"pattern" validate:"max=255"`
+	Name       string     `json:"name" validate:"max=255"`
+}
+
+type SubscriptionRepository interface {
+	ListCharges(ctx context.Context, userID uuid.UUID, from time.Time) (*[]SubscriptionCharge, error)
+	List(ctx context.Context, userID uuid.UUID, status string) (*[]Subscription, error)
+	Save(ctx context.Context, req *DecideSubscription, detected *DetectedSubscription) (*Subscription, error)
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+}
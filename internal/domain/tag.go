@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	TagMatchAny = "any"
+	TagMatchAll = "all"
+)
+
+type Tag struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	WorkspaceID      uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Name             string    `db:"name" json:"name"`
+	TransactionCount int       `db:"transaction_count" json:"transaction_count"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+}
+
+type UpdateTag struct {
+	UserID uuid.UUID
+	ID     uuid.UUID
+	Name   string `json:"name" validate:"required,max=50"`
+}
+
+type MergeTag struct {
+	UserID   uuid.UUID
+	SourceID uuid.UUID
+	TargetID uuid.UUID `json:"target_id" validate:"required"`
+}
+
+type TagSummary struct {
+	ID               uuid.UUID       `db:"id" json:"id"`
+	Name             string          `db:"name" json:"name"`
+	TransactionCount int             `db:"transaction_count" json:"transaction_count"`
+	Income           decimal.Decimal `db:"income" json:"income"`
+	Expense          decimal.Decimal `db:"expense" json:"expense"`
+	Net              decimal.Decimal `db:"net" json:"net"`
+}
+
+// NormalizeTagNames lowercases and trims tag names, dropping blanks and duplicates.
+func NormalizeTagNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized
+}
+
+type TagRepository interface {
+	List(ctx context.Context, userID uuid.UUID) (*[]Tag, error)
+	GetByID(ctx context.Context, id, userID uuid.UUID, write bool) (*Tag, error)
+	Upsert(ctx context.Context, workspaceID uuid.UUID, names []string) ([]uuid.UUID, error)
+	SetTransactionTags(ctx context.Context, transactionID uuid.UUID, tagIDs []uuid.UUID) error
+	Rename(ctx context.Context, id uuid.UUID, name string) error
+	Merge(ctx context.Context, sourceID, targetID uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
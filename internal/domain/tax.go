@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// TaxEstimateRequest estimates the yearly personal income tax. Year defaults
+// to the current one and Status, the PTKP status such as TK/0 or K/1, to TK/0.
+// Employee deducts the occupational cost from gross income.
+type TaxEstimateRequest struct {
+	UserID   uuid.UUID
+	Year     int    `query:"year"`
+	Status   string `query:"status"`
+	Employee bool   `query:"employee"`
+}
+
+// TaxYear is a version of the tax rules, in force from its year until a newer
+// version.
+type TaxYear struct {
+	Year                 int             `db:"year"`
+	OccupationalCostRate decimal.Decimal `db:"occupational_cost_rate"`
+	OccupationalCostCap  decimal.Decimal `db:"occupational_cost_cap"`
+	Notes                *string         `db:"notes"`
+}
+
+type TaxBracket struct {
+	LowerBound decimal.Decimal `db:"lower_bound"`
+	Rate       decimal.Decimal `db:"rate"`
+}
+
+// TaxIncome is the income booked on a category over the year.
+type TaxIncome struct {
+	CategoryID uuid.UUID       `db:"category_id" json:"category_id"`
+	Name       string          `db:"name" json:"name"`
+	Amount     decimal.Decimal `db:"amount" json:"amount"`
+	IsTaxable  bool            `db:"is_taxable" json:"is_taxable"`
+}
+
+type TaxBracketLine struct {
+	LowerBound decimal.Decimal  `json:"lower_bound"`
+	UpperBound *decimal.Decimal `json:"upper_bound"` // nil for the top bracket
+	Rate       decimal.Decimal  `json:"rate"`
+	Taxable    decimal.Decimal  `json:"taxable"`
+	Tax        decimal.Decimal  `json:"tax"`
+}
+
+type TaxEstimate struct {
+	Year             int              `json:"year"`
+	RulesYear        int              `json:"rules_year"` // version of the rules applied
+	Status           string           `json:"status"`
+	Employee         bool             `json:"employee"`
+	GrossIncome      decimal.Decimal  `json:"gross_income"` // taxable categories only
+	NonTaxableIncome decimal.Decimal  `json:"non_taxable_income"`
+	OccupationalCost decimal.Decimal  `json:"occupational_cost"`
+	NetIncome        decimal.Decimal  `json:"net_income"`
+	PTKP             decimal.Decimal  `json:"ptkp"`
+	TaxableIncome    decimal.Decimal  `json:"taxable_income"` // PKP, rounded down to the thousand
+	Brackets         []TaxBracketLine `json:"brackets"`
+	Tax              decimal.Decimal  `json:"tax"`
+	EffectiveRate    decimal.Decimal  `json:"effective_rate"` // tax as a percentage of gross income
+	Incomes          []TaxIncome      `json:"incomes"`
+}
+
+type TaxRepository interface {
+	GetYear(ctx context.Context, year int) (*TaxYear, error)
+	GetPTKP(ctx context.Context, year int, status string) (decimal.Decimal, error)
+	ListBrackets(ctx context.Context, year int) (*[]TaxBracket, error)
+	ListIncome(ctx context.Context, userID uuid.UUID, from, to time.Time) (*[]TaxIncome, error)
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type PersonalAccessToken struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	UserID      uuid.UUID      `db:"user_id" json:"-"`
+	Name        string         `db:"name" json:"name"`
+	TokenHash   string         `db:"token_hash" json:"-"`
+	TokenPrefix string         `db:"token_prefix" json:"token_prefix"`
+	Scopes      pq.StringArray `db:"scopes" json:"scopes"`
+	ExpiresAt   *time.Time     `db:"expires_at" json:"expires_at"`
+	LastUsedAt  *time.Time     `db:"last_used_at" json:"last_used_at"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+}
+
+type CreatePersonalAccessToken struct {
+	UserID    uuid.UUID
+	Name      string   `json:"name" validate:"required,max=255"`
+	Scopes    []string `json:"scopes" validate:"required,min=1"`
+	ExpiresAt string   `json:"expires_at" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type PersonalAccessTokenRepository interface {
+	List(ctx context.Context, userID uuid.UUID) (*[]PersonalAccessToken, error)
+	Insert(ctx context.Context, data *PersonalAccessToken) (uuid.UUID, error)
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+	Touch(ctx context.Context, tokenHash string) (*PersonalAccessToken, error)
+}
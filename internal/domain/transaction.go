@@ -2,16 +2,19 @@ package domain
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/fazriegi/netbase-be/pkg"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/shopspring/decimal"
 )
 
 type TransactionDB struct {
 	ID              uuid.UUID       `db:"id"`
 	UserID          uuid.UUID       `db:"user_id"`
+	WorkspaceID     uuid.UUID       `db:"workspace_id"`
 	AssetID         *uuid.UUID      `db:"asset_id"`
 	LiabilityID     *uuid.UUID      `db:"liability_id"`
 	CategoryID      uuid.UUID       `db:"category_id"`
@@ -23,30 +26,94 @@ type TransactionDB struct {
 }
 
 type Transaction struct {
-	ID              uuid.UUID       `db:"id" json:"id"`
-	UserID          uuid.UUID       `db:"user_id" json:"user_id"`
-	AssetID         *uuid.UUID      `db:"asset_id" json:"asset_id"`
-	AssetName       *string         `db:"asset_name" json:"asset_name"`
-	LiabilityID     *uuid.UUID      `db:"liability_id" json:"liability_id"`
-	LiabilityName   *string         `db:"liability_name" json:"liability_name"`
-	CategoryID      uuid.UUID       `db:"category_id" json:"category_id"`
-	CategoryName    string          `db:"category_name" json:"category_name"`
-	CategoryType    string          `db:"category_type" json:"category_type"`
-	Amount          decimal.Decimal `db:"amount" json:"amount"`
-	TransactionDate time.Time       `db:"transaction_date" json:"transaction_date"`
-	Notes           *string         `db:"notes" json:"notes"`
-	CreatedAt       time.Time       `db:"created_at" json:"-"`
+	ID              uuid.UUID          `db:"id" json:"id"`
+	UserID          uuid.UUID          `db:"user_id" json:"user_id"`
+	WorkspaceID     uuid.UUID          `db:"workspace_id" json:"workspace_id"`
+	AssetID         *uuid.UUID         `db:"asset_id" json:"asset_id"`
+	AssetName       *string            `db:"asset_name" json:"asset_name"`
+	LiabilityID     *uuid.UUID         `db:"liability_id" json:"liability_id"`
+	LiabilityName   *string            `db:"liability_name" json:"liability_name"`
+	CategoryID      uuid.UUID          `db:"category_id" json:"category_id"`
+	CategoryName    string             `db:"category_name" json:"category_name"`
+	CategoryType    string             `db:"category_type" json:"category_type"`
+	Amount          decimal.Decimal    `db:"amount" json:"amount"`
+	TransactionDate time.Time          `db:"transaction_date" json:"transaction_date"`
+	Notes           *string            `db:"notes" json:"notes"`
+	Tags            pq.StringArray     `db:"tags" json:"tags"`
+	IsSplit         bool               `db:"is_split" json:"is_split"`
+	Splits          []TransactionSplit `db:"-" json:"splits,omitempty"`
+	CreatedAt       time.Time          `db:"created_at" json:"-"`
+}
+
+// TransactionSplit is one line of a transaction divided across categories.
+// The lines of a split transaction add up to its amount.
+type TransactionSplit struct {
+	ID            uuid.UUID       `db:"id" json:"id"`
+	TransactionID uuid.UUID       `db:"transaction_id" json:"-"`
+	CategoryID    uuid.UUID       `db:"category_id" json:"category_id"`
+	CategoryName  string          `db:"category_name" json:"category_name"`
+	Amount        decimal.Decimal `db:"amount" json:"amount"`
+	Notes         *string         `db:"notes" json:"notes"`
 }
 
 type CreateTransaction struct {
 	ID              uuid.UUID
 	UserID          uuid.UUID
-	AssetID         *uuid.UUID       `json:"asset_id"`
-	LiabilityID     *uuid.UUID       `json:"liability_id"`
-	CategoryID      uuid.UUID        `json:"category_id" validate:"required"`
-	Amount          *decimal.Decimal `json:"amount" validate:"required"`
-	TransactionDate string           `json:"transaction_date" validate:"required"`
-	Notes           *string          `json:"notes"`
+	AssetID         *uuid.UUID               `json:"asset_id"`
+	LiabilityID     *uuid.UUID               `json:"liability_id"`
+	CategoryID      uuid.UUID                `json:"category_id"` // may be left empty on create for a rule to assign
+	Amount          *decimal.Decimal         `json:"amount" validate:"required"`
+	TransactionDate string                   `json:"transaction_date" validate:"required"`
+	Notes           *string                  `json:"notes"`
+	Tags            []string                 `json:"tags" validate:"omitempty,max=20,dive,max=50"` // nil keeps the current tags on update
+	Splits          []CreateTransactionSplit `json:"splits" validate:"omitempty,dive"`             // nil keeps the current splits on update, empty removes them
+}
+
+type CreateTransactionSplit struct {
+	CategoryID uuid.UUID        `json:"category_id" validate:"required"`
+	Amount     *decimal.Decimal `json:"amount" validate:"required"`
+	Notes      *string          `json:"notes"`
+}
+
+const (
+	BulkOpCreate       = "create"
+	BulkOpRecategorize = "recategorize"
+	BulkOpRetag        = "retag"
+	BulkOpMove         = "move"
+	BulkOpDelete       = "delete"
+)
+
+// BulkTransactionRequest runs many operations in one go. Unless AllOrNothing
+// is set, a failing operation is skipped and the rest are still saved.
+type BulkTransactionRequest struct {
+	AllOrNothing bool                       `json:"all_or_nothing"`
+	Operations   []BulkTransactionOperation `json:"operations" validate:"required,min=1,max=500,dive"`
+}
+
+// BulkTransactionOperation carries the fields its Op needs: Create for create,
+// ID for the others plus CategoryID, Tags or AssetID for recategorize, retag
+// and move.
+type BulkTransactionOperation struct {
+	Op         string             `json:"op" validate:"required,oneof=create recategorize retag move delete"`
+	ID         uuid.UUID          `json:"id"`
+	Create     *CreateTransaction `json:"create"`
+	CategoryID *uuid.UUID         `json:"category_id"`
+	Tags       []string           `json:"tags" validate:"omitempty,max=20,dive,max=50"`
+	AssetID    *uuid.UUID         `json:"asset_id"`
+}
+
+type BulkTransactionResult struct {
+	Index   int        `json:"index"`
+	Op      string     `json:"op"`
+	ID      *uuid.UUID `json:"id,omitempty"`
+	Success bool       `json:"success"`
+	Error   string     `json:"error,omitempty"`
+}
+
+type BulkTransactionResponse struct {
+	Succeeded int                     `json:"succeeded"`
+	Failed    int                     `json:"failed"`
+	Results   []BulkTransactionResult `json:"results"`
 }
 
 type ListTransactionRequest struct {
@@ -54,10 +121,31 @@ type ListTransactionRequest struct {
 	UserID       uuid.UUID
 	CategoryName string `query:"category_name"`
 	Notes        string `query:"notes"`
-	FilterType   string `query:"filter_type"` // "week", "month", "year", "range"
+	FilterType   string `query:"filter_type"` // "week", "month", "quarter", "year", "range"
 	DateStr      string `query:"date"`        // reference date YYYY-MM-DD
 	StartDateStr string `query:"start_date"`  // range start date YYYY-MM-DD
 	EndDateStr   string `query:"end_date"`    // range end date YYYY-MM-DD
+	Tags         string `query:"tags"`        // comma separated tag names
+	TagMatch     string `query:"tag_match"`   // "any" (default), "all"
+
+	MinAmount     *decimal.Decimal `query:"min_amount"`
+	MaxAmount     *decimal.Decimal `query:"max_amount"`
+	AssetID       *uuid.UUID       `query:"asset_id"`
+	LiabilityID   *uuid.UUID       `query:"liability_id"`
+	BaseType      string           `query:"base_type"`   // "income", "expense"
+	CategoryIDs   []uuid.UUID      `query:"category_id"` // repeatable, subcategories included
+	CreatedAfter  *time.Time       `query:"created_after"`
+	CreatedBefore *time.Time       `query:"created_before"` // exclusive
+	UpdatedAfter  *time.Time       `query:"updated_after"`
+	UpdatedBefore *time.Time       `query:"updated_before"` // exclusive
+}
+
+// TagNames returns the normalized tag names of the tags filter.
+func (r *ListTransactionRequest) TagNames() []string {
+	if r.Tags == "" {
+		return nil
+	}
+	return NormalizeTagNames(strings.Split(r.Tags, ","))
 }
 
 type ListTransactionResponse struct {
@@ -72,28 +160,39 @@ type ListTransactionResponse struct {
 	Amount          decimal.Decimal `json:"amount"`
 	TransactionDate time.Time       `json:"transaction_date"`
 	Notes           *string         `json:"notes"`
+	Tags            []string        `json:"tags"`
+	IsSplit         bool            `json:"is_split"`
 }
 
 type TransactionSummary struct {
-	Income  decimal.Decimal `json:"income"`
-	Expense decimal.Decimal `json:"expense"`
-	Net     decimal.Decimal `json:"net"`
+	Income     decimal.Decimal   `json:"income"`
+	Expense    decimal.Decimal   `json:"expense"`
+	Net        decimal.Decimal   `json:"net"`
+	Categories []CategorySummary `json:"categories"`
 }
 
-type ListCategoryRequest struct {
-	UserID   uuid.UUID
-	BaseType string `query:"base_type"` // "income", "expense"
-	Search   string `query:"search"`
+// CategorySummary is a node of the category spend tree. Amount is what was
+// booked on the category itself, Total also includes all of its descendants.
+type CategorySummary struct {
+	ID       uuid.UUID         `db:"id" json:"id"`
+	ParentID *uuid.UUID        `db:"parent_id" json:"parent_id"`
+	Name     string            `db:"name" json:"name"`
+	BaseType string            `db:"base_type" json:"base_type"`
+	Amount   decimal.Decimal   `db:"amount" json:"amount"`
+	Total    decimal.Decimal   `db:"-" json:"total"`
+	Children []CategorySummary `db:"-" json:"children,omitempty"`
 }
 
 type TransactionRepository interface {
-	ListCategory(ctx context.Context, req *ListCategoryRequest) (*[]Category, error)
-	GetCategoryByID(ctx context.Context, id, userID uuid.UUID) (*Category, error)
-	InsertCategory(ctx context.Context, category *Category) error
-	DeleteCategory(ctx context.Context, id, userID uuid.UUID) error
-	List(ctx context.Context, req *ListTransactionRequest) (*[]Transaction, int, error)
+	GetCategoryByID(ctx context.Context, id, transactionID, userID uuid.UUID) (*Category, error)
+	List(ctx context.Context, req *ListTransactionRequest) (*[]Transaction, pkg.Page, error)
 	GetSummary(ctx context.Context, req *ListTransactionRequest) (*TransactionSummary, error)
+	GetCategorySummary(ctx context.Context, req *ListTransactionRequest) (*[]CategorySummary, error)
+	GetTagSummary(ctx context.Context, req *ListTransactionRequest) (*[]TagSummary, error)
+	ListForRule(ctx context.Context, rule *CategorizationRule, userID uuid.UUID) (*[]Transaction, error)
 	GetByID(ctx context.Context, id, userID uuid.UUID) (*Transaction, error)
+	ListSplits(ctx context.Context, transactionID uuid.UUID) (*[]TransactionSplit, error)
+	ReplaceSplits(ctx context.Context, transactionID uuid.UUID, splits []TransactionSplit) error
 	Delete(ctx context.Context, id, userID uuid.UUID) error
 	Insert(ctx context.Context, data *TransactionDB) error
 	Update(ctx context.Context, data *TransactionDB) error
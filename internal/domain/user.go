@@ -8,12 +8,15 @@ import (
 )
 
 type User struct {
-	ID           uuid.UUID `db:"id" json:"id"`
-	FullName     string    `db:"full_name" json:"full_name"`
-	Username     string    `db:"username" json:"username"`
-	Email        string    `db:"email" json:"email"`
-	Password     string    `db:"password" json:"-"`
-	RefreshToken string    `db:"refresh_token" json:"-"`
+	ID       uuid.UUID `db:"id" json:"id"`
+	FullName string    `db:"full_name" json:"full_name"`
+	Username string    `db:"username" json:"username"`
+	Email    string    `db:"email" json:"email"`
+	// EmailVerified is only set for addresses a provider vouched for; local
+	// signup doesn't verify emails
+	EmailVerified bool   `db:"email_verified" json:"email_verified"`
+	Password      string `db:"password" json:"-"`
+	RefreshToken  string `db:"refresh_token" json:"-"`
 }
 
 type RegisterRequest struct {
@@ -37,6 +40,33 @@ type RefreshToken struct {
 	IPAddress  string
 }
 
+type LoginAttempt struct {
+	KeyType      string     `db:"key_type"`
+	KeyValue     string     `db:"key_value"`
+	FailedCount  int        `db:"failed_count"`
+	LastFailedAt time.Time  `db:"last_failed_at"`
+	LockedUntil  *time.Time `db:"locked_until"`
+}
+
+type AuthAuditLog struct {
+	UserID    *uuid.UUID `db:"user_id"`
+	Username  string     `db:"username"`
+	IPAddress string     `db:"ip_address"`
+	Event     string     `db:"event"`
+}
+
+// LoginThrottleConfig controls how failed logins are counted and locked out.
+// A key is locked once its failures inside Window reach the max attempts, and
+// every further failure doubles the lockout starting from BaseLockout. The
+// count only restarts a Window after the last lockout ended, or on success.
+type LoginThrottleConfig struct {
+	MaxUsernameAttempts int
+	MaxIPAttempts       int
+	Window              time.Duration
+	BaseLockout         time.Duration
+	MaxLockout          time.Duration
+}
+
 type UserRepository interface {
 	Create(ctx context.Context, user *User) (uuid.UUID, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
@@ -45,6 +75,12 @@ type UserRepository interface {
 	CheckRefreshToken(ctx context.Context, userId uuid.UUID, refreshToken string) (exp time.Time, err error)
 	InsertRefreshToken(ctx context.Context, data RefreshToken) error
 	SeedDefaultCategories(ctx context.Context, userID uuid.UUID) error
+	CreatePersonalWorkspace(ctx context.Context, userID uuid.UUID) error
 	RevokeRefreshToken(ctx context.Context, userID uuid.UUID, refreshToken string) error
 	RemoveExpiredToken(ctx context.Context, userID *uuid.UUID) error
+	GetLoginAttempt(ctx context.Context, keyType, keyValue string) (*LoginAttempt, error)
+	IncrementLoginFailure(ctx context.Context, keyType, keyValue string, window time.Duration) (int, error)
+	LockLogin(ctx context.Context, keyType, keyValue string, until time.Time) error
+	ResetLoginAttempt(ctx context.Context, keyType, keyValue string) error
+	InsertAuthAudit(ctx context.Context, data AuthAuditLog) error
 }
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+type Workspace struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	Name       string    `db:"name" json:"name"`
+	OwnerID    uuid.UUID `db:"owner_id" json:"owner_id"`
+	IsPersonal bool      `db:"is_personal" json:"is_personal"`
+	Role       string    `db:"role" json:"role"`
+	CreatedAt  time.Time `db:"created_at" json:"-"`
+}
+
+type WorkspaceMember struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"-"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	Username    string    `db:"username" json:"username"`
+	FullName    string    `db:"full_name" json:"full_name"`
+	Role        string    `db:"role" json:"role"`
+	CreatedAt   time.Time `db:"created_at" json:"joined_at"`
+}
+
+type WorkspaceInvitation struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	WorkspaceID   uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName string    `db:"workspace_name" json:"workspace_name"`
+	InvitedBy     uuid.UUID `db:"invited_by" json:"invited_by"`
+	InviteeID     uuid.UUID `db:"invitee_id" json:"-"`
+	Role          string    `db:"role" json:"role"`
+	Status        string    `db:"status" json:"status"`
+	ExpiresAt     time.Time `db:"expires_at" json:"expires_at"`
+	CreatedAt     time.Time `db:"created_at" json:"-"`
+}
+
+type CreateWorkspace struct {
+	ID   uuid.UUID
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+type InviteWorkspaceMember struct {
+	WorkspaceID uuid.UUID
+	Username    string `json:"username" validate:"required"`
+	Role        string `json:"role" validate:"required,oneof=editor viewer"`
+}
+
+type UpdateWorkspaceMember struct {
+	WorkspaceID uuid.UUID
+	UserID      uuid.UUID
+	Role        string `json:"role" validate:"required,oneof=editor viewer"`
+}
+
+type WorkspaceRepository interface {
+	List(ctx context.Context, userID uuid.UUID) (*[]Workspace, error)
+	GetByID(ctx context.Context, id, userID uuid.UUID) (*Workspace, error)
+	Insert(ctx context.Context, data *Workspace) (uuid.UUID, error)
+	Update(ctx context.Context, data *Workspace) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListMembers(ctx context.Context, workspaceID uuid.UUID) (*[]WorkspaceMember, error)
+	InsertMember(ctx context.Context, workspaceID, userID uuid.UUID, role string) error
+	UpdateMemberRole(ctx context.Context, workspaceID, userID uuid.UUID, role string) error
+	DeleteMember(ctx context.Context, workspaceID, userID uuid.UUID) error
+	InsertInvitation(ctx context.Context, data *WorkspaceInvitation) error
+	ListInvitations(ctx context.Context, inviteeID uuid.UUID) (*[]WorkspaceInvitation, error)
+	GetInvitation(ctx context.Context, id, inviteeID uuid.UUID) (*WorkspaceInvitation, error)
+	UpdateInvitationStatus(ctx context.Context, id uuid.UUID, status string) error
+}
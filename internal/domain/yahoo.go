@@ -7,5 +7,8 @@ import (
 )
 
 type YahooProvider interface {
+	// FetchPrice returns the price of an IDX ticker.
 	FetchPrice(ctx context.Context, ticker string) (decimal.Decimal, error)
+	// FetchQuote returns the market price of any Yahoo symbol, e.g. GC=F.
+	FetchQuote(ctx context.Context, symbol string) (decimal.Decimal, error)
 }
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ZakatRequest estimates the zakat maal of the current workspace. GoldPrice
+// overrides the configured price per gram used for the nisab.
+type ZakatRequest struct {
+	UserID    uuid.UUID
+	GoldPrice decimal.Decimal `query:"gold_price"`
+}
+
+// ZakatCategory is the value held in an asset category and whether it counts
+// toward zakatable wealth.
+type ZakatCategory struct {
+	CategoryID  uuid.UUID       `db:"category_id" json:"category_id"`
+	Name        string          `db:"name" json:"name"`
+	BaseType    string          `db:"base_type" json:"base_type"`
+	Value       decimal.Decimal `db:"value" json:"value"`
+	IsZakatable bool            `db:"is_zakatable" json:"is_zakatable"`
+}
+
+// HaulHistory is what the daily snapshots tell about the haul: since when the
+// zakatable wealth of the workspace is tracked and its lowest value within the
+// haul.
+type HaulHistory struct {
+	TrackedSince          *time.Time       `db:"tracked_since"`
+	LowestZakatableWealth *decimal.Decimal `db:"lowest_zakatable_wealth"`
+}
+
+type Zakat struct {
+	GoldPricePerGram      decimal.Decimal  `json:"gold_price_per_gram"`
+	NisabGrams            decimal.Decimal  `json:"nisab_grams"`
+	Nisab                 decimal.Decimal  `json:"nisab"`
+	ZakatableAssets       decimal.Decimal  `json:"zakatable_assets"`
+	DebtsDue              decimal.Decimal  `json:"debts_due"` // short-term liabilities, deducted from the assets
+	ZakatableWealth       decimal.Decimal  `json:"zakatable_wealth"`
+	AboveNisab            bool             `json:"above_nisab"`
+	HaulStart             string           `json:"haul_start"`
+	HaulEnd               string           `json:"haul_end"`
+	TrackedSince          *string          `json:"tracked_since"`
+	LowestZakatableWealth *decimal.Decimal `json:"lowest_zakatable_wealth"` // within the haul
+	HaulCompleted         bool             `json:"haul_completed"`
+	Due                   bool             `json:"due"`
+	Rate                  decimal.Decimal  `json:"rate"`
+	Amount                decimal.Decimal  `json:"amount"`
+	Categories            []ZakatCategory  `json:"categories"`
+}
+
+type ZakatRepository interface {
+	ListCategoryBalances(ctx context.Context, userID uuid.UUID) (*[]ZakatCategory, error)
+	GetDebtsDue(ctx context.Context, userID uuid.UUID) (decimal.Decimal, error)
+	GetHaulHistory(ctx context.Context, userID uuid.UUID, from time.Time) (*HaulHistory, error)
+}
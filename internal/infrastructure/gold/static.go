@@ -0,0 +1,30 @@
+package gold
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// staticProvider serves a price per gram set in the configuration, as the
+// fallback of the live feed.
+type staticProvider struct {
+	price decimal.Decimal
+}
+
+// NewStaticProvider parses price as rupiah per gram; an empty or invalid
+// value leaves the provider unconfigured.
+func NewStaticProvider(price string) domain.GoldPriceProvider {
+	p, _ := decimal.NewFromString(price)
+	return &staticProvider{price: p}
+}
+
+func (s *staticProvider) FetchPricePerGram(ctx context.Context) (decimal.Decimal, error) {
+	if !s.price.IsPositive() {
+		return decimal.Zero, errors.New("gold price is not configured")
+	}
+
+	return s.price, nil
+}
@@ -0,0 +1,77 @@
+package gold
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	goldFuturesSymbol = "GC=F"  // US dollars per troy ounce
+	usdIDRSymbol      = "IDR=X" // rupiah per US dollar
+
+	// livePriceTTL is how long a fetched price is reused, gold barely moves
+	// within the hour and every fetch costs two API calls
+	livePriceTTL = time.Hour
+)
+
+var gramsPerTroyOunce = decimal.RequireFromString("31.1034768")
+
+// yahooProvider prices gold from the gold futures quote converted to rupiah.
+// While the feed is unavailable the fallback, usually the configured static
+// price, serves instead.
+type yahooProvider struct {
+	quotes   domain.YahooProvider
+	fallback domain.GoldPriceProvider
+	log      *log.Logger
+
+	mu        sync.Mutex
+	price     decimal.Decimal
+	fetchedAt time.Time
+}
+
+func NewYahooProvider(quotes domain.YahooProvider, fallback domain.GoldPriceProvider, log *log.Logger) domain.GoldPriceProvider {
+	return &yahooProvider{quotes: quotes, fallback: fallback, log: log}
+}
+
+func (y *yahooProvider) FetchPricePerGram(ctx context.Context) (decimal.Decimal, error) {
+	y.mu.Lock()
+	defer y.mu.Unlock()
+
+	if y.price.IsPositive() && time.Since(y.fetchedAt) < livePriceTTL {
+		return y.price, nil
+	}
+
+	price, err := y.fetchLive(ctx)
+	if err != nil {
+		y.log.Printf("[ERROR] live gold price: %s", err.Error())
+
+		fallback, fallbackErr := y.fallback.FetchPricePerGram(ctx)
+		if fallbackErr != nil {
+			return decimal.Zero, fmt.Errorf("%w; fallback: %w", err, fallbackErr)
+		}
+		return fallback, nil
+	}
+
+	y.price, y.fetchedAt = price, time.Now()
+	return price, nil
+}
+
+func (y *yahooProvider) fetchLive(ctx context.Context) (decimal.Decimal, error) {
+	perOunce, err := y.quotes.FetchQuote(ctx, goldFuturesSymbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	rupiahPerDollar, err := y.quotes.FetchQuote(ctx, usdIDRSymbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return perOunce.Mul(rupiahPerDollar).Div(gramsPerTroyOunce).Round(2), nil
+}
@@ -0,0 +1,175 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// mockServer is a minimal OIDC provider: discovery, JWKS and a token endpoint that
+// checks the PKCE verifier against the challenge sent to the authorization endpoint.
+type mockServer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]mockGrant
+}
+
+type mockGrant struct {
+	challenge string
+	nonce     string
+}
+
+func newMockServer(t *testing.T) *mockServer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &mockServer{key: key, codes: map[string]mockGrant{}}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(discovery{
+			Issuer:                m.URL,
+			AuthorizationEndpoint: m.URL + "/authorize",
+			TokenEndpoint:         m.URL + "/token",
+			JwksURI:               m.URL + "/jwks",
+		})
+	})
+
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		encode := base64.RawURLEncoding.EncodeToString
+		json.NewEncoder(w).Encode(map[string]any{"keys": []jwk{{
+			Kty: "RSA",
+			Kid: "test",
+			N:   encode(key.N.Bytes()),
+			E:   encode(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+
+		m.mu.Lock()
+		grant, ok := m.codes[r.Form.Get("code")]
+		delete(m.codes, r.Form.Get("code"))
+		m.mu.Unlock()
+
+		sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != grant.challenge {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, idTokenClaims{
+			Email:         "budi@example.com",
+			EmailVerified: true,
+			Nonce:         grant.nonce,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    m.URL,
+				Subject:   "subject-1",
+				Audience:  jwt.ClaimStrings{"netbase"},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+		})
+		token.Header["kid"] = "test"
+
+		signed, err := token.SignedString(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": signed})
+	})
+
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+	return m
+}
+
+// authorize plays the user approving the login and returns the issued code.
+func (m *mockServer) authorize(t *testing.T, authURL string) string {
+	t.Helper()
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := u.Query()
+	if params.Get("code_challenge_method") != "S256" {
+		t.Fatalf("code_challenge_method is %q, want S256", params.Get("code_challenge_method"))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	code := "code-" + params.Get("state")
+	m.codes[code] = mockGrant{challenge: params.Get("code_challenge"), nonce: params.Get("nonce")}
+	return code
+}
+
+func testProvider(m *mockServer) *provider {
+	return NewProvider(Config{
+		Issuer:      m.URL,
+		ClientID:    "netbase",
+		RedirectURL: "http://localhost/v1/auth/oidc/mock/callback",
+	}).(*provider)
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func TestProviderExchangeWithPKCE(t *testing.T) {
+	m := newMockServer(t)
+	p := testProvider(m)
+	ctx := context.Background()
+
+	authURL, err := p.AuthCodeURL(ctx, "state-1", "nonce-1", pkceChallenge("verifier-1"))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+
+	claims, err := p.Exchange(ctx, m.authorize(t, authURL), "verifier-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	if claims.Subject != "subject-1" || claims.Email != "budi@example.com" || !claims.EmailVerified {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+	if claims.Nonce != "nonce-1" {
+		t.Errorf("nonce is %q, want nonce-1", claims.Nonce)
+	}
+}
+
+func TestProviderExchangeRejectsWrongVerifier(t *testing.T) {
+	m := newMockServer(t)
+	p := testProvider(m)
+	ctx := context.Background()
+
+	authURL, err := p.AuthCodeURL(ctx, "state-1", "nonce-1", pkceChallenge("verifier-1"))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+
+	if _, err := p.Exchange(ctx, m.authorize(t, authURL), "another-verifier"); err == nil {
+		t.Error("Exchange accepted a code with the wrong PKCE verifier")
+	}
+}
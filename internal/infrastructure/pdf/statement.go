@@ -0,0 +1,166 @@
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/go-pdf/fpdf"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	pageWidth   = 210.0 // A4, in mm
+	margin      = 20.0
+	amountWidth = 50.0
+	lineHeight  = 7.0
+)
+
+type statementRenderer struct{}
+
+// NewStatementRenderer renders financial statements as A4 PDFs using the
+// built-in Helvetica font.
+func NewStatementRenderer() domain.StatementRenderer {
+	return &statementRenderer{}
+}
+
+// document wraps fpdf with the layout shared by every statement.
+type document struct {
+	pdf *fpdf.Fpdf
+	tr  func(string) string
+}
+
+func newDocument(title, subtitle string) *document {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	pdf.SetTitle(title, true)
+	pdf.SetCreator("NetBase", false)
+	pdf.AliasNbPages("")
+
+	generated := time.Now().Format(time.DateOnly)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.SetTextColor(128, 128, 128)
+		pdf.CellFormat(0, 5, "Generated on "+generated, "", 0, "L", false, 0, "")
+		pdf.SetX(margin)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+
+	// the core fonts only cover cp1252, names outside it are approximated
+	d := &document{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, d.tr(title), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(96, 96, 96)
+	pdf.CellFormat(0, 6, d.tr(subtitle), "", 1, "L", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Ln(4)
+
+	return d
+}
+
+func (d *document) heading(text string) {
+	d.pdf.Ln(2)
+	d.pdf.SetFont("Helvetica", "B", 12)
+	d.pdf.SetFillColor(235, 238, 242)
+	d.pdf.CellFormat(0, lineHeight+1, d.tr(text), "", 1, "L", true, 0, "")
+}
+
+func (d *document) row(label string, amount decimal.Decimal, bold bool) {
+	style := ""
+	if bold {
+		style = "B"
+	}
+	d.pdf.SetFont("Helvetica", style, 10)
+	d.pdf.CellFormat(pageWidth-2*margin-amountWidth, lineHeight, d.tr(label), "", 0, "L", false, 0, "")
+	d.pdf.CellFormat(amountWidth, lineHeight, formatAmount(amount), "", 1, "R", false, 0, "")
+}
+
+// total draws a bold row under a rule.
+func (d *document) total(label string, amount decimal.Decimal) {
+	y := d.pdf.GetY()
+	d.pdf.SetDrawColor(160, 160, 160)
+	d.pdf.Line(margin, y, pageWidth-margin, y)
+	d.row(label, amount, true)
+}
+
+func (d *document) section(section domain.StatementSection, emptyText string) {
+	d.heading(section.Name)
+	if len(section.Lines) == 0 {
+		d.pdf.SetFont("Helvetica", "I", 10)
+		d.pdf.CellFormat(0, lineHeight, emptyText, "", 1, "L", false, 0, "")
+	}
+
+	for _, line := range section.Lines {
+		label := line.Name
+		if line.Category != "" && line.Category != line.Name {
+			label += " (" + line.Category + ")"
+		}
+		d.row(label, line.Amount, false)
+	}
+	d.total("Total "+strings.ToLower(section.Name), section.Total)
+}
+
+func (d *document) output(w io.Writer) error {
+	return d.pdf.Output(w)
+}
+
+func (r *statementRenderer) IncomeStatement(w io.Writer, statement *domain.IncomeStatement) error {
+	d := newDocument("Income Statement", statement.From+" to "+statement.To)
+
+	d.section(statement.Income, "No income in this period")
+	d.section(statement.Expense, "No expenses in this period")
+
+	d.pdf.Ln(4)
+	d.total("Net income", statement.NetIncome)
+
+	return d.output(w)
+}
+
+func (r *statementRenderer) BalanceSheet(w io.Writer, sheet *domain.BalanceSheet) error {
+	d := newDocument("Balance Sheet", "As of "+sheet.Date)
+
+	for _, section := range sheet.Assets {
+		d.section(section, "No assets")
+	}
+	d.pdf.Ln(2)
+	d.total("Total assets", sheet.TotalAssets)
+
+	for _, section := range sheet.Liabilities {
+		d.section(section, "No liabilities")
+	}
+	d.pdf.Ln(2)
+	d.total("Total liabilities", sheet.TotalLiabilities)
+
+	d.pdf.Ln(4)
+	d.total("Net worth", sheet.NetWorth)
+
+	return d.output(w)
+}
+
+// formatAmount renders an amount with two decimals and thousands separators.
+func formatAmount(amount decimal.Decimal) string {
+	text := amount.Abs().StringFixed(2)
+	whole, fraction, _ := strings.Cut(text, ".")
+
+	var b strings.Builder
+	if amount.IsNegative() {
+		b.WriteByte('-')
+	}
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	b.WriteByte('.')
+	b.WriteString(fraction)
+
+	return b.String()
+}
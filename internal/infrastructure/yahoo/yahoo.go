@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 
 	"github.com/fazriegi/netbase-be/internal/domain"
 	"github.com/shopspring/decimal"
@@ -37,8 +38,12 @@ type YahooFinanceQuote struct {
 }
 
 func (y *yahooProvider) FetchPrice(ctx context.Context, ticker string) (decimal.Decimal, error) {
-	url := fmt.Sprintf("https://yahoo-finance-real-time1.p.rapidapi.com/stock/get-options?symbol=%s.JK&lang=en-US", ticker)
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	return y.FetchQuote(ctx, ticker+".JK")
+}
+
+func (y *yahooProvider) FetchQuote(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	endpoint := fmt.Sprintf("https://yahoo-finance-real-time1.p.rapidapi.com/stock/get-options?symbol=%s&lang=en-US", url.QueryEscape(symbol))
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return decimal.Zero, err
 	}
@@ -70,12 +75,12 @@ func (y *yahooProvider) FetchPrice(ctx context.Context, ticker string) (decimal.
 	}
 
 	if len(yahooFinanceResp.OptionChain.Result) == 0 {
-		return decimal.Zero, fmt.Errorf("no result found for ticker %s", ticker)
+		return decimal.Zero, fmt.Errorf("no result found for symbol %s", symbol)
 	}
 
 	result := yahooFinanceResp.OptionChain.Result[0]
 	if result.Quote.RegularMarketPrice.IsZero() {
-		return decimal.Zero, fmt.Errorf("no market price found for ticker %s", ticker)
+		return decimal.Zero, fmt.Errorf("no market price found for symbol %s", symbol)
 	}
 
 	return result.Quote.RegularMarketPrice, nil
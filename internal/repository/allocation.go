@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type allocationRepository struct {
+	db *sqlx.DB
+}
+
+func NewAllocationRepository(db *sqlx.DB) domain.AllocationRepository {
+	return &allocationRepository{db: db}
+}
+
+func (r *allocationRepository) ListTargets(ctx context.Context, userID uuid.UUID) (*[]domain.AllocationTarget, error) {
+	db := getQueryer(ctx, r.db)
+	var targets = make([]domain.AllocationTarget, 0)
+	query := `
+		SELECT t.id, t.workspace_id, t.category_id, CAST(t.base_type AS text) AS base_type,
+			COALESCE(ac.name, CAST(t.base_type AS text)) AS name,
+			t.target_percent, t.tolerance_percent, t.created_at
+		FROM allocation_targets t
+		LEFT JOIN asset_categories ac ON ac.id = t.category_id
+		WHERE t.workspace_id = $2
+			AND ` + memberOf("t.workspace_id", "$1", false) + `
+		ORDER BY t.target_percent DESC, name ASC
+	`
+	err := db.SelectContext(ctx, &targets, query, userID, workspaceFromContext(ctx, userID))
+
+	return &targets, err
+}
+
+// ReplaceTargets swaps the targets of the current workspace for req.Targets.
+func (r *allocationRepository) ReplaceTargets(ctx context.Context, req *domain.SetAllocationTargets) error {
+	db := getQueryer(ctx, r.db)
+	workspaceID := workspaceFromContext(ctx, req.UserID)
+	if err := authorizeWorkspace(ctx, db, workspaceID, req.UserID, true); err != nil {
+		return err
+	}
+
+	_, err := db.ExecContext(ctx, `DELETE FROM allocation_targets WHERE workspace_id = $1`, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO allocation_targets (workspace_id, user_id, category_id, base_type, target_percent, tolerance_percent)
+		VALUES ($1, $2, $3, CAST(NULLIF($4, '') AS asset_base_type), $5, $6)
+	`
+	for _, target := range req.Targets {
+		_, err := db.ExecContext(ctx, query,
+			workspaceID, req.UserID, target.CategoryID, target.BaseType, *target.TargetPercent, *target.TolerancePercent,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -23,17 +23,27 @@ func NewAssetRepository(db *sqlx.DB) domain.AssetRepository {
 	return &assetRepository{db: db}
 }
 
-func (r *assetRepository) ListAsset(ctx context.Context, req *domain.ListAssetRequest) (*[]domain.Asset, int, error) {
+// assetKeyset is the order of cursor paginated asset lists, newest first.
+var assetKeyset = pkg.Keyset{
+	Columns: []pkg.KeysetColumn{
+		{Expr: "assets.created_at", Type: "timestamptz"},
+		{Expr: "assets.id", Type: "uuid"},
+	},
+	Desc: true,
+}
+
+func (r *assetRepository) ListAsset(ctx context.Context, req *domain.ListAssetRequest) (*[]domain.Asset, pkg.Page, error) {
 	db := getQueryer(ctx, r.db)
 	var assets = make([]domain.Asset, 0)
 	var total int
 	var defaultSort = "created_at desc"
 	query := `
-		SELECT assets.id, assets.user_id, ac.name as category, assets.name, assets.current_value, assets.details, assets.is_active,
-			assets.created_at
+		SELECT assets.id, assets.user_id, assets.workspace_id, ac.name as category, assets.name, assets.current_value, assets.details,
+			assets.is_active, assets.created_at
 		FROM assets 
-		join asset_categories ac on ac.id = assets.category_id and ac.user_id = assets.user_id
-		WHERE assets.user_id = :user_id
+		join asset_categories ac on ac.id = assets.category_id
+		WHERE assets.workspace_id = :workspace_id
+			AND ` + memberOf("assets.workspace_id", ":user_id", false) + `
 	`
 
 	if req.Name != "" {
@@ -48,6 +58,19 @@ func (r *assetRepository) ListAsset(ctx context.Context, req *domain.ListAssetRe
 		query += ` AND assets.is_active = :is_active`
 	}
 
+	if req.UseCursor() {
+		assets, page, err := selectKeysetPage(ctx, db, query, map[string]interface{}{
+			"user_id":      req.UserId,
+			"workspace_id": workspaceFromContext(ctx, req.UserId),
+			"name":         "%" + req.Name + "%",
+			"category":     "%" + req.Category + "%",
+			"is_active":    req.IsActive,
+		}, assetKeyset, &req.PaginationRequest, func(asset *domain.Asset) []string {
+			return []string{cursorTime(asset.CreatedAt), asset.ID.String()}
+		})
+		return &assets, page, err
+	}
+
 	if req.Sort == nil {
 		req.Sort = &defaultSort
 	}
@@ -60,10 +83,11 @@ func (r *assetRepository) ListAsset(ctx context.Context, req *domain.ListAssetRe
 	go func() {
 		defer wg.Done()
 		resCount, err := db.NamedQueryContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM (%s) as count_query", query), map[string]interface{}{
-			"user_id":   req.UserId,
-			"name":      "%" + req.Name + "%",
-			"category":  "%" + req.Category + "%",
-			"is_active": req.IsActive,
+			"user_id":      req.UserId,
+			"workspace_id": workspaceFromContext(ctx, req.UserId),
+			"name":         "%" + req.Name + "%",
+			"category":     "%" + req.Category + "%",
+			"is_active":    req.IsActive,
 		})
 
 		if err != nil {
@@ -85,13 +109,14 @@ func (r *assetRepository) ListAsset(ctx context.Context, req *domain.ListAssetRe
 	go func() {
 		defer wg.Done()
 		res, err := pkg.SelectWithPagination(ctx, db, query, map[string]interface{}{
-			"page":      req.Page,
-			"limit":     req.Limit,
-			"sort":      req.Sort,
-			"user_id":   req.UserId,
-			"name":      "%" + req.Name + "%",
-			"category":  "%" + req.Category + "%",
-			"is_active": req.IsActive,
+			"page":         req.Page,
+			"limit":        req.Limit,
+			"sort":         req.Sort,
+			"user_id":      req.UserId,
+			"workspace_id": workspaceFromContext(ctx, req.UserId),
+			"name":         "%" + req.Name + "%",
+			"category":     "%" + req.Category + "%",
+			"is_active":    req.IsActive,
 		})
 
 		if err != nil {
@@ -118,32 +143,25 @@ func (r *assetRepository) ListAsset(ctx context.Context, req *domain.ListAssetRe
 
 	for err := range errChan {
 		if err != nil {
-			return nil, 0, err
+			return nil, pkg.Page{}, err
 		}
 	}
 
-	return &assets, total, nil
-}
-
-func (r *assetRepository) ListCategory(ctx context.Context, userId uuid.UUID) (*[]domain.Category, error) {
-	db := getQueryer(ctx, r.db)
-	var categories = make([]domain.Category, 0)
-	query := `SELECT id, name, base_type FROM asset_categories WHERE user_id = $1 ORDER BY name ASC`
-	err := db.SelectContext(ctx, &categories, query, userId)
-
-	return &categories, err
+	return &assets, pkg.Page{Total: total}, nil
 }
 
 func (r *assetRepository) GetByID(ctx context.Context, id, userId uuid.UUID) (*domain.Asset, error) {
 	db := getQueryer(ctx, r.db)
 	var asset domain.Asset
 	query := `
-		SELECT assets.id, assets.user_id, assets.category_id, assets.name, assets.current_value, assets.details, assets.is_active, ac."name" as category, 
-			ac.base_type as category_type
+		SELECT assets.id, assets.user_id, assets.workspace_id, assets.category_id, assets.name, assets.current_value, assets.details,
+			assets.is_active, ac."name" as category, ac.base_type as category_type
 		FROM assets 
-		JOIN asset_categories ac ON assets.user_id = ac.user_id AND assets.category_id = ac.id
-		WHERE assets.id = $1 AND assets.user_id = $2`
-	err := db.GetContext(ctx, &asset, query, id, userId)
+		JOIN asset_categories ac ON assets.category_id = ac.id
+		WHERE assets.id = $1
+			AND assets.workspace_id = $3
+			AND ` + memberOf("assets.workspace_id", "$2", false)
+	err := db.GetContext(ctx, &asset, query, id, userId, workspaceFromContext(ctx, userId))
 	if err == sql.ErrNoRows {
 		return nil, errors.New(constant.ErrNotFound)
 	}
@@ -153,15 +171,35 @@ func (r *assetRepository) GetByID(ctx context.Context, id, userId uuid.UUID) (*d
 
 func (r *assetRepository) Delete(ctx context.Context, id, userId uuid.UUID) error {
 	db := getQueryer(ctx, r.db)
-	query := `DELETE FROM assets WHERE id = $1 AND user_id = $2`
-	_, err := db.ExecContext(ctx, query, id, userId)
+	workspaceID := workspaceFromContext(ctx, userId)
+	if err := authorizeWorkspace(ctx, db, workspaceID, userId, true); err != nil {
+		return err
+	}
 
-	return err
+	query := `DELETE FROM assets WHERE id = $1 AND assets.workspace_id = $3 AND ` + memberOf("assets.workspace_id", "$2", true)
+	res, err := db.ExecContext(ctx, query, id, userId, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return errors.New(constant.ErrNotFound)
+	}
+
+	return nil
 }
 
 func (r *assetRepository) Insert(ctx context.Context, data *domain.AssetDB) error {
 	db := getQueryer(ctx, r.db)
-	query := `INSERT INTO assets (user_id, category_id, name, current_value, details, is_active) VALUES (:user_id, :category_id, :name, :current_value, :details, :is_active)`
+	data.WorkspaceID = workspaceFromContext(ctx, data.UserId)
+	if err := authorizeWorkspace(ctx, db, data.WorkspaceID, data.UserId, true); err != nil {
+		return err
+	}
+	if err := authorizeCategory(ctx, db, "asset_categories", "assets", uuid.Nil, data.CategoryID, data.WorkspaceID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO assets (user_id, workspace_id, category_id, name, current_value, details, is_active) VALUES (:user_id, :workspace_id, :category_id, :name, :current_value, :details, :is_active)`
 	_, err := db.NamedExecContext(ctx, query, data)
 
 	return err
@@ -169,10 +207,28 @@ func (r *assetRepository) Insert(ctx context.Context, data *domain.AssetDB) erro
 
 func (r *assetRepository) Update(ctx context.Context, data *domain.AssetDB) error {
 	db := getQueryer(ctx, r.db)
-	query := `UPDATE assets SET name = :name, category_id = :category_id, current_value = :current_value, details = :details, is_active = :is_active, updated_at = now() WHERE id = :id AND user_id = :user_id`
-	_, err := db.NamedExecContext(ctx, query, data)
+	data.WorkspaceID = workspaceFromContext(ctx, data.UserId)
+	if err := authorizeWorkspace(ctx, db, data.WorkspaceID, data.UserId, true); err != nil {
+		return err
+	}
+	if err := authorizeCategory(ctx, db, "asset_categories", "assets", data.ID, data.CategoryID, data.WorkspaceID); err != nil {
+		return err
+	}
 
-	return err
+	// deactivated_at keeps when the asset stopped being held, for past balance sheets
+	query := `UPDATE assets SET name = :name, category_id = :category_id, current_value = :current_value, details = :details, is_active = :is_active,
+		deactivated_at = CASE WHEN :is_active THEN NULL WHEN assets.is_active IS NOT FALSE THEN now() ELSE assets.deactivated_at END,
+		updated_at = now() WHERE id = :id AND assets.workspace_id = :workspace_id AND ` + memberOf("assets.workspace_id", ":user_id", true)
+	res, err := db.NamedExecContext(ctx, query, data)
+	if err != nil {
+		return err
+	}
+
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return errors.New(constant.ErrNotFound)
+	}
+
+	return nil
 }
 
 func (r *assetRepository) GetTickers(ctx context.Context) (*[]string, error) {
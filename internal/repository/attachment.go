@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type attachmentRepository struct {
+	db *sqlx.DB
+}
+
+func NewAttachmentRepository(db *sqlx.DB) domain.AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+const attachmentColumns = `a.id, a.transaction_id, a.user_id, a.file_name, a.content_type, a.size_bytes, a.storage_key, a.created_at`
+
+// ListByTransaction returns the attachments of a transaction the user can read.
+// It returns constant.ErrNotFound when there is no such transaction.
+func (r *attachmentRepository) ListByTransaction(ctx context.Context, transactionID, userID uuid.UUID) (*[]domain.Attachment, error) {
+	db := getQueryer(ctx, r.db)
+	var attachments = make([]domain.Attachment, 0)
+	query := `
+		SELECT ` + attachmentColumns + `
+		FROM transaction_attachments a
+		JOIN transactions ON transactions.id = a.transaction_id
+		WHERE a.transaction_id = $1
+			AND transactions.workspace_id = $3
+			AND ` + memberOf("transactions.workspace_id", "$2", false) + `
+		ORDER BY a.created_at ASC
+	`
+	workspaceID := workspaceFromContext(ctx, userID)
+	if err := db.SelectContext(ctx, &attachments, query, transactionID, userID, workspaceID); err != nil {
+		return nil, err
+	}
+
+	if len(attachments) == 0 {
+		var exists bool
+		query = `
+			SELECT EXISTS (
+				SELECT 1 FROM transactions
+				WHERE transactions.id = $1
+					AND transactions.workspace_id = $3
+					AND ` + memberOf("transactions.workspace_id", "$2", false) + `
+			)
+		`
+		if err := db.GetContext(ctx, &exists, query, transactionID, userID, workspaceID); err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, errors.New(constant.ErrNotFound)
+		}
+	}
+
+	return &attachments, nil
+}
+
+// GetByID returns an attachment of the given transaction, provided the user can
+// read it or, with write set, edit it.
+func (r *attachmentRepository) GetByID(ctx context.Context, id, transactionID, userID uuid.UUID, write bool) (*domain.Attachment, error) {
+	db := getQueryer(ctx, r.db)
+	var attachment domain.Attachment
+	query := `
+		SELECT ` + attachmentColumns + `
+		FROM transaction_attachments a
+		JOIN transactions ON transactions.id = a.transaction_id
+		WHERE a.id = $1
+			AND a.transaction_id = $2
+			AND transactions.workspace_id = $4
+			AND ` + memberOf("transactions.workspace_id", "$3", write)
+	err := db.GetContext(ctx, &attachment, query, id, transactionID, userID, workspaceFromContext(ctx, userID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(constant.ErrNotFound)
+	}
+
+	return &attachment, err
+}
+
+// Insert stores the metadata of an attachment. It returns constant.ErrNotFound
+// when the transaction doesn't exist or the user can't edit it.
+func (r *attachmentRepository) Insert(ctx context.Context, data *domain.Attachment) error {
+	db := getQueryer(ctx, r.db)
+	query := `
+		INSERT INTO transaction_attachments (id, transaction_id, user_id, file_name, content_type, size_bytes, storage_key)
+		SELECT $1, transactions.id, $3, $4, $5, $6, $7
+		FROM transactions
+		WHERE transactions.id = $2
+			AND transactions.workspace_id = $8
+			AND ` + memberOf("transactions.workspace_id", "$3", true) + `
+		RETURNING created_at
+	`
+	err := db.QueryRowContext(ctx, query,
+		data.ID, data.TransactionID, data.UserID, data.FileName, data.ContentType, data.SizeBytes, data.StorageKey,
+		workspaceFromContext(ctx, data.UserID),
+	).Scan(&data.CreatedAt)
+	if err == sql.ErrNoRows {
+		return errors.New(constant.ErrNotFound)
+	}
+
+	return err
+}
+
+func (r *attachmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	db := getQueryer(ctx, r.db)
+	query := `DELETE FROM transaction_attachments WHERE id = $1`
+	res, err := db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return errors.New(constant.ErrNotFound)
+	}
+
+	return nil
+}
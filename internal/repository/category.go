@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// categoryRef is a table column pointing at a category table.
+type categoryRef struct {
+	table  string
+	column string
+}
+
+type categoryTable struct {
+	name string
+	refs []categoryRef
+}
+
+var categoryTables = map[string]categoryTable{
+	domain.CategoryKindAsset: {
+		name: "asset_categories",
+		refs: []categoryRef{{"assets", "category_id"}},
+	},
+	domain.CategoryKindLiability: {
+		name: "liability_categories",
+		refs: []categoryRef{{"liabilities", "category_id"}},
+	},
+	domain.CategoryKindTransaction: {
+		name: "transaction_categories",
+		refs: []categoryRef{{"transactions", "category_id"}, {"transaction_splits", "category_id"}, {"categorization_rules", "set_category_id"}},
+	},
+}
+
+type categoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewCategoryRepository(db *sqlx.DB) domain.CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func lookupCategoryTable(kind string) (categoryTable, error) {
+	table, ok := categoryTables[kind]
+	if !ok {
+		return categoryTable{}, fmt.Errorf("unknown category kind %q", kind)
+	}
+	return table, nil
+}
+
+// categoryColumns is the select list of a category table; flat tables have no
+// parent_id column so it is filled with NULL, and the zakat and tax flags are
+// only filled in for the kinds they apply to.
+func categoryColumns(kind string) string {
+	switch kind {
+	case domain.CategoryKindAsset:
+		return `id, user_id, NULL::uuid AS parent_id, name, base_type, is_archived,
+			COALESCE(is_zakatable, base_type <> 'physical') AS is_zakatable, NULL::boolean AS is_taxable`
+	case domain.CategoryKindTransaction:
+		return `id, user_id, parent_id, name, base_type, is_archived,
+			NULL::boolean AS is_zakatable, CASE WHEN base_type = 'income' THEN is_taxable END AS is_taxable`
+	}
+	return `id, user_id, NULL::uuid AS parent_id, name, base_type, is_archived, NULL::boolean AS is_zakatable, NULL::boolean AS is_taxable`
+}
+
+func (r *categoryRepository) List(ctx context.Context, req *domain.ListCategoryRequest) (*[]domain.Category, error) {
+	table, err := lookupCategoryTable(req.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	db := getQueryer(ctx, r.db)
+	var categories = make([]domain.Category, 0)
+	query := `SELECT ` + categoryColumns(req.Kind) + ` FROM ` + table.name + ` WHERE user_id = $1`
+	args := []interface{}{req.UserID}
+
+	if !req.IncludeArchived {
+		query += ` AND is_archived = FALSE`
+	}
+
+	if req.BaseType != "" {
+		args = append(args, req.BaseType)
+		query += fmt.Sprintf(` AND base_type::text = $%d`, len(args))
+	}
+
+	if req.Search != "" {
+		args = append(args, "%"+req.Search+"%")
+		query += fmt.Sprintf(` AND name ILIKE $%d`, len(args))
+	}
+
+	query += ` ORDER BY name ASC`
+
+	err = db.SelectContext(ctx, &categories, query, args...)
+
+	return &categories, err
+}
+
+func (r *categoryRepository) GetByID(ctx context.Context, kind string, id, userID uuid.UUID) (*domain.Category, error) {
+	table, err := lookupCategoryTable(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	db := getQueryer(ctx, r.db)
+	var category domain.Category
+	query := `SELECT ` + categoryColumns(kind) + ` FROM ` + table.name + ` WHERE id = $1 AND user_id = $2`
+	err = db.GetContext(ctx, &category, query, id, userID)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(constant.ErrNotFound)
+	}
+
+	return &category, err
+}
+
+func (r *categoryRepository) Insert(ctx context.Context, req *domain.CreateCategory) (*domain.Category, error) {
+	table, err := lookupCategoryTable(req.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	db := getQueryer(ctx, r.db)
+	var category domain.Category
+	query := `
+		INSERT INTO ` + table.name + ` (user_id, name, base_type)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, name, base_type) DO NOTHING
+		RETURNING ` + categoryColumns(req.Kind)
+	args := []interface{}{req.UserID, req.Name, req.BaseType}
+
+	if domain.CategoryHierarchical(req.Kind) {
+		query = `
+			INSERT INTO ` + table.name + ` (user_id, name, base_type, parent_id)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id, name, base_type) DO NOTHING
+			RETURNING ` + categoryColumns(req.Kind)
+		args = append(args, req.ParentID)
+	}
+
+	err = db.GetContext(ctx, &category, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(constant.ErrCategoryExists)
+	}
+
+	return &category, err
+}
+
+func (r *categoryRepository) Update(ctx context.Context, req *domain.UpdateCategory) (*domain.Category, error) {
+	table, err := lookupCategoryTable(req.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	db := getQueryer(ctx, r.db)
+	var category domain.Category
+	setClause := `name = $3, is_archived = $4`
+	args := []interface{}{req.ID, req.UserID, req.Name, *req.IsArchived}
+
+	if domain.CategoryHierarchical(req.Kind) {
+		setClause += `, parent_id = $5`
+		args = append(args, req.ParentID)
+	}
+
+	if req.Kind == domain.CategoryKindAsset && req.IsZakatable != nil {
+		args = append(args, *req.IsZakatable)
+		setClause += fmt.Sprintf(`, is_zakatable = $%d`, len(args))
+	}
+
+	if req.Kind == domain.CategoryKindTransaction && req.IsTaxable != nil {
+		args = append(args, *req.IsTaxable)
+		setClause += fmt.Sprintf(`, is_taxable = $%d`, len(args))
+	}
+
+	query := `
+		UPDATE ` + table.name + ` SET ` + setClause + `
+		WHERE id = $1 AND user_id = $2
+		RETURNING ` + categoryColumns(req.Kind)
+	err = db.GetContext(ctx, &category, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(constant.ErrNotFound)
+	}
+
+	if err != nil && strings.Contains(err.Error(), "duplicate key value") {
+		return nil, errors.New(constant.ErrCategoryExists)
+	}
+
+	return &category, err
+}
+
+func (r *categoryRepository) Delete(ctx context.Context, kind string, id, userID uuid.UUID) error {
+	table, err := lookupCategoryTable(kind)
+	if err != nil {
+		return err
+	}
+
+	db := getQueryer(ctx, r.db)
+	query := `DELETE FROM ` + table.name + ` WHERE id = $1 AND user_id = $2`
+	res, err := db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "foreign key constraint") {
+			return errors.New("violates foreign key constraint")
+		}
+		return err
+	}
+
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return errors.New(constant.ErrNotFound)
+	}
+
+	return nil
+}
+
+// IsDescendant reports whether id sits anywhere below ancestorID in the tree.
+func (r *categoryRepository) IsDescendant(ctx context.Context, kind string, id, ancestorID uuid.UUID) (bool, error) {
+	table, err := lookupCategoryTable(kind)
+	if err != nil {
+		return false, err
+	}
+
+	if !domain.CategoryHierarchical(kind) {
+		return false, nil
+	}
+
+	db := getQueryer(ctx, r.db)
+	var found bool
+	query := `
+		WITH RECURSIVE descendants AS (
+			SELECT id FROM ` + table.name + ` WHERE parent_id = $1
+			UNION
+			SELECT c.id FROM ` + table.name + ` c JOIN descendants d ON c.parent_id = d.id
+		)
+		SELECT EXISTS (SELECT 1 FROM descendants WHERE id = $2)
+	`
+	err = db.QueryRowContext(ctx, query, ancestorID, id).Scan(&found)
+
+	return found, err
+}
+
+// Merge repoints every row referencing sourceID to targetID, moves the source's
+// subcategories under the target and deletes the source category. Ownership of
+// both categories must be checked by the caller.
+func (r *categoryRepository) Merge(ctx context.Context, kind string, sourceID, targetID uuid.UUID) (int64, error) {
+	table, err := lookupCategoryTable(kind)
+	if err != nil {
+		return 0, err
+	}
+
+	db := getQueryer(ctx, r.db)
+	var moved int64
+	for _, ref := range table.refs {
+		query := `UPDATE ` + ref.table + ` SET ` + ref.column + ` = $2 WHERE ` + ref.column + ` = $1`
+		res, err := db.ExecContext(ctx, query, sourceID, targetID)
+		if err != nil {
+			return 0, err
+		}
+
+		rows, _ := res.RowsAffected()
+		moved += rows
+	}
+
+	if domain.CategoryHierarchical(kind) {
+		query := `UPDATE ` + table.name + ` SET parent_id = $2 WHERE parent_id = $1`
+		if _, err := db.ExecContext(ctx, query, sourceID, targetID); err != nil {
+			return 0, err
+		}
+	}
+
+	query := `DELETE FROM ` + table.name + ` WHERE id = $1`
+	if _, err := db.ExecContext(ctx, query, sourceID); err != nil {
+		return 0, err
+	}
+
+	return moved, nil
+}
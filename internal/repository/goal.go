@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type goalRepository struct {
+	db *sqlx.DB
+}
+
+func NewGoalRepository(db *sqlx.DB) domain.GoalRepository {
+	return &goalRepository{db: db}
+}
+
+const goalColumns = `g.id, g.workspace_id, g.user_id, g.name, g.target_amount, g.target_date, g.created_at`
+
+func (r *goalRepository) List(ctx context.Context, userID uuid.UUID) (*[]domain.SavingsGoal, error) {
+	db := getQueryer(ctx, r.db)
+	var goals = make([]domain.SavingsGoal, 0)
+	query := `
+		SELECT ` + goalColumns + `
+		FROM savings_goals g
+		WHERE g.workspace_id = $2
+			AND ` + memberOf("g.workspace_id", "$1", false) + `
+		ORDER BY g.target_date ASC, g.created_at ASC
+	`
+	err := db.SelectContext(ctx, &goals, query, userID, workspaceFromContext(ctx, userID))
+
+	return &goals, err
+}
+
+func (r *goalRepository) GetByID(ctx context.Context, id, userID uuid.UUID, write bool) (*domain.SavingsGoal, error) {
+	db := getQueryer(ctx, r.db)
+	var goal domain.SavingsGoal
+	query := `
+		SELECT ` + goalColumns + `
+		FROM savings_goals g
+		WHERE g.id = $1
+			AND g.workspace_id = $3
+			AND ` + memberOf("g.workspace_id", "$2", write)
+	err := db.GetContext(ctx, &goal, query, id, userID, workspaceFromContext(ctx, userID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(constant.ErrNotFound)
+	}
+
+	return &goal, err
+}
+
+// ListAssets returns the assets linked to the given goals. The goals are
+// expected to be fetched through List or GetByID, which check membership.
+func (r *goalRepository) ListAssets(ctx context.Context, goalIDs []uuid.UUID) (*[]domain.GoalAsset, error) {
+	db := getQueryer(ctx, r.db)
+	var assets = make([]domain.GoalAsset, 0)
+	query := `
+		SELECT ga.goal_id, a.id AS asset_id, a.name, a.current_value
+		FROM savings_goal_assets ga
+		JOIN assets a ON a.id = ga.asset_id
+		WHERE ga.goal_id = ANY($1)
+		ORDER BY a.name ASC
+	`
+	err := db.SelectContext(ctx, &assets, query, pq.Array(goalIDs))
+
+	return &assets, err
+}
+
+// ListContributions sums, per goal, what the transactions on its linked assets
+// moved into them between from and to, booked as applyCashflowEffect does:
+// an expense adds to the asset and an income takes from it.
+func (r *goalRepository) ListContributions(ctx context.Context, goalIDs []uuid.UUID, from, to time.Time) (*[]domain.GoalContribution, error) {
+	db := getQueryer(ctx, r.db)
+	var contributions = make([]domain.GoalContribution, 0)
+	query := `
+		SELECT ga.goal_id,
+			SUM(CASE WHEN tc.base_type = 'income' THEN -t.amount ELSE t.amount END) AS amount,
+			CAST(DATE_TRUNC('month', CAST(MIN(t.transaction_date) AS timestamp)) AS date) AS first_month
+		FROM savings_goal_assets ga
+		JOIN transactions t ON t.asset_id = ga.asset_id
+		JOIN transaction_categories tc ON tc.id = t.category_id
+		WHERE ga.goal_id = ANY($1)
+			AND t.transaction_date BETWEEN $2 AND $3
+		GROUP BY ga.goal_id
+	`
+	err := db.SelectContext(ctx, &contributions, query, pq.Array(goalIDs), from.Format(time.DateOnly), to.Format(time.DateOnly))
+
+	return &contributions, err
+}
+
+func (r *goalRepository) Insert(ctx context.Context, req *domain.CreateGoal, targetDate time.Time) (uuid.UUID, error) {
+	db := getQueryer(ctx, r.db)
+	workspaceID := workspaceFromContext(ctx, req.UserID)
+	if err := authorizeWorkspace(ctx, db, workspaceID, req.UserID, true); err != nil {
+		return uuid.Nil, err
+	}
+
+	query := `
+		INSERT INTO savings_goals (workspace_id, user_id, name, target_amount, target_date)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	var id uuid.UUID
+	err := db.QueryRowContext(ctx, query,
+		workspaceID, req.UserID, req.Name, *req.TargetAmount, targetDate.Format(time.DateOnly),
+	).Scan(&id)
+
+	return id, err
+}
+
+func (r *goalRepository) Update(ctx context.Context, req *domain.CreateGoal, targetDate time.Time) error {
+	db := getQueryer(ctx, r.db)
+	query := `
+		UPDATE savings_goals g
+		SET name = $4, target_amount = $5, target_date = $6, updated_at = now()
+		WHERE g.id = $1
+			AND g.workspace_id = $3
+			AND ` + memberOf("g.workspace_id", "$2", true)
+	res, err := db.ExecContext(ctx, query,
+		req.ID, req.UserID, workspaceFromContext(ctx, req.UserID),
+		req.Name, *req.TargetAmount, targetDate.Format(time.DateOnly),
+	)
+	if err != nil {
+		return err
+	}
+
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return errors.New(constant.ErrNotFound)
+	}
+
+	return nil
+}
+
+// SetAssets replaces the linked assets of a goal.
+func (r *goalRepository) SetAssets(ctx context.Context, goalID uuid.UUID, assetIDs []uuid.UUID) error {
+	db := getQueryer(ctx, r.db)
+
+	_, err := db.ExecContext(ctx, `DELETE FROM savings_goal_assets WHERE goal_id = $1`, goalID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO savings_goal_assets (goal_id, asset_id)
+		SELECT $1, UNNEST(CAST($2 AS uuid[]))
+		ON CONFLICT DO NOTHING
+	`
+	_, err = db.ExecContext(ctx, query, goalID, pq.Array(assetIDs))
+
+	return err
+}
+
+func (r *goalRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	db := getQueryer(ctx, r.db)
+	query := `DELETE FROM savings_goals g WHERE g.id = $1 AND g.workspace_id = $3 AND ` + memberOf("g.workspace_id", "$2", true)
+	res, err := db.ExecContext(ctx, query, id, userID, workspaceFromContext(ctx, userID))
+	if err != nil {
+		return err
+	}
+
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return errors.New(constant.ErrNotFound)
+	}
+
+	return nil
+}
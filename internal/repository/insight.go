@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type insightRepository struct {
+	db *sqlx.DB
+}
+
+func NewInsightRepository(db *sqlx.DB) domain.InsightRepository {
+	return &insightRepository{db: db}
+}
+
+func (r *insightRepository) ListWorkspaceIDs(ctx context.Context) ([]uuid.UUID, error) {
+	db := getQueryer(ctx, r.db)
+	var ids = make([]uuid.UUID, 0)
+	err := db.SelectContext(ctx, &ids, `SELECT id FROM workspaces ORDER BY id`)
+
+	return ids, err
+}
+
+// ListCategoryMonthSpend totals the expense lines of the workspace per category
+// and month, starting with the month of from.
+func (r *insightRepository) ListCategoryMonthSpend(ctx context.Context, workspaceID uuid.UUID, from time.Time) (*[]domain.CategoryMonthSpend, error) {
+	db := getQueryer(ctx, r.db)
+	var spend = make([]domain.CategoryMonthSpend, 0)
+	query := `
+		SELECT tl.category_id, tc.name AS category_name,
+			CAST(DATE_TRUNC('month', CAST(t.transaction_date AS timestamp)) AS date) AS month,
+			SUM(tl.amount) AS amount
+		FROM transactions t
+		JOIN transaction_lines tl ON tl.transaction_id = t.id
+		JOIN transaction_categories tc ON tc.id = tl.category_id
+		WHERE t.workspace_id = $1
+			AND tc.base_type = 'expense'
+			AND t.transaction_date >= DATE_TRUNC('month', CAST($2 AS date))
+		GROUP BY tl.category_id, tc.name, month
+		ORDER BY tl.category_id, month
+	`
+	err := db.SelectContext(ctx, &spend, query, workspaceID, from.Format(time.DateOnly))
+
+	return &spend, err
+}
+
+// ListExpenses lists the expense transactions of the workspace since from,
+// oldest first.
+func (r *insightRepository) ListExpenses(ctx context.Context, workspaceID uuid.UUID, from time.Time) (*[]domain.ExpenseEntry, error) {
+	db := getQueryer(ctx, r.db)
+	var expenses = make([]domain.ExpenseEntry, 0)
+	query := `
+		SELECT t.id, t.category_id, tc.name AS category_name, t.amount, t.transaction_date, t.notes
+		FROM transactions t
+		JOIN transaction_categories tc ON tc.id = t.category_id
+		WHERE t.workspace_id = $1
+			AND tc.base_type = 'expense'
+			AND t.transaction_date >= $2
+		ORDER BY t.transaction_date, t.created_at
+	`
+	err := db.SelectContext(ctx, &expenses, query, workspaceID, from.Format(time.DateOnly))
+
+	return &expenses, err
+}
+
+// ListLiquidAssetChanges returns the active liquid assets of the workspace
+// with the net effect of the transactions booked on them after since, signed
+// the way the transaction usecase applies them.
+func (r *insightRepository) ListLiquidAssetChanges(ctx context.Context, workspaceID uuid.UUID, since time.Time) (*[]domain.AssetChange, error) {
+	db := getQueryer(ctx, r.db)
+	var changes = make([]domain.AssetChange, 0)
+	query := `
+		SELECT a.id AS asset_id, a.name, a.current_value,
+			COALESCE((
+				SELECT SUM(CASE WHEN tc.base_type = 'income' THEN -t.amount ELSE t.amount END)
+				FROM transactions t
+				JOIN transaction_categories tc ON tc.id = t.category_id
+				WHERE t.asset_id = a.id AND t.transaction_date > $2
+			), 0) AS change
+		FROM assets a
+		JOIN asset_categories ac ON ac.id = a.category_id
+		WHERE a.workspace_id = $1
+			AND a.is_active = TRUE
+			AND ac.base_type = 'liquid'
+	`
+	err := db.SelectContext(ctx, &changes, query, workspaceID, since.Format(time.DateOnly))
+
+	return &changes, err
+}
+
+// Replace swaps the stored insights of a workspace for a freshly computed set.
+func (r *insightRepository) Replace(ctx context.Context, workspaceID uuid.UUID, insights []domain.Insight) error {
+	db := getQueryer(ctx, r.db)
+
+	_, err := db.ExecContext(ctx, `DELETE FROM insights WHERE workspace_id = $1`, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	if len(insights) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO insights (workspace_id, type, subject_id, subject_name, message, amount, baseline, score, insight_date)
+		VALUES (:workspace_id, :type, :subject_id, :subject_name, :message, :amount, :baseline, :score, :insight_date)
+	`
+	_, err = db.NamedExecContext(ctx, query, insights)
+
+	return err
+}
+
+// List returns the stored insights of the current workspace, newest first.
+func (r *insightRepository) List(ctx context.Context, userID uuid.UUID) (*[]domain.Insight, error) {
+	db := getQueryer(ctx, r.db)
+	var insights = make([]domain.Insight, 0)
+	query := `
+		SELECT id, workspace_id, type, subject_id, subject_name, message, amount, baseline, score, insight_date, created_at
+		FROM insights
+		WHERE workspace_id = $2
+			AND ` + memberOf("insights.workspace_id", "$1", false) + `
+		ORDER BY insight_date DESC, score DESC
+	`
+	err := db.SelectContext(ctx, &insights, query, userID, workspaceFromContext(ctx, userID))
+
+	return &insights, err
+}
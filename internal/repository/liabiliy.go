@@ -22,26 +22,27 @@ func NewLiabilityRepository(db *sqlx.DB) domain.LiabilityRepository {
 	return &liabilityRepository{db: db}
 }
 
-func (r *liabilityRepository) ListCategory(ctx context.Context, userId uuid.UUID) (*[]domain.Category, error) {
-	db := getQueryer(ctx, r.db)
-	var categories = make([]domain.Category, 0)
-	query := `SELECT id, name, base_type FROM liability_categories WHERE user_id = $1 ORDER BY name ASC`
-	err := db.SelectContext(ctx, &categories, query, userId)
-
-	return &categories, err
+// liabilityKeyset is the order of cursor paginated liability lists, newest first.
+var liabilityKeyset = pkg.Keyset{
+	Columns: []pkg.KeysetColumn{
+		{Expr: "liabilities.created_at", Type: "timestamptz"},
+		{Expr: "liabilities.id", Type: "uuid"},
+	},
+	Desc: true,
 }
 
-func (r *liabilityRepository) List(ctx context.Context, req *domain.ListLiabilityRequest) (*[]domain.Liability, int, error) {
+func (r *liabilityRepository) List(ctx context.Context, req *domain.ListLiabilityRequest) (*[]domain.Liability, pkg.Page, error) {
 	db := getQueryer(ctx, r.db)
 	var liabilities = make([]domain.Liability, 0)
 	var total int
 	var defaultSort = "created_at desc"
 	query := `
-		SELECT liabilities.id, liabilities.user_id, lc.name as category, liabilities.name, liabilities.remaining_balance, liabilities.details,
-			liabilities.created_at
+		SELECT liabilities.id, liabilities.user_id, liabilities.workspace_id, lc.name as category, liabilities.name,
+			liabilities.remaining_balance, liabilities.details, liabilities.created_at
 		FROM liabilities 
-		join liability_categories lc on lc.id = liabilities.category_id and lc.user_id = liabilities.user_id
-		WHERE liabilities.user_id = :user_id
+		join liability_categories lc on lc.id = liabilities.category_id
+		WHERE liabilities.workspace_id = :workspace_id
+			AND ` + memberOf("liabilities.workspace_id", ":user_id", false) + `
 	`
 
 	if req.Name != "" {
@@ -60,6 +61,18 @@ func (r *liabilityRepository) List(ctx context.Context, req *domain.ListLiabilit
 		}
 	}
 
+	if req.UseCursor() {
+		liabilities, page, err := selectKeysetPage(ctx, db, query, map[string]interface{}{
+			"user_id":      req.UserId,
+			"workspace_id": workspaceFromContext(ctx, req.UserId),
+			"name":         "%" + req.Name + "%",
+			"category":     "%" + req.Category + "%",
+		}, liabilityKeyset, &req.PaginationRequest, func(liability *domain.Liability) []string {
+			return []string{cursorTime(liability.CreatedAt), liability.ID.String()}
+		})
+		return &liabilities, page, err
+	}
+
 	if req.Sort == nil {
 		req.Sort = &defaultSort
 	}
@@ -72,9 +85,10 @@ func (r *liabilityRepository) List(ctx context.Context, req *domain.ListLiabilit
 	go func() {
 		defer wg.Done()
 		resCount, err := db.NamedQueryContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM (%s) as count_query", query), map[string]interface{}{
-			"user_id":  req.UserId,
-			"name":     "%" + req.Name + "%",
-			"category": "%" + req.Category + "%",
+			"user_id":      req.UserId,
+			"workspace_id": workspaceFromContext(ctx, req.UserId),
+			"name":         "%" + req.Name + "%",
+			"category":     "%" + req.Category + "%",
 		})
 
 		if err != nil {
@@ -96,12 +110,13 @@ func (r *liabilityRepository) List(ctx context.Context, req *domain.ListLiabilit
 	go func() {
 		defer wg.Done()
 		res, err := pkg.SelectWithPagination(ctx, db, query, map[string]interface{}{
-			"page":     req.Page,
-			"limit":    req.Limit,
-			"sort":     req.Sort,
-			"user_id":  req.UserId,
-			"name":     "%" + req.Name + "%",
-			"category": "%" + req.Category + "%",
+			"page":         req.Page,
+			"limit":        req.Limit,
+			"sort":         req.Sort,
+			"user_id":      req.UserId,
+			"workspace_id": workspaceFromContext(ctx, req.UserId),
+			"name":         "%" + req.Name + "%",
+			"category":     "%" + req.Category + "%",
 		})
 
 		if err != nil {
@@ -128,24 +143,44 @@ func (r *liabilityRepository) List(ctx context.Context, req *domain.ListLiabilit
 
 	for err := range errChan {
 		if err != nil {
-			return nil, 0, err
+			return nil, pkg.Page{}, err
 		}
 	}
 
-	return &liabilities, total, nil
+	return &liabilities, pkg.Page{Total: total}, nil
 }
 
 func (r *liabilityRepository) Delete(ctx context.Context, id, userId uuid.UUID) error {
 	db := getQueryer(ctx, r.db)
-	query := `DELETE FROM liabilities WHERE id = $1 AND user_id = $2`
-	_, err := db.ExecContext(ctx, query, id, userId)
+	workspaceID := workspaceFromContext(ctx, userId)
+	if err := authorizeWorkspace(ctx, db, workspaceID, userId, true); err != nil {
+		return err
+	}
 
-	return err
+	query := `DELETE FROM liabilities WHERE id = $1 AND liabilities.workspace_id = $3 AND ` + memberOf("liabilities.workspace_id", "$2", true)
+	res, err := db.ExecContext(ctx, query, id, userId, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return errors.New(constant.ErrNotFound)
+	}
+
+	return nil
 }
 
 func (r *liabilityRepository) Insert(ctx context.Context, data *domain.LiabilityDB) error {
 	db := getQueryer(ctx, r.db)
-	query := `INSERT INTO liabilities (user_id, category_id, name, principal_amount, remaining_balance, details) VALUES (:user_id, :category_id, :name, :principal_amount, :remaining_balance, :details)`
+	data.WorkspaceID = workspaceFromContext(ctx, data.UserId)
+	if err := authorizeWorkspace(ctx, db, data.WorkspaceID, data.UserId, true); err != nil {
+		return err
+	}
+	if err := authorizeCategory(ctx, db, "liability_categories", "liabilities", uuid.Nil, data.CategoryID, data.WorkspaceID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO liabilities (user_id, workspace_id, category_id, name, principal_amount, remaining_balance, details) VALUES (:user_id, :workspace_id, :category_id, :name, :principal_amount, :remaining_balance, :details)`
 	_, err := db.NamedExecContext(ctx, query, data)
 
 	return err
@@ -153,23 +188,40 @@ func (r *liabilityRepository) Insert(ctx context.Context, data *domain.Liability
 
 func (r *liabilityRepository) Update(ctx context.Context, data *domain.LiabilityDB) error {
 	db := getQueryer(ctx, r.db)
-	query := `UPDATE liabilities SET name = :name, category_id = :category_id, principal_amount = :principal_amount, remaining_balance = :remaining_balance, details = :details, updated_at = now() WHERE id = :id AND user_id = :user_id`
-	_, err := db.NamedExecContext(ctx, query, data)
+	data.WorkspaceID = workspaceFromContext(ctx, data.UserId)
+	if err := authorizeWorkspace(ctx, db, data.WorkspaceID, data.UserId, true); err != nil {
+		return err
+	}
+	if err := authorizeCategory(ctx, db, "liability_categories", "liabilities", data.ID, data.CategoryID, data.WorkspaceID); err != nil {
+		return err
+	}
 
-	return err
+	query := `UPDATE liabilities SET name = :name, category_id = :category_id, principal_amount = :principal_amount, remaining_balance = :remaining_balance, details = :details, updated_at = now() WHERE id = :id AND liabilities.workspace_id = :workspace_id AND ` + memberOf("liabilities.workspace_id", ":user_id", true)
+	res, err := db.NamedExecContext(ctx, query, data)
+	if err != nil {
+		return err
+	}
+
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return errors.New(constant.ErrNotFound)
+	}
+
+	return nil
 }
 
 func (r *liabilityRepository) GetByID(ctx context.Context, id, userId uuid.UUID) (*domain.Liability, error) {
 	db := getQueryer(ctx, r.db)
 	var liability domain.Liability
 	query := `
-		SELECT liabilities.id, liabilities.user_id, liabilities.category_id, liabilities.name, 
+		SELECT liabilities.id, liabilities.user_id, liabilities.workspace_id, liabilities.category_id, liabilities.name, 
 			liabilities.principal_amount, liabilities.remaining_balance, liabilities.details, 
 			lc.name as category, lc.base_type as category_type
 		FROM liabilities
-		JOIN liability_categories lc ON liabilities.user_id = lc.user_id AND liabilities.category_id = lc.id
-		WHERE liabilities.id = $1 AND liabilities.user_id = $2`
-	err := db.GetContext(ctx, &liability, query, id, userId)
+		JOIN liability_categories lc ON liabilities.category_id = lc.id
+		WHERE liabilities.id = $1
+			AND liabilities.workspace_id = $3
+			AND ` + memberOf("liabilities.workspace_id", "$2", false)
+	err := db.GetContext(ctx, &liability, query, id, userId, workspaceFromContext(ctx, userId))
 	if err == sql.ErrNoRows {
 		return nil, errors.New(constant.ErrNotFound)
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/fazriegi/netbase-be/internal/domain"
 	"github.com/fazriegi/netbase-be/pkg/constant"
@@ -23,30 +24,50 @@ func (r *networthRepository) Calculate(ctx context.Context) error {
 	db := getQueryer(ctx, r.db)
 	query := `
 		WITH AssetSummary AS (
-			SELECT user_id, COALESCE(SUM(current_value), 0) AS total_assets
+			SELECT workspace_id, COALESCE(SUM(current_value), 0) AS total_assets
 			FROM assets 
 			WHERE is_active = TRUE 
-			GROUP BY user_id
+			GROUP BY workspace_id
 		),
 		LiabilitySummary AS (
-			SELECT user_id, COALESCE(SUM(remaining_balance), 0) AS total_liabilities
+			SELECT workspace_id, COALESCE(SUM(remaining_balance), 0) AS total_liabilities
 			FROM liabilities 
 			WHERE remaining_balance > 0 
-			GROUP BY user_id
+			GROUP BY workspace_id
+		),
+		-- zakatable assets net of the debts due, as the zakat estimate values them
+		ZakatableSummary AS (
+			SELECT a.workspace_id, COALESCE(SUM(a.current_value), 0) AS zakatable_assets
+			FROM assets a
+			JOIN asset_categories ac ON ac.id = a.category_id
+			WHERE a.is_active = TRUE AND COALESCE(ac.is_zakatable, ac.base_type <> 'physical')
+			GROUP BY a.workspace_id
+		),
+		DebtsDueSummary AS (
+			SELECT l.workspace_id, COALESCE(SUM(l.remaining_balance), 0) AS debts_due
+			FROM liabilities l
+			JOIN liability_categories lc ON lc.id = l.category_id
+			WHERE l.remaining_balance > 0 AND lc.base_type = 'short_term'
+			GROUP BY l.workspace_id
 		)
-		INSERT INTO net_worth_histories (user_id, total_assets, total_liabilities, recorded_date)
+		INSERT INTO net_worth_histories (user_id, workspace_id, total_assets, total_liabilities, zakatable_wealth, recorded_date)
 		SELECT 
-			U.id, 
+			W.owner_id, 
+			W.id,
 			COALESCE(A.total_assets, 0), 
 			COALESCE(L.total_liabilities, 0),
+			GREATEST(COALESCE(Z.zakatable_assets, 0) - COALESCE(D.debts_due, 0), 0),
 			CURRENT_DATE
-		FROM users U
-		LEFT JOIN AssetSummary A ON U.id = A.user_id
-		LEFT JOIN LiabilitySummary L ON U.id = L.user_id
-		ON CONFLICT (user_id, recorded_date) 
+		FROM workspaces W
+		LEFT JOIN AssetSummary A ON W.id = A.workspace_id
+		LEFT JOIN LiabilitySummary L ON W.id = L.workspace_id
+		LEFT JOIN ZakatableSummary Z ON W.id = Z.workspace_id
+		LEFT JOIN DebtsDueSummary D ON W.id = D.workspace_id
+		ON CONFLICT (workspace_id, recorded_date) 
 		DO UPDATE SET 
 			total_assets = EXCLUDED.total_assets,
 			total_liabilities = EXCLUDED.total_liabilities,
+			zakatable_wealth = EXCLUDED.zakatable_wealth,
 			updated_at = NOW();`
 
 	_, err := db.ExecContext(ctx, query)
@@ -61,17 +82,19 @@ func (r *networthRepository) GetCurrent(ctx context.Context, userId uuid.UUID) (
 		WITH realtime_assets AS (
 			SELECT COALESCE(SUM(current_value), 0) AS total_assets
 			FROM assets
-			WHERE user_id = $1 AND is_active = TRUE
+			WHERE assets.workspace_id = $2 AND is_active = TRUE
+				AND ` + memberOf("assets.workspace_id", "$1", false) + `
 		),
 		realtime_liabilities AS (
 			SELECT COALESCE(SUM(remaining_balance), 0) AS total_liabilities
 			FROM liabilities
-			WHERE user_id = $1 AND remaining_balance > 0
+			WHERE liabilities.workspace_id = $2 AND remaining_balance > 0
+				AND ` + memberOf("liabilities.workspace_id", "$1", false) + `
 		),
 		last_month_snapshot AS (
 			SELECT net_worth
 			FROM net_worth_histories
-			WHERE user_id = $1 
+			WHERE workspace_id = $2 
 			AND recorded_date < DATE_TRUNC('month', CURRENT_DATE)
 			ORDER BY recorded_date DESC
 			LIMIT 1
@@ -87,10 +110,92 @@ func (r *networthRepository) GetCurrent(ctx context.Context, userId uuid.UUID) (
 		FROM realtime_assets ra
 		CROSS JOIN realtime_liabilities rl
 		LEFT JOIN last_month_snapshot lms ON TRUE;`
-	err := db.GetContext(ctx, &networth, query, userId)
+	err := db.GetContext(ctx, &networth, query, userId, workspaceFromContext(ctx, userId))
 	if err == sql.ErrNoRows {
 		return nil, errors.New(constant.ErrNotFound)
 	}
 
 	return &networth, err
 }
+
+// GetHealthInputs sums the current workspace's active assets per base type,
+// its outstanding liabilities and the monthly installments of the long-term
+// ones, and the income and expense booked between from and to.
+func (r *networthRepository) GetHealthInputs(ctx context.Context, userID uuid.UUID, from, to time.Time) (*domain.HealthInputs, error) {
+	db := getQueryer(ctx, r.db)
+	var inputs domain.HealthInputs
+	query := `
+		WITH asset_totals AS (
+			SELECT
+				COALESCE(SUM(a.current_value) FILTER (WHERE ac.base_type = 'liquid'), 0) AS liquid_assets,
+				COALESCE(SUM(a.current_value) FILTER (WHERE ac.base_type = 'investment'), 0) AS investment_assets,
+				COALESCE(SUM(a.current_value) FILTER (WHERE ac.base_type = 'physical'), 0) AS physical_assets
+			FROM assets a
+			JOIN asset_categories ac ON ac.id = a.category_id
+			WHERE a.workspace_id = $2 AND a.is_active = TRUE
+				AND ` + memberOf("a.workspace_id", "$1", false) + `
+		),
+		liability_totals AS (
+			SELECT
+				COALESCE(SUM(l.remaining_balance) FILTER (WHERE lc.base_type = 'short_term'), 0) AS short_term_liabilities,
+				COALESCE(SUM(l.remaining_balance) FILTER (WHERE lc.base_type = 'long_term'), 0) AS long_term_liabilities,
+				COALESCE(SUM(CAST(NULLIF(l.details->>'monthly_installment', '') AS numeric)) FILTER (WHERE lc.base_type = 'long_term'), 0) AS monthly_installments
+			FROM liabilities l
+			JOIN liability_categories lc ON lc.id = l.category_id
+			WHERE l.workspace_id = $2 AND l.remaining_balance > 0
+				AND ` + memberOf("l.workspace_id", "$1", false) + `
+		),
+		cashflow AS (
+			SELECT
+				COALESCE(SUM(tl.amount) FILTER (WHERE tc.base_type = 'income'), 0) AS income,
+				COALESCE(SUM(tl.amount) FILTER (WHERE tc.base_type = 'expense'), 0) AS expense,
+				COUNT(DISTINCT DATE_TRUNC('month', CAST(t.transaction_date AS timestamp))) AS active_months
+			FROM transactions t
+			JOIN transaction_lines tl ON tl.transaction_id = t.id
+			JOIN transaction_categories tc ON tc.id = tl.category_id
+			WHERE t.workspace_id = $2 AND t.transaction_date BETWEEN $3 AND $4
+				AND ` + memberOf("t.workspace_id", "$1", false) + `
+		)
+		SELECT * FROM asset_totals, liability_totals, cashflow`
+	err := db.GetContext(ctx, &inputs, query, userID, workspaceFromContext(ctx, userID), from.Format(time.DateOnly), to.Format(time.DateOnly))
+
+	return &inputs, err
+}
+
+// ListAssetCategoryBalances sums the current workspace's active assets per category.
+func (r *networthRepository) ListAssetCategoryBalances(ctx context.Context, userID uuid.UUID) (*[]domain.AssetCategoryBalance, error) {
+	db := getQueryer(ctx, r.db)
+	var balances = make([]domain.AssetCategoryBalance, 0)
+	query := `
+		SELECT ac.id AS category_id, ac.name, CAST(ac.base_type AS text) AS base_type, SUM(a.current_value) AS value
+		FROM assets a
+		JOIN asset_categories ac ON ac.id = a.category_id
+		WHERE a.workspace_id = $2 AND a.is_active = TRUE
+			AND ` + memberOf("a.workspace_id", "$1", false) + `
+		GROUP BY ac.id, ac.name, ac.base_type
+		ORDER BY ac.base_type, ac.name
+	`
+	err := db.SelectContext(ctx, &balances, query, userID, workspaceFromContext(ctx, userID))
+
+	return &balances, err
+}
+
+// ListLiabilityBalances returns the current workspace's outstanding liabilities
+// with the installment and interest rate of the long-term ones.
+func (r *networthRepository) ListLiabilityBalances(ctx context.Context, userID uuid.UUID) (*[]domain.LiabilityBalance, error) {
+	db := getQueryer(ctx, r.db)
+	var balances = make([]domain.LiabilityBalance, 0)
+	query := `
+		SELECT l.id, l.name, CAST(lc.base_type AS text) AS base_type, l.remaining_balance,
+			COALESCE(CAST(NULLIF(l.details->>'monthly_installment', '') AS numeric), 0) AS monthly_installment,
+			COALESCE(CAST(NULLIF(l.details->>'interest_rate_pa', '') AS numeric), 0) AS interest_rate_pa
+		FROM liabilities l
+		JOIN liability_categories lc ON lc.id = l.category_id
+		WHERE l.workspace_id = $2 AND l.remaining_balance > 0
+			AND ` + memberOf("l.workspace_id", "$1", false) + `
+		ORDER BY l.name
+	`
+	err := db.SelectContext(ctx, &balances, query, userID, workspaceFromContext(ctx, userID))
+
+	return &balances, err
+}
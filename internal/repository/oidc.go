@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/jmoiron/sqlx"
+)
+
+type oidcRepository struct {
+	db *sqlx.DB
+}
+
+func NewOIDCRepository(db *sqlx.DB) domain.OIDCRepository {
+	return &oidcRepository{db: db}
+}
+
+func (r *oidcRepository) InsertState(ctx context.Context, data *domain.OIDCLoginState) error {
+	db := getQueryer(ctx, r.db)
+
+	// drop abandoned logins while we're here
+	if _, err := db.ExecContext(ctx, `DELETE FROM oidc_login_states WHERE expires_at < now()`); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO oidc_login_states (state, provider, nonce, code_verifier, expires_at)
+		VALUES (:state, :provider, :nonce, :code_verifier, :expires_at)
+	`
+	_, err := db.NamedExecContext(ctx, query, data)
+
+	return err
+}
+
+// ConsumeState deletes and returns a non-expired login state, so each state can be used only once.
+func (r *oidcRepository) ConsumeState(ctx context.Context, state string) (*domain.OIDCLoginState, error) {
+	db := getQueryer(ctx, r.db)
+	var loginState domain.OIDCLoginState
+	query := `
+		DELETE FROM oidc_login_states
+		WHERE state = $1 AND expires_at > now()
+		RETURNING state, provider, nonce, code_verifier, expires_at
+	`
+	err := db.GetContext(ctx, &loginState, query, state)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(constant.ErrNotFound)
+	}
+
+	return &loginState, err
+}
+
+func (r *oidcRepository) GetIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	db := getQueryer(ctx, r.db)
+	var identity domain.UserIdentity
+	query := `SELECT user_id, provider, subject, COALESCE(email, '') as email FROM user_identities WHERE provider = $1 AND subject = $2`
+	err := db.GetContext(ctx, &identity, query, provider, subject)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(constant.ErrNotFound)
+	}
+
+	return &identity, err
+}
+
+func (r *oidcRepository) InsertIdentity(ctx context.Context, data *domain.UserIdentity) error {
+	db := getQueryer(ctx, r.db)
+	query := `
+		INSERT INTO user_identities (user_id, provider, subject, email)
+		VALUES (:user_id, :provider, :subject, :email)
+	`
+	_, err := db.NamedExecContext(ctx, query, data)
+
+	return err
+}
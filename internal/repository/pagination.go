@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fazriegi/netbase-be/pkg"
+)
+
+// cursorTime formats a timestamp key so Postgres casts it back without losing
+// the microseconds that keep equal-looking rows apart.
+func cursorTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+func countRows(ctx context.Context, db Queryer, query string, args map[string]interface{}) (int, error) {
+	var total int
+	rows, err := db.NamedQueryContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM (%s) as count_query", query), args)
+	if err != nil {
+		return 0, fmt.Errorf("error counting data: %v", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, fmt.Errorf("error scanning count: %v", err)
+		}
+	}
+
+	return total, rows.Err()
+}
+
+// selectKeysetPage fetches one keyset page of query, which must end inside its
+// WHERE clause. Unlike offset pages, the total is only counted on request.
+func selectKeysetPage[T any](
+	ctx context.Context,
+	db Queryer,
+	query string,
+	args map[string]interface{},
+	keyset pkg.Keyset,
+	req *pkg.PaginationRequest,
+	key func(*T) []string,
+) ([]T, pkg.Page, error) {
+	var cursor *pkg.Cursor
+	if req.Cursor != nil {
+		c, err := pkg.DecodeCursor(*req.Cursor, keyset)
+		if err != nil {
+			return nil, pkg.Page{}, err
+		}
+		cursor = c
+	}
+
+	var total int
+	if req.WithTotal {
+		var err error
+		if total, err = countRows(ctx, db, query, args); err != nil {
+			return nil, pkg.Page{}, err
+		}
+	}
+
+	limit := req.CursorLimit()
+	rows, err := pkg.SelectWithKeyset(ctx, db, query, args, keyset, cursor, limit)
+	if err != nil {
+		return nil, pkg.Page{}, fmt.Errorf("error fetching data: %v", err)
+	}
+	defer rows.Close()
+
+	items := make([]T, 0, limit+1)
+	for rows.Next() {
+		var item T
+		if err := rows.StructScan(&item); err != nil {
+			return nil, pkg.Page{}, fmt.Errorf("error scanning data: %v", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkg.Page{}, err
+	}
+
+	items, page := pkg.KeysetPage(items, limit, cursor, key)
+	page.Total = total
+
+	return items, page, nil
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type reportRepository struct {
+	db           *sqlx.DB
+	transactions *transactionRepository
+}
+
+func NewReportRepository(db *sqlx.DB) domain.ReportRepository {
+	return &reportRepository{db: db, transactions: &transactionRepository{db: db}}
+}
+
+// GetCashflow totals the matching transaction lines per period and base type,
+// or per period and category, in one pass. Every period of the window gets a
+// row for every key, zero when nothing was booked.
+func (r *reportRepository) GetCashflow(ctx context.Context, req *domain.CashflowRequest) (*[]domain.CashflowRow, error) {
+	db := getQueryer(ctx, r.db)
+	var rows = make([]domain.CashflowRow, 0)
+
+	byCategory := req.By == "category"
+
+	lineCategory := `CAST(NULL AS uuid)`
+	keys := `SELECT CAST(NULL AS uuid) AS category_id, CAST(t AS transaction_type) AS base_type FROM unnest(ARRAY['income', 'expense']) t`
+	if byCategory {
+		lineCategory = `tl.category_id`
+		keys = `SELECT DISTINCT category_id, base_type FROM lines`
+	}
+
+	query := `
+		WITH lines AS (
+			SELECT
+				CAST(DATE_TRUNC(CAST(:group_by AS text), CAST(transactions.transaction_date AS timestamp)) AS date) AS period,
+				` + lineCategory + ` AS category_id,
+				tc.base_type,
+				SUM(tl.amount) AS amount
+			FROM transactions
+			JOIN transaction_lines tl ON tl.transaction_id = transactions.id
+			JOIN transaction_categories tc ON tc.id = tl.category_id
+			WHERE transactions.workspace_id = :workspace_id
+				AND ` + memberOf("transactions.workspace_id", ":user_id", false) +
+		r.transactions.transactionFilter(&req.ListTransactionRequest, true) + `
+			GROUP BY 1, 2, 3
+		), periods AS (
+			SELECT CAST(p AS date) AS period
+			FROM generate_series(
+				DATE_TRUNC(CAST(:group_by AS text), CAST(CAST(:start_date AS date) AS timestamp)),
+				CAST(CAST(:end_date AS date) AS timestamp),
+				CAST('1 ' || :group_by AS interval)
+			) p
+		), keys AS (
+			` + keys + `
+		)
+		SELECT periods.period, keys.category_id, c.name AS category_name, keys.base_type, COALESCE(lines.amount, 0) AS amount
+		FROM periods
+		LEFT JOIN keys ON TRUE
+		LEFT JOIN lines ON lines.period = periods.period
+			AND lines.base_type = keys.base_type
+			AND lines.category_id IS NOT DISTINCT FROM keys.category_id
+		LEFT JOIN transaction_categories c ON c.id = keys.category_id
+		ORDER BY periods.period ASC, keys.base_type ASC, c.name ASC
+	`
+
+	args := r.transactions.transactionFilterArgs(ctx, &req.ListTransactionRequest)
+	args["group_by"] = req.GroupBy
+
+	result, err := db.NamedQueryContext(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+
+	for result.Next() {
+		var row domain.CashflowRow
+		if err := result.StructScan(&row); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+
+	return &rows, result.Err()
+}
+
+// GetIncomeStatement totals the matching transaction lines per category.
+func (r *reportRepository) GetIncomeStatement(ctx context.Context, req *domain.ListTransactionRequest) (*[]domain.StatementRow, error) {
+	db := getQueryer(ctx, r.db)
+	var rows = make([]domain.StatementRow, 0)
+
+	query := `
+		SELECT CAST(tc.base_type AS text) AS base_type, tc.id AS category_id, tc.name AS category, tc.name AS name, SUM(tl.amount) AS amount
+		FROM transactions
+		JOIN transaction_lines tl ON tl.transaction_id = transactions.id
+		JOIN transaction_categories tc ON tc.id = tl.category_id
+		WHERE transactions.workspace_id = :workspace_id
+			AND ` + memberOf("transactions.workspace_id", ":user_id", false) +
+		r.transactions.transactionFilter(req, true) + `
+		GROUP BY tc.id, tc.base_type, tc.name
+		ORDER BY tc.base_type ASC, amount DESC, tc.name ASC
+	`
+
+	result, err := db.NamedQueryContext(ctx, query, r.transactions.transactionFilterArgs(ctx, req))
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+
+	for result.Next() {
+		var row domain.StatementRow
+		if err := result.StructScan(&row); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+
+	return &rows, result.Err()
+}
+
+// GetBalanceSheet lists the assets and liabilities of the current workspace as
+// of the end of date. Only current values are stored, so for a past date the
+// transactions booked after it are rolled back the way they were applied;
+// market value changes in between can't be recovered and are not reflected.
+// Assets deactivated since then are still listed.
+func (r *reportRepository) GetBalanceSheet(ctx context.Context, userID uuid.UUID, date time.Time) (*[]domain.StatementRow, error) {
+	db := getQueryer(ctx, r.db)
+	var rows = make([]domain.StatementRow, 0)
+
+	query := `
+		SELECT * FROM (
+			SELECT 'asset' AS kind, CAST(ac.base_type AS text) AS base_type, ac.id AS category_id, ac.name AS category, a.name,
+				a.current_value - COALESCE((
+					SELECT SUM(CASE WHEN tc.base_type = 'income' THEN -t.amount ELSE t.amount END)
+					FROM transactions t
+					JOIN transaction_categories tc ON tc.id = t.category_id
+					WHERE t.asset_id = a.id AND t.transaction_date > $3
+				), 0) AS amount
+			FROM assets a
+			JOIN asset_categories ac ON ac.id = a.category_id
+			WHERE a.workspace_id = $2
+				AND (a.is_active = TRUE OR CAST(a.deactivated_at AS date) > $3)
+				AND CAST(a.created_at AS date) <= $3
+				AND ` + memberOf("a.workspace_id", "$1", false) + `
+			UNION ALL
+			SELECT 'liability' AS kind, CAST(lc.base_type AS text) AS base_type, lc.id AS category_id, lc.name AS category, l.name,
+				l.remaining_balance - COALESCE((
+					SELECT SUM(CASE WHEN tc.base_type = 'income' THEN t.amount ELSE -t.amount END)
+					FROM transactions t
+					JOIN transaction_categories tc ON tc.id = t.category_id
+					WHERE t.liability_id = l.id AND t.transaction_date > $3
+				), 0) AS amount
+			FROM liabilities l
+			JOIN liability_categories lc ON lc.id = l.category_id
+			WHERE l.workspace_id = $2
+				AND CAST(l.created_at AS date) <= $3
+				AND ` + memberOf("l.workspace_id", "$1", false) + `
+		) AS sheet
+		WHERE kind = 'asset' OR amount > 0
+		ORDER BY kind ASC, base_type ASC, category ASC, name ASC
+	`
+	err := db.SelectContext(ctx, &rows, query, userID, workspaceFromContext(ctx, userID), date.Format(time.DateOnly))
+
+	return &rows, err
+}
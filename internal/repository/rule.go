@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ruleRepository struct {
+	db *sqlx.DB
+}
+
+func NewRuleRepository(db *sqlx.DB) domain.RuleRepository {
+	return &ruleRepository{db: db}
+}
+
+const ruleColumns = `
+	r.id, r.workspace_id, r.user_id, r.name, r.priority, r.is_active, r.notes_pattern, r.notes_match,
+	r.min_amount, r.max_amount, r.condition_asset_id, r.set_category_id, r.set_asset_id, r.set_tags, r.created_at,
+	(SELECT tc.base_type FROM transaction_categories tc WHERE tc.id = r.set_category_id) AS set_category_type
+`
+
+func (r *ruleRepository) List(ctx context.Context, userID uuid.UUID) (*[]domain.CategorizationRule, error) {
+	db := getQueryer(ctx, r.db)
+	var rules = make([]domain.CategorizationRule, 0)
+	query := `
+		SELECT ` + ruleColumns + `
+		FROM categorization_rules r
+		WHERE r.workspace_id = $2
+			AND ` + memberOf("r.workspace_id", "$1", false) + `
+		ORDER BY r.priority ASC, r.created_at ASC
+	`
+	err := db.SelectContext(ctx, &rules, query, userID, workspaceFromContext(ctx, userID))
+
+	return &rules, err
+}
+
+// ListActive returns the active rules of the current workspace in evaluation order.
+func (r *ruleRepository) ListActive(ctx context.Context, userID uuid.UUID) (*[]domain.CategorizationRule, error) {
+	db := getQueryer(ctx, r.db)
+	var rules = make([]domain.CategorizationRule, 0)
+	query := `
+		SELECT ` + ruleColumns + `
+		FROM categorization_rules r
+		WHERE r.workspace_id = $2
+			AND r.is_active = TRUE
+			AND ` + memberOf("r.workspace_id", "$1", false) + `
+		ORDER BY r.priority ASC, r.created_at ASC
+	`
+	err := db.SelectContext(ctx, &rules, query, userID, workspaceFromContext(ctx, userID))
+
+	return &rules, err
+}
+
+func (r *ruleRepository) GetByID(ctx context.Context, id, userID uuid.UUID, write bool) (*domain.CategorizationRule, error) {
+	db := getQueryer(ctx, r.db)
+	var rule domain.CategorizationRule
+	query := `
+		SELECT ` + ruleColumns + `
+		FROM categorization_rules r
+		WHERE r.id = $1
+			AND r.workspace_id = $3
+			AND ` + memberOf("r.workspace_id", "$2", write)
+	err := db.GetContext(ctx, &rule, query, id, userID, workspaceFromContext(ctx, userID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(constant.ErrNotFound)
+	}
+
+	return &rule, err
+}
+
+func (r *ruleRepository) Insert(ctx context.Context, req *domain.CreateRule) (uuid.UUID, error) {
+	db := getQueryer(ctx, r.db)
+	workspaceID := workspaceFromContext(ctx, req.UserID)
+	if err := authorizeWorkspace(ctx, db, workspaceID, req.UserID, true); err != nil {
+		return uuid.Nil, err
+	}
+
+	query := `
+		INSERT INTO categorization_rules (
+			workspace_id, user_id, name, priority, is_active, notes_pattern, notes_match,
+			min_amount, max_amount, condition_asset_id, set_category_id, set_asset_id, set_tags
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id
+	`
+
+	var id uuid.UUID
+	err := db.QueryRowContext(ctx, query,
+		workspaceID, req.UserID, req.Name, req.Priority, *req.IsActive, req.NotesPattern, req.NotesMatch,
+		req.MinAmount, req.MaxAmount, req.ConditionAssetID, req.SetCategoryID, req.SetAssetID, pq.StringArray(req.SetTags),
+	).Scan(&id)
+
+	return id, err
+}
+
+func (r *ruleRepository) Update(ctx context.Context, req *domain.CreateRule) error {
+	db := getQueryer(ctx, r.db)
+	query := `
+		UPDATE categorization_rules r
+		SET name = $4, priority = $5, is_active = $6, notes_pattern = $7, notes_match = $8,
+			min_amount = $9, max_amount = $10, condition_asset_id = $11, set_category_id = $12,
+			set_asset_id = $13, set_tags = $14, updated_at = now()
+		WHERE r.id = $1
+			AND r.workspace_id = $3
+			AND ` + memberOf("r.workspace_id", "$2", true)
+	res, err := db.ExecContext(ctx, query,
+		req.ID, req.UserID, workspaceFromContext(ctx, req.UserID),
+		req.Name, req.Priority, *req.IsActive, req.NotesPattern, req.NotesMatch,
+		req.MinAmount, req.MaxAmount, req.ConditionAssetID, req.SetCategoryID, req.SetAssetID, pq.StringArray(req.SetTags),
+	)
+	if err != nil {
+		return err
+	}
+
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return errors.New(constant.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *ruleRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	db := getQueryer(ctx, r.db)
+	query := `DELETE FROM categorization_rules r WHERE r.id = $1 AND r.workspace_id = $3 AND ` + memberOf("r.workspace_id", "$2", true)
+	res, err := db.ExecContext(ctx, query, id, userID, workspaceFromContext(ctx, userID))
+	if err != nil {
+		return err
+	}
+
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return errors.New(constant.ErrNotFound)
+	}
+
+	return nil
+}
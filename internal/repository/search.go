@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type searchRepository struct {
+	db *sqlx.DB
+}
+
+func NewSearchRepository(db *sqlx.DB) domain.SearchRepository {
+	return &searchRepository{db: db}
+}
+
+// searchHeadline highlights the query in an HTML-escaped copy of text, so the
+// <mark> tags are the only markup in the result.
+func searchHeadline(text string) string {
+	return fmt.Sprintf(
+		`ts_headline('simple', replace(replace(replace(%s, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), q.query, 'StartSel=<mark>, StopSel=</mark>, MaxWords=20, MinWords=5, MaxFragments=2')`,
+		text,
+	)
+}
+
+// searchQueries holds one ranked select per result type. Each expects the
+// user as $1, the workspace as $2 and a q relation holding the tsquery.
+var searchQueries = map[string]string{
+	domain.SearchTypeTransaction: `
+		SELECT 'transaction' AS type, t.id, tc.name AS title, ` + searchHeadline("COALESCE(t.notes, '')") + ` AS highlight,
+			ts_rank(t.search_vector, q.query) AS rank, t.amount, t.transaction_date AS date
+		FROM q, transactions t
+		JOIN transaction_categories tc ON tc.id = t.category_id
+		WHERE t.search_vector @@ q.query
+			AND t.workspace_id = $2
+			AND ` + memberOf("t.workspace_id", "$1", false),
+	domain.SearchTypeCategory: `
+		SELECT 'category' AS type, tc.id, tc.name AS title, ` + searchHeadline("tc.name") + ` AS highlight,
+			ts_rank(tc.search_vector, q.query) AS rank, NULL::numeric AS amount, NULL::date AS date
+		FROM q, transaction_categories tc
+		WHERE tc.search_vector @@ q.query
+			AND tc.is_archived = FALSE
+			AND tc.user_id IN (SELECT user_id FROM workspace_members WHERE workspace_id = $2)
+			AND ` + memberOf("$2", "$1", false),
+	domain.SearchTypeAsset: `
+		SELECT 'asset' AS type, a.id, a.name AS title,
+			` + searchHeadline("a.name || COALESCE(' (' || (a.details->>'ticker_symbol') || ')', '')") + ` AS highlight,
+			ts_rank(a.search_vector, q.query) AS rank, a.current_value AS amount, NULL::date AS date
+		FROM q, assets a
+		WHERE a.search_vector @@ q.query
+			AND a.workspace_id = $2
+			AND ` + memberOf("a.workspace_id", "$1", false),
+	domain.SearchTypeLiability: `
+		SELECT 'liability' AS type, l.id, l.name AS title, ` + searchHeadline("l.name") + ` AS highlight,
+			ts_rank(l.search_vector, q.query) AS rank, l.remaining_balance AS amount, NULL::date AS date
+		FROM q, liabilities l
+		WHERE l.search_vector @@ q.query
+			AND l.workspace_id = $2
+			AND ` + memberOf("l.workspace_id", "$1", false),
+}
+
+// Search runs tsQuery over the requested result types of the current workspace
+// and returns the best ranked hits first.
+func (r *searchRepository) Search(ctx context.Context, req *domain.SearchRequest, tsQuery string) (*[]domain.SearchResult, error) {
+	db := getQueryer(ctx, r.db)
+	var results = make([]domain.SearchResult, 0)
+
+	parts := make([]string, 0, len(searchQueries))
+	for _, t := range req.TypeList() {
+		if query, ok := searchQueries[t]; ok {
+			parts = append(parts, query)
+		}
+	}
+
+	if len(parts) == 0 {
+		return &results, nil
+	}
+
+	query := `
+		WITH q AS (SELECT to_tsquery('simple', $3) AS query)
+		SELECT * FROM (` + strings.Join(parts, " UNION ALL ") + `) AS results
+		ORDER BY rank DESC, title ASC
+		LIMIT $4
+	`
+	err := db.SelectContext(ctx, &results, query, req.UserID, workspaceFromContext(ctx, req.UserID), tsQuery, req.Limit)
+
+	return &results, err
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type subscriptionRepository struct {
+	db *sqlx.DB
+}
+
+func NewSubscriptionRepository(db *sqlx.DB) domain.SubscriptionRepository {
+	return &subscriptionRepository{db: db}
+}
+
+// ListCharges lists the expense lines of the current workspace since from,
+// oldest first.
+func (r *subscriptionRepository) ListCharges(ctx context.Context, userID uuid.UUID, from time.Time) (*[]domain.SubscriptionCharge, error) {
+	db := getQueryer(ctx, r.db)
+	var charges = make([]domain.SubscriptionCharge, 0)
+	query := `
+		SELECT tl.category_id, tc.name AS category_name, tl.amount, t.transaction_date, tl.notes
+		FROM transactions t
+		JOIN transaction_lines tl ON tl.transaction_id = t.id
+		JOIN transaction_categories tc ON tc.id = tl.category_id
+		WHERE t.workspace_id = $2
+			AND tc.base_type = 'expense'
+			AND t.transaction_date >= $3
+			AND ` + memberOf("t.workspace_id", "$1", false) + `
+		ORDER BY t.transaction_date, t.created_at
+	`
+	err := db.SelectContext(ctx, &charges, query, userID, workspaceFromContext(ctx, userID), from.Format(time.DateOnly))
+
+	return &charges, err
+}
+
+// List returns the subscriptions of the current workspace with the given
+// status, or all of them when status is empty.
+func (r *subscriptionRepository) List(ctx context.Context, userID uuid.UUID, status string) (*[]domain.Subscription, error) {
+	db := getQueryer(ctx, r.db)
+	var subscriptions = make([]domain.Subscription, 0)
+	query := `
+		SELECT s.id, s.workspace_id, s.category_id, tc.name AS category_name, s.pattern, s.status,
+			s.name, s.cadence, s.amount, s.last_charge_date, s.created_at
+		FROM subscriptions s
+		JOIN transaction_categories tc ON tc.id = s.category_id
+		WHERE s.workspace_id = $2
+			AND ($3 = '' OR s.status = $3)
+			AND ` + memberOf("s.workspace_id", "$1", false) + `
+		ORDER BY s.name ASC, s.created_at ASC
+	`
+	err := db.SelectContext(ctx, &subscriptions, query, userID, workspaceFromContext(ctx, userID), status)
+
+	return &subscriptions, err
+}
+
+// Save records the decision on a detection, replacing an earlier one on the
+// same pattern.
+func (r *subscriptionRepository) Save(ctx context.Context, req *domain.DecideSubscription, detected *domain.DetectedSubscription) (*domain.Subscription, error) {
+	db := getQueryer(ctx, r.db)
+	workspaceID := workspaceFromContext(ctx, req.UserID)
+	if err := authorizeWorkspace(ctx, db, workspaceID, req.UserID, true); err != nil {
+		return nil, err
+	}
+
+	var subscription domain.Subscription
+	query := `
+		INSERT INTO subscriptions (workspace_id, user_id, category_id, pattern, status, name, cadence, amount, last_charge_date)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (workspace_id, category_id, pattern) DO UPDATE SET
+			status = EXCLUDED.status,
+			name = EXCLUDED.name,
+			cadence = EXCLUDED.cadence,
+			amount = EXCLUDED.amount,
+			last_charge_date = EXCLUDED.last_charge_date,
+			updated_at = now()
+		RETURNING id, workspace_id, category_id, pattern, status, name, cadence, amount, last_charge_date, created_at
+	`
+	err := db.GetContext(ctx, &subscription, query,
+		workspaceID, req.UserID, *req.CategoryID, req.Pattern, req.Status,
+		detected.Name, detected.Cadence, detected.AverageAmount, detected.LastCharge,
+	)
+	subscription.CategoryName = detected.CategoryName
+
+	return &subscription, err
+}
+
+func (r *subscriptionRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	db := getQueryer(ctx, r.db)
+	query := `DELETE FROM subscriptions s WHERE s.id = $1 AND s.workspace_id = $3 AND ` + memberOf("s.workspace_id", "$2", true)
+	res, err := db.ExecContext(ctx, query, id, userID, workspaceFromContext(ctx, userID))
+	if err != nil {
+		return err
+	}
+
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return errors.New(constant.ErrNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type tagRepository struct {
+	db *sqlx.DB
+}
+
+func NewTagRepository(db *sqlx.DB) domain.TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) List(ctx context.Context, userID uuid.UUID) (*[]domain.Tag, error) {
+	db := getQueryer(ctx, r.db)
+	var tags = make([]domain.Tag, 0)
+	query := `
+		SELECT t.id, t.workspace_id, t.name, t.created_at,
+			(SELECT COUNT(*) FROM transaction_tags tt WHERE tt.tag_id = t.id) AS transaction_count
+		FROM tags t
+		WHERE t.workspace_id = $2
+			AND ` + memberOf("t.workspace_id", "$1", false) + `
+		ORDER BY t.name ASC
+	`
+	err := db.SelectContext(ctx, &tags, query, userID, workspaceFromContext(ctx, userID))
+
+	return &tags, err
+}
+
+func (r *tagRepository) GetByID(ctx context.Context, id, userID uuid.UUID, write bool) (*domain.Tag, error) {
+	db := getQueryer(ctx, r.db)
+	var tag domain.Tag
+	query := `
+		SELECT t.id, t.workspace_id, t.name, t.created_at,
+			(SELECT COUNT(*) FROM transaction_tags tt WHERE tt.tag_id = t.id) AS transaction_count
+		FROM tags t
+		WHERE t.id = $1
+			AND t.workspace_id = $3
+			AND ` + memberOf("t.workspace_id", "$2", write)
+	err := db.GetContext(ctx, &tag, query, id, userID, workspaceFromContext(ctx, userID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(constant.ErrNotFound)
+	}
+
+	return &tag, err
+}
+
+// Upsert creates the missing tags of a workspace and returns the ids of all names.
+func (r *tagRepository) Upsert(ctx context.Context, workspaceID uuid.UUID, names []string) ([]uuid.UUID, error) {
+	db := getQueryer(ctx, r.db)
+	var ids = make([]uuid.UUID, 0, len(names))
+	if len(names) == 0 {
+		return ids, nil
+	}
+
+	// DO UPDATE instead of DO NOTHING so existing tags are returned as well
+	query := `
+		INSERT INTO tags (workspace_id, name)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT (workspace_id, name) DO UPDATE SET name = EXCLUDED.name
+		RETURNING id
+	`
+	err := db.SelectContext(ctx, &ids, query, workspaceID, pq.StringArray(names))
+
+	return ids, err
+}
+
+// SetTransactionTags replaces the tags of a transaction.
+func (r *tagRepository) SetTransactionTags(ctx context.Context, transactionID uuid.UUID, tagIDs []uuid.UUID) error {
+	db := getQueryer(ctx, r.db)
+	query := `DELETE FROM transaction_tags WHERE transaction_id = $1`
+	if _, err := db.ExecContext(ctx, query, transactionID); err != nil {
+		return err
+	}
+
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	query = `INSERT INTO transaction_tags (transaction_id, tag_id) SELECT $1, unnest($2::uuid[])`
+	_, err := db.ExecContext(ctx, query, transactionID, pq.Array(tagIDs))
+
+	return err
+}
+
+func (r *tagRepository) Rename(ctx context.Context, id uuid.UUID, name string) error {
+	db := getQueryer(ctx, r.db)
+	query := `UPDATE tags SET name = $2 WHERE id = $1`
+	_, err := db.ExecContext(ctx, query, id, name)
+	if err != nil && strings.Contains(err.Error(), "duplicate key value") {
+		return errors.New(constant.ErrTagExists)
+	}
+
+	return err
+}
+
+// Merge moves every transaction tagged with sourceID to targetID and deletes the source tag.
+func (r *tagRepository) Merge(ctx context.Context, sourceID, targetID uuid.UUID) error {
+	db := getQueryer(ctx, r.db)
+	query := `
+		INSERT INTO transaction_tags (transaction_id, tag_id)
+		SELECT transaction_id, $2 FROM transaction_tags WHERE tag_id = $1
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := db.ExecContext(ctx, query, sourceID, targetID); err != nil {
+		return err
+	}
+
+	return r.Delete(ctx, sourceID)
+}
+
+func (r *tagRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	db := getQueryer(ctx, r.db)
+	query := `DELETE FROM tags WHERE id = $1`
+	_, err := db.ExecContext(ctx, query, id)
+
+	return err
+}
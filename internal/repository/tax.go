@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+type taxRepository struct {
+	db *sqlx.DB
+}
+
+func NewTaxRepository(db *sqlx.DB) domain.TaxRepository {
+	return &taxRepository{db: db}
+}
+
+// GetYear returns the version of the tax rules in force in year, the latest
+// one not newer than it.
+func (r *taxRepository) GetYear(ctx context.Context, year int) (*domain.TaxYear, error) {
+	db := getQueryer(ctx, r.db)
+	var taxYear domain.TaxYear
+	query := `
+		SELECT year, occupational_cost_rate, occupational_cost_cap, notes
+		FROM tax_years
+		WHERE year <= $1
+		ORDER BY year DESC
+		LIMIT 1
+	`
+	err := db.GetContext(ctx, &taxYear, query, year)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(constant.ErrNotFound)
+	}
+
+	return &taxYear, err
+}
+
+func (r *taxRepository) GetPTKP(ctx context.Context, year int, status string) (decimal.Decimal, error) {
+	db := getQueryer(ctx, r.db)
+	var amount decimal.Decimal
+	query := `SELECT amount FROM tax_ptkp WHERE year = $1 AND status = $2`
+	err := db.GetContext(ctx, &amount, query, year, status)
+	if err == sql.ErrNoRows {
+		return amount, errors.New(constant.ErrNotFound)
+	}
+
+	return amount, err
+}
+
+func (r *taxRepository) ListBrackets(ctx context.Context, year int) (*[]domain.TaxBracket, error) {
+	db := getQueryer(ctx, r.db)
+	var brackets = make([]domain.TaxBracket, 0)
+	query := `SELECT lower_bound, rate FROM tax_brackets WHERE year = $1 ORDER BY lower_bound`
+	err := db.SelectContext(ctx, &brackets, query, year)
+
+	return &brackets, err
+}
+
+// ListIncome totals the income booked in the current workspace between from
+// and to per category.
+func (r *taxRepository) ListIncome(ctx context.Context, userID uuid.UUID, from, to time.Time) (*[]domain.TaxIncome, error) {
+	db := getQueryer(ctx, r.db)
+	var incomes = make([]domain.TaxIncome, 0)
+	query := `
+		SELECT tc.id AS category_id, tc.name, tc.is_taxable, SUM(tl.amount) AS amount
+		FROM transactions t
+		JOIN transaction_lines tl ON tl.transaction_id = t.id
+		JOIN transaction_categories tc ON tc.id = tl.category_id
+		WHERE t.workspace_id = $2 AND tc.base_type = 'income'
+			AND t.transaction_date BETWEEN $3 AND $4
+			AND ` + memberOf("t.workspace_id", "$1", false) + `
+		GROUP BY tc.id, tc.name, tc.is_taxable
+		ORDER BY amount DESC, tc.name
+	`
+	err := db.SelectContext(ctx, &incomes, query, userID, workspaceFromContext(ctx, userID), from, to)
+
+	return &incomes, err
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type personalAccessTokenRepository struct {
+	db *sqlx.DB
+}
+
+func NewPersonalAccessTokenRepository(db *sqlx.DB) domain.PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{db: db}
+}
+
+func (r *personalAccessTokenRepository) List(ctx context.Context, userID uuid.UUID) (*[]domain.PersonalAccessToken, error) {
+	db := getQueryer(ctx, r.db)
+	var tokens = make([]domain.PersonalAccessToken, 0)
+	query := `
+		SELECT id, user_id, name, token_prefix, scopes, expires_at, last_used_at, created_at
+		FROM personal_access_tokens
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+	err := db.SelectContext(ctx, &tokens, query, userID)
+
+	return &tokens, err
+}
+
+func (r *personalAccessTokenRepository) Insert(ctx context.Context, data *domain.PersonalAccessToken) (uuid.UUID, error) {
+	db := getQueryer(ctx, r.db)
+	query := `
+		INSERT INTO personal_access_tokens (user_id, name, token_hash, token_prefix, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	var id uuid.UUID
+	err := db.QueryRowContext(ctx, query, data.UserID, data.Name, data.TokenHash, data.TokenPrefix, data.Scopes, data.ExpiresAt).Scan(&id)
+
+	return id, err
+}
+
+func (r *personalAccessTokenRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	db := getQueryer(ctx, r.db)
+	query := `DELETE FROM personal_access_tokens WHERE id = $1 AND user_id = $2`
+	res, err := db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return errors.New(constant.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Touch looks up a non-expired token by its hash and records it as used.
+func (r *personalAccessTokenRepository) Touch(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	db := getQueryer(ctx, r.db)
+	var token domain.PersonalAccessToken
+	query := `
+		UPDATE personal_access_tokens
+		SET last_used_at = now()
+		WHERE token_hash = $1
+			AND (expires_at IS NULL OR expires_at > now())
+		RETURNING id, user_id, name, token_prefix, scopes, expires_at, last_used_at, created_at
+	`
+	err := db.GetContext(ctx, &token, query, tokenHash)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(constant.ErrNotFound)
+	}
+
+	return &token, err
+}
@@ -14,8 +14,12 @@ import (
 	"github.com/fazriegi/netbase-be/pkg/constant"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// likeEscaper escapes the LIKE wildcards of user input.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 type transactionRepository struct {
 	db *sqlx.DB
 }
@@ -24,77 +28,128 @@ func NewTransactionRepository(db *sqlx.DB) domain.TransactionRepository {
 	return &transactionRepository{db: db}
 }
 
-func (r *transactionRepository) ListCategory(ctx context.Context, req *domain.ListCategoryRequest) (*[]domain.Category, error) {
+// GetCategoryByID returns a category of a member of the current workspace, or
+// one the transaction transactionID already uses, on itself or a split.
+func (r *transactionRepository) GetCategoryByID(ctx context.Context, id, transactionID, userID uuid.UUID) (*domain.Category, error) {
 	db := getQueryer(ctx, r.db)
-	var categories = make([]domain.Category, 0)
-	query := `SELECT id, name, base_type FROM transaction_categories WHERE user_id = $1`
+	var category domain.Category
+	query := `
+		SELECT tc.id, tc.name, tc.base_type FROM transaction_categories tc
+		WHERE tc.id = $1 AND (
+			tc.user_id IN (SELECT user_id FROM workspace_members WHERE workspace_id = $3)
+			OR EXISTS (SELECT 1 FROM transactions WHERE id = $2 AND category_id = tc.id)
+			OR EXISTS (SELECT 1 FROM transaction_splits WHERE transaction_id = $2 AND category_id = tc.id)
+		)
+	`
+	err := db.GetContext(ctx, &category, query, id, transactionID, workspaceFromContext(ctx, userID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(constant.ErrNotFound)
+	}
 
-	args := []interface{}{req.UserID}
+	return &category, err
+}
 
-	if req.BaseType != "" {
-		query += ` AND base_type = $2`
-		args = append(args, req.BaseType)
-	}
+// categorySubtree selects the workspace categories matching seed together with
+// all of their descendants, so filtering on a parent includes its children.
+func categorySubtree(seed string) string {
+	return `
+		WITH RECURSIVE matched AS (
+			SELECT id FROM transaction_categories
+			WHERE ` + seed + `
+				AND user_id IN (SELECT user_id FROM workspace_members WHERE workspace_id = :workspace_id)
+			UNION
+			SELECT c.id FROM transaction_categories c JOIN matched m ON c.parent_id = m.id
+		)
+		SELECT id FROM matched
+	`
+}
 
-	if req.Search != "" {
-		query += ` AND name ILIKE $3`
-		args = append(args, "%"+req.Search+"%")
+// lineCategoryFilter keeps the lines booked on one of categories. Per
+// transaction, a split transaction matches when any of its splits does.
+func lineCategoryFilter(categories string, perLine bool) string {
+	if perLine {
+		return ` AND tl.category_id IN (` + categories + `)`
 	}
 
-	query += ` ORDER BY name ASC`
+	return ` AND EXISTS (
+		SELECT 1 FROM transaction_lines tl
+		WHERE tl.transaction_id = transactions.id AND tl.category_id IN (` + categories + `)
+	)`
+}
 
-	err := db.SelectContext(ctx, &categories, query, args...)
+// transactionFilter builds the conditions of the list filters. Queries that
+// aggregate over the transaction_lines view set perLine, so the category filters
+// keep only the matching splits instead of whole transactions.
+func (r *transactionRepository) transactionFilter(req *domain.ListTransactionRequest, perLine bool) string {
+	var query string
+	if req.CategoryName != "" {
+		query += lineCategoryFilter(categorySubtree(`name ILIKE :category_name`), perLine)
+	}
 
-	return &categories, err
-}
+	if len(req.CategoryIDs) > 0 {
+		query += lineCategoryFilter(categorySubtree(`id = ANY(CAST(:category_ids AS uuid[]))`), perLine)
+	}
 
-func (r *transactionRepository) GetCategoryByID(ctx context.Context, id, userID uuid.UUID) (*domain.Category, error) {
-	db := getQueryer(ctx, r.db)
-	var category domain.Category
-	query := `SELECT id, name, base_type FROM transaction_categories WHERE id = $1 AND user_id = $2`
-	err := db.GetContext(ctx, &category, query, id, userID)
-	if err == sql.ErrNoRows {
-		return nil, errors.New(constant.ErrNotFound)
+	if req.BaseType != "" {
+		query += ` AND EXISTS (
+			SELECT 1 FROM transaction_categories btc
+			WHERE btc.id = transactions.category_id AND btc.base_type = CAST(:base_type AS transaction_type)
+		)`
 	}
 
-	return &category, err
-}
+	if req.MinAmount != nil {
+		query += ` AND transactions.amount >= :min_amount`
+	}
 
-func (r *transactionRepository) InsertCategory(ctx context.Context, category *domain.Category) error {
-	db := getQueryer(ctx, r.db)
-	query := `INSERT INTO transaction_categories (user_id, name, base_type) VALUES (:user_id, :name, :base_type) ON CONFLICT (user_id, name, base_type) DO NOTHING`
-	_, err := db.NamedExecContext(ctx, query, category)
+	if req.MaxAmount != nil {
+		query += ` AND transactions.amount <= :max_amount`
+	}
 
-	return err
-}
+	if req.AssetID != nil {
+		query += ` AND transactions.asset_id = :asset_id`
+	}
 
-func (r *transactionRepository) DeleteCategory(ctx context.Context, id, userID uuid.UUID) error {
-	db := getQueryer(ctx, r.db)
-	query := `DELETE FROM transaction_categories WHERE id = $1 AND user_id = $2`
-	_, err := db.ExecContext(ctx, query, id, userID)
+	if req.LiabilityID != nil {
+		query += ` AND transactions.liability_id = :liability_id`
+	}
 
-	if err != nil && strings.Contains(err.Error(), "violates RESTRICT setting of foreign key constraint") {
-		return errors.New("violates foreign key constraint")
+	if req.CreatedAfter != nil {
+		query += ` AND transactions.created_at >= :created_after`
 	}
 
-	return err
-}
+	if req.CreatedBefore != nil {
+		query += ` AND transactions.created_at < :created_before`
+	}
 
-func (r *transactionRepository) transactionFilter(req *domain.ListTransactionRequest) string {
-	var query string
-	if req.CategoryName != "" {
-		query += ` AND tc.name ILIKE :category_name`
+	if req.UpdatedAfter != nil {
+		query += ` AND transactions.updated_at >= :updated_after`
+	}
+
+	if req.UpdatedBefore != nil {
+		query += ` AND transactions.updated_at < :updated_before`
 	}
 
 	if req.Notes != "" {
 		query += ` AND transactions.notes ILIKE :notes`
 	}
 
+	if len(req.TagNames()) > 0 {
+		tagged := `SELECT COUNT(DISTINCT t.name) FROM transaction_tags tt JOIN tags t ON t.id = tt.tag_id
+			WHERE tt.transaction_id = transactions.id AND t.name = ANY(:tags)`
+		if req.TagMatch == domain.TagMatchAll {
+			query += ` AND (` + tagged + `) = :tag_count`
+		} else {
+			query += ` AND (` + tagged + `) > 0`
+		}
+	}
+
 	switch req.FilterType {
 	case "week":
 		query += ` AND DATE_TRUNC('week', transactions.transaction_date) = DATE_TRUNC('week', CAST(:ref_date AS date))`
 	case "month":
 		query += ` AND DATE_TRUNC('month', transactions.transaction_date) = DATE_TRUNC('month', CAST(:ref_date AS date))`
+	case "quarter":
+		query += ` AND DATE_TRUNC('quarter', transactions.transaction_date) = DATE_TRUNC('quarter', CAST(:ref_date AS date))`
 	case "year":
 		query += ` AND DATE_TRUNC('year', transactions.transaction_date) = DATE_TRUNC('year', CAST(:ref_date AS date))`
 	case "range":
@@ -104,7 +159,46 @@ func (r *transactionRepository) transactionFilter(req *domain.ListTransactionReq
 	return query
 }
 
-func (r *transactionRepository) List(ctx context.Context, req *domain.ListTransactionRequest) (*[]domain.Transaction, int, error) {
+func (r *transactionRepository) transactionFilterArgs(ctx context.Context, req *domain.ListTransactionRequest) map[string]interface{} {
+	refDate := req.DateStr
+	if refDate == "" {
+		refDate = time.Now().Format("2006-01-02")
+	}
+
+	return map[string]interface{}{
+		"user_id":        req.UserID,
+		"workspace_id":   workspaceFromContext(ctx, req.UserID),
+		"category_name":  "%" + req.CategoryName + "%",
+		"notes":          "%" + req.Notes + "%",
+		"ref_date":       refDate,
+		"start_date":     req.StartDateStr,
+		"end_date":       req.EndDateStr,
+		"tags":           pq.StringArray(req.TagNames()),
+		"tag_count":      len(req.TagNames()),
+		"category_ids":   pq.Array(req.CategoryIDs),
+		"base_type":      req.BaseType,
+		"min_amount":     req.MinAmount,
+		"max_amount":     req.MaxAmount,
+		"asset_id":       req.AssetID,
+		"liability_id":   req.LiabilityID,
+		"created_after":  req.CreatedAfter,
+		"created_before": req.CreatedBefore,
+		"updated_after":  req.UpdatedAfter,
+		"updated_before": req.UpdatedBefore,
+	}
+}
+
+// transactionKeyset is the order of cursor paginated transaction lists, newest first.
+var transactionKeyset = pkg.Keyset{
+	Columns: []pkg.KeysetColumn{
+		{Expr: "transactions.transaction_date", Type: "date"},
+		{Expr: "transactions.created_at", Type: "timestamptz"},
+		{Expr: "transactions.id", Type: "uuid"},
+	},
+	Desc: true,
+}
+
+func (r *transactionRepository) List(ctx context.Context, req *domain.ListTransactionRequest) (*[]domain.Transaction, pkg.Page, error) {
 	db := getQueryer(ctx, r.db)
 	var transactions = make([]domain.Transaction, 0)
 	var total int
@@ -114,6 +208,7 @@ func (r *transactionRepository) List(ctx context.Context, req *domain.ListTransa
 		SELECT 
 			transactions.id, 
 			transactions.user_id, 
+			transactions.workspace_id,
 			transactions.asset_id, 
 			assets.name as asset_name,
 			transactions.liability_id, 
@@ -124,22 +219,30 @@ func (r *transactionRepository) List(ctx context.Context, req *domain.ListTransa
 			transactions.amount, 
 			transactions.transaction_date, 
 			transactions.notes,
+			ARRAY(
+				SELECT t.name FROM transaction_tags tt JOIN tags t ON t.id = tt.tag_id
+				WHERE tt.transaction_id = transactions.id ORDER BY t.name
+			) AS tags,
+			EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = transactions.id) AS is_split,
 			transactions.created_at
 		FROM transactions 
-		JOIN transaction_categories tc ON tc.id = transactions.category_id AND tc.user_id = transactions.user_id
-		LEFT JOIN assets ON assets.id = transactions.asset_id AND assets.user_id = transactions.user_id
-		LEFT JOIN liabilities ON liabilities.id = transactions.liability_id AND liabilities.user_id = transactions.user_id
-		WHERE transactions.user_id = :user_id
+		JOIN transaction_categories tc ON tc.id = transactions.category_id
+		LEFT JOIN assets ON assets.id = transactions.asset_id
+		LEFT JOIN liabilities ON liabilities.id = transactions.liability_id
+		WHERE transactions.workspace_id = :workspace_id
+			AND ` + memberOf("transactions.workspace_id", ":user_id", false) + `
 	`
 
-	var refDate string
-	if req.DateStr != "" {
-		refDate = req.DateStr
-	} else {
-		refDate = time.Now().Format("2006-01-02")
-	}
+	query += r.transactionFilter(req, false)
 
-	query += r.transactionFilter(req)
+	if req.UseCursor() {
+		transactions, page, err := selectKeysetPage(ctx, db, query, r.transactionFilterArgs(ctx, req), transactionKeyset, &req.PaginationRequest,
+			func(tx *domain.Transaction) []string {
+				return []string{tx.TransactionDate.Format(time.DateOnly), cursorTime(tx.CreatedAt), tx.ID.String()}
+			},
+		)
+		return &transactions, page, err
+	}
 
 	if req.Sort == nil {
 		req.Sort = &defaultSort
@@ -150,14 +253,7 @@ func (r *transactionRepository) List(ctx context.Context, req *domain.ListTransa
 
 	wg.Add(2)
 
-	arg := map[string]interface{}{
-		"user_id":       req.UserID,
-		"category_name": "%" + req.CategoryName + "%",
-		"notes":         "%" + req.Notes + "%",
-		"ref_date":      refDate,
-		"start_date":    req.StartDateStr,
-		"end_date":      req.EndDateStr,
-	}
+	arg := r.transactionFilterArgs(ctx, req)
 	go func() {
 		defer wg.Done()
 		resCount, err := db.NamedQueryContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM (%s) as count_query", query), arg)
@@ -209,11 +305,11 @@ func (r *transactionRepository) List(ctx context.Context, req *domain.ListTransa
 
 	for err := range errChan {
 		if err != nil {
-			return nil, 0, err
+			return nil, pkg.Page{}, err
 		}
 	}
 
-	return &transactions, total, nil
+	return &transactions, pkg.Page{Total: total}, nil
 }
 
 func (r *transactionRepository) GetSummary(ctx context.Context, req *domain.ListTransactionRequest) (*domain.TransactionSummary, error) {
@@ -221,30 +317,18 @@ func (r *transactionRepository) GetSummary(ctx context.Context, req *domain.List
 
 	query := `
 		SELECT 
-			COALESCE(SUM(CASE WHEN tc.base_type = 'income' THEN transactions.amount ELSE 0 END), 0) as income,
-			COALESCE(SUM(CASE WHEN tc.base_type = 'expense' THEN transactions.amount ELSE 0 END), 0) as expense
+			COALESCE(SUM(CASE WHEN tc.base_type = 'income' THEN tl.amount ELSE 0 END), 0) as income,
+			COALESCE(SUM(CASE WHEN tc.base_type = 'expense' THEN tl.amount ELSE 0 END), 0) as expense
 		FROM transactions 
-		JOIN transaction_categories tc ON tc.id = transactions.category_id AND tc.user_id = transactions.user_id
-		WHERE transactions.user_id = :user_id
+		JOIN transaction_lines tl ON tl.transaction_id = transactions.id
+		JOIN transaction_categories tc ON tc.id = tl.category_id
+		WHERE transactions.workspace_id = :workspace_id
+			AND ` + memberOf("transactions.workspace_id", ":user_id", false) + `
 	`
 
-	var refDate string
-	if req.DateStr != "" {
-		refDate = req.DateStr
-	} else {
-		refDate = time.Now().Format("2006-01-02")
-	}
-
-	query += r.transactionFilter(req)
+	query += r.transactionFilter(req, true)
 
-	rows, err := db.NamedQueryContext(ctx, query, map[string]interface{}{
-		"user_id":       req.UserID,
-		"category_name": "%" + req.CategoryName + "%",
-		"notes":         "%" + req.Notes + "%",
-		"ref_date":      refDate,
-		"start_date":    req.StartDateStr,
-		"end_date":      req.EndDateStr,
-	})
+	rows, err := db.NamedQueryContext(ctx, query, r.transactionFilterArgs(ctx, req))
 	if err != nil {
 		return nil, err
 	}
@@ -261,6 +345,160 @@ func (r *transactionRepository) GetSummary(ctx context.Context, req *domain.List
 	return &summary, nil
 }
 
+// GetCategorySummary returns the amount booked directly on each category used by
+// the matching transactions, together with all of their ancestors so the
+// caller can roll spend up the tree.
+func (r *transactionRepository) GetCategorySummary(ctx context.Context, req *domain.ListTransactionRequest) (*[]domain.CategorySummary, error) {
+	db := getQueryer(ctx, r.db)
+	var summaries = make([]domain.CategorySummary, 0)
+
+	query := `
+		WITH RECURSIVE spend AS (
+			SELECT tl.category_id, SUM(tl.amount) AS amount
+			FROM transactions
+			JOIN transaction_lines tl ON tl.transaction_id = transactions.id
+			WHERE transactions.workspace_id = :workspace_id
+				AND ` + memberOf("transactions.workspace_id", ":user_id", false) +
+		r.transactionFilter(req, true) + `
+			GROUP BY tl.category_id
+		), tree AS (
+			SELECT tc.id, tc.parent_id FROM transaction_categories tc
+			WHERE tc.id IN (SELECT category_id FROM spend)
+			UNION
+			SELECT p.id, p.parent_id FROM transaction_categories p JOIN tree ON p.id = tree.parent_id
+		)
+		SELECT c.id, c.parent_id, c.name, c.base_type, COALESCE(spend.amount, 0) AS amount
+		FROM transaction_categories c
+		LEFT JOIN spend ON spend.category_id = c.id
+		WHERE c.id IN (SELECT id FROM tree)
+		ORDER BY c.name ASC
+	`
+
+	rows, err := db.NamedQueryContext(ctx, query, r.transactionFilterArgs(ctx, req))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var summary domain.CategorySummary
+		if err := rows.StructScan(&summary); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return &summaries, rows.Err()
+}
+
+// GetTagSummary totals the matching transactions per tag. A transaction with
+// several tags counts towards each of them.
+func (r *transactionRepository) GetTagSummary(ctx context.Context, req *domain.ListTransactionRequest) (*[]domain.TagSummary, error) {
+	db := getQueryer(ctx, r.db)
+	var summaries = make([]domain.TagSummary, 0)
+
+	query := `
+		SELECT
+			t.id,
+			t.name,
+			COUNT(DISTINCT transactions.id) AS transaction_count,
+			COALESCE(SUM(CASE WHEN tc.base_type = 'income' THEN tl.amount ELSE 0 END), 0) AS income,
+			COALESCE(SUM(CASE WHEN tc.base_type = 'expense' THEN tl.amount ELSE 0 END), 0) AS expense,
+			COALESCE(SUM(CASE WHEN tc.base_type = 'income' THEN tl.amount ELSE -tl.amount END), 0) AS net
+		FROM transactions
+		JOIN transaction_lines tl ON tl.transaction_id = transactions.id
+		JOIN transaction_categories tc ON tc.id = tl.category_id
+		JOIN transaction_tags tt ON tt.transaction_id = transactions.id
+		JOIN tags t ON t.id = tt.tag_id
+		WHERE transactions.workspace_id = :workspace_id
+			AND ` + memberOf("transactions.workspace_id", ":user_id", false) +
+		r.transactionFilter(req, true) + `
+		GROUP BY t.id, t.name
+		ORDER BY expense DESC, t.name ASC
+	`
+
+	rows, err := db.NamedQueryContext(ctx, query, r.transactionFilterArgs(ctx, req))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var summary domain.TagSummary
+		if err := rows.StructScan(&summary); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return &summaries, rows.Err()
+}
+
+// ListForRule returns the transactions of the current workspace that pass the
+// amount, asset and "contains" conditions of a rule. A rule setting a category
+// only gets transactions of the same base type. Regex conditions are left to the
+// caller so they match the same way as on create.
+func (r *transactionRepository) ListForRule(ctx context.Context, rule *domain.CategorizationRule, userID uuid.UUID) (*[]domain.Transaction, error) {
+	db := getQueryer(ctx, r.db)
+	var transactions = make([]domain.Transaction, 0)
+
+	query := `
+		SELECT
+			transactions.id,
+			transactions.user_id,
+			transactions.workspace_id,
+			transactions.asset_id,
+			transactions.liability_id,
+			transactions.category_id,
+			tc.name as category_name,
+			tc.base_type as category_type,
+			transactions.amount,
+			transactions.transaction_date,
+			transactions.notes,
+			ARRAY(
+				SELECT t.name FROM transaction_tags tt JOIN tags t ON t.id = tt.tag_id
+				WHERE tt.transaction_id = transactions.id ORDER BY t.name
+			) AS tags,
+			EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = transactions.id) AS is_split,
+			transactions.created_at
+		FROM transactions
+		JOIN transaction_categories tc ON tc.id = transactions.category_id
+		WHERE transactions.workspace_id = $2
+			AND ` + memberOf("transactions.workspace_id", "$1", false)
+	args := []interface{}{userID, workspaceFromContext(ctx, userID)}
+
+	if rule.MinAmount != nil {
+		args = append(args, *rule.MinAmount)
+		query += fmt.Sprintf(` AND transactions.amount >= $%d`, len(args))
+	}
+
+	if rule.MaxAmount != nil {
+		args = append(args, *rule.MaxAmount)
+		query += fmt.Sprintf(` AND transactions.amount <= $%d`, len(args))
+	}
+
+	if rule.ConditionAssetID != nil {
+		args = append(args, *rule.ConditionAssetID)
+		query += fmt.Sprintf(` AND transactions.asset_id = $%d`, len(args))
+	}
+
+	if rule.NotesPattern != nil && rule.NotesMatch == domain.RuleMatchContains {
+		args = append(args, "%"+likeEscaper.Replace(*rule.NotesPattern)+"%")
+		query += fmt.Sprintf(` AND transactions.notes ILIKE $%d`, len(args))
+	}
+
+	if rule.SetCategoryID != nil {
+		args = append(args, *rule.SetCategoryID)
+		query += fmt.Sprintf(` AND tc.base_type = (SELECT base_type FROM transaction_categories WHERE id = $%d)`, len(args))
+	}
+
+	query += ` ORDER BY transactions.transaction_date DESC, transactions.created_at DESC`
+
+	err := db.SelectContext(ctx, &transactions, query, args...)
+
+	return &transactions, err
+}
+
 func (r *transactionRepository) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Transaction, error) {
 	db := getQueryer(ctx, r.db)
 	var tx domain.Transaction
@@ -268,6 +506,7 @@ func (r *transactionRepository) GetByID(ctx context.Context, id, userID uuid.UUI
 		SELECT 
 			transactions.id, 
 			transactions.user_id, 
+			transactions.workspace_id,
 			transactions.asset_id, 
 			assets.name as asset_name,
 			transactions.liability_id, 
@@ -278,14 +517,20 @@ func (r *transactionRepository) GetByID(ctx context.Context, id, userID uuid.UUI
 			transactions.amount, 
 			transactions.transaction_date, 
 			transactions.notes,
+			ARRAY(
+				SELECT t.name FROM transaction_tags tt JOIN tags t ON t.id = tt.tag_id
+				WHERE tt.transaction_id = transactions.id ORDER BY t.name
+			) AS tags,
+			EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = transactions.id) AS is_split,
 			transactions.created_at
 		FROM transactions 
-		JOIN transaction_categories tc ON tc.id = transactions.category_id AND tc.user_id = transactions.user_id
-		LEFT JOIN assets ON assets.id = transactions.asset_id AND assets.user_id = transactions.user_id
-		LEFT JOIN liabilities ON liabilities.id = transactions.liability_id AND liabilities.user_id = transactions.user_id
-		WHERE transactions.id = $1 AND transactions.user_id = $2
-	`
-	err := db.GetContext(ctx, &tx, query, id, userID)
+		JOIN transaction_categories tc ON tc.id = transactions.category_id
+		LEFT JOIN assets ON assets.id = transactions.asset_id
+		LEFT JOIN liabilities ON liabilities.id = transactions.liability_id
+		WHERE transactions.id = $1
+			AND transactions.workspace_id = $3
+			AND ` + memberOf("transactions.workspace_id", "$2", false)
+	err := db.GetContext(ctx, &tx, query, id, userID, workspaceFromContext(ctx, userID))
 	if err == sql.ErrNoRows {
 		return nil, errors.New(constant.ErrNotFound)
 	}
@@ -293,33 +538,106 @@ func (r *transactionRepository) GetByID(ctx context.Context, id, userID uuid.UUI
 	return &tx, err
 }
 
+// ListSplits returns the split lines of a transaction in the order they were entered.
+func (r *transactionRepository) ListSplits(ctx context.Context, transactionID uuid.UUID) (*[]domain.TransactionSplit, error) {
+	db := getQueryer(ctx, r.db)
+	var splits = make([]domain.TransactionSplit, 0)
+	query := `
+		SELECT s.id, s.transaction_id, s.category_id, tc.name AS category_name, s.amount, s.notes
+		FROM transaction_splits s
+		JOIN transaction_categories tc ON tc.id = s.category_id
+		WHERE s.transaction_id = $1
+		ORDER BY s.created_at ASC, s.id ASC
+	`
+	err := db.SelectContext(ctx, &splits, query, transactionID)
+
+	return &splits, err
+}
+
+// ReplaceSplits replaces the split lines of a transaction. An empty slice
+// turns it back into a single-category transaction.
+func (r *transactionRepository) ReplaceSplits(ctx context.Context, transactionID uuid.UUID, splits []domain.TransactionSplit) error {
+	db := getQueryer(ctx, r.db)
+	query := `DELETE FROM transaction_splits WHERE transaction_id = $1`
+	if _, err := db.ExecContext(ctx, query, transactionID); err != nil {
+		return err
+	}
+
+	query = `INSERT INTO transaction_splits (transaction_id, category_id, amount, notes) VALUES ($1, $2, $3, $4)`
+	for _, split := range splits {
+		if _, err := db.ExecContext(ctx, query, transactionID, split.CategoryID, split.Amount, split.Notes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (r *transactionRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
 	db := getQueryer(ctx, r.db)
-	query := `DELETE FROM transactions WHERE id = $1 AND user_id = $2`
-	_, err := db.ExecContext(ctx, query, id, userID)
+	query := `DELETE FROM transactions WHERE id = $1 AND transactions.workspace_id = $3 AND ` + memberOf("transactions.workspace_id", "$2", true)
+	res, err := db.ExecContext(ctx, query, id, userID, workspaceFromContext(ctx, userID))
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return errors.New(constant.ErrNotFound)
+	}
 
-	return err
+	return nil
 }
 
 func (r *transactionRepository) Insert(ctx context.Context, data *domain.TransactionDB) error {
 	db := getQueryer(ctx, r.db)
+	data.WorkspaceID = workspaceFromContext(ctx, data.UserID)
+	if err := authorizeWorkspace(ctx, db, data.WorkspaceID, data.UserID, true); err != nil {
+		return err
+	}
+	if err := authorizeCategory(ctx, db, "transaction_categories", "transactions", uuid.Nil, data.CategoryID, data.WorkspaceID); err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO transactions (user_id, asset_id, liability_id, category_id, amount, transaction_date, notes) 
-		VALUES (:user_id, :asset_id, :liability_id, :category_id, :amount, :transaction_date, :notes)
+		INSERT INTO transactions (user_id, workspace_id, asset_id, liability_id, category_id, amount, transaction_date, notes) 
+		VALUES (:user_id, :workspace_id, :asset_id, :liability_id, :category_id, :amount, :transaction_date, :notes)
+		RETURNING id
 	`
-	_, err := db.NamedExecContext(ctx, query, data)
+	rows, err := db.NamedQueryContext(ctx, query, data)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		err = rows.Scan(&data.ID)
+	}
 
 	return err
 }
 
 func (r *transactionRepository) Update(ctx context.Context, data *domain.TransactionDB) error {
 	db := getQueryer(ctx, r.db)
+	data.WorkspaceID = workspaceFromContext(ctx, data.UserID)
+	if err := authorizeWorkspace(ctx, db, data.WorkspaceID, data.UserID, true); err != nil {
+		return err
+	}
+	if err := authorizeCategory(ctx, db, "transaction_categories", "transactions", data.ID, data.CategoryID, data.WorkspaceID); err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE transactions 
 		SET asset_id = :asset_id, liability_id = :liability_id, category_id = :category_id, amount = :amount, transaction_date = :transaction_date, notes = :notes, updated_at = now() 
-		WHERE id = :id AND user_id = :user_id
-	`
-	_, err := db.NamedExecContext(ctx, query, data)
+		WHERE id = :id
+			AND transactions.workspace_id = :workspace_id
+			AND ` + memberOf("transactions.workspace_id", ":user_id", true)
+	res, err := db.NamedExecContext(ctx, query, data)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return errors.New(constant.ErrNotFound)
+	}
 
-	return err
+	return nil
 }
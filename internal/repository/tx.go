@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"sync/atomic"
 
 	"github.com/fazriegi/netbase-be/internal/domain"
 	"github.com/jmoiron/sqlx"
@@ -18,7 +20,14 @@ func NewTransactionManager(db *sqlx.DB) domain.TransactionManager {
 	return &sqlxTxManager{db: db}
 }
 
+// WithTransaction runs fn in a database transaction. Called inside another
+// transaction it runs fn under a savepoint instead, so a failing fn only rolls
+// back its own changes and the outer transaction can carry on.
 func (m *sqlxTxManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return withSavepoint(ctx, tx, fn)
+	}
+
 	tx, err := m.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
@@ -33,6 +42,25 @@ func (m *sqlxTxManager) WithTransaction(ctx context.Context, fn func(ctx context
 	return tx.Commit()
 }
 
+var savepointSeq atomic.Uint64
+
+func withSavepoint(ctx context.Context, tx *sqlx.Tx, fn func(ctx context.Context) error) error {
+	name := fmt.Sprintf("sp_%d", savepointSeq.Add(1))
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+
+	if err := fn(ctx); err != nil {
+		if _, rbErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			return rbErr
+		}
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
+
 // sqlxQueryer defines methods implemented by both *sqlx.DB and *sqlx.Tx
 type sqlxQueryer interface {
 	sqlx.ExtContext
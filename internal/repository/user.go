@@ -23,16 +23,16 @@ func NewUserRepository(db *sqlx.DB) domain.UserRepository {
 
 func (r *userRepo) Create(ctx context.Context, user *domain.User) (uuid.UUID, error) {
 	db := getQueryer(ctx, r.db)
-	query := `INSERT INTO users (username, email, password, full_name) VALUES ($1, $2, $3, $4) RETURNING id`
+	query := `INSERT INTO users (username, email, email_verified, password, full_name) VALUES ($1, NULLIF($2, ''), $3, $4, $5) RETURNING id`
 	var userId uuid.UUID
-	err := db.QueryRowContext(ctx, query, user.Username, user.Email, user.Password, user.FullName).Scan(&userId)
+	err := db.QueryRowContext(ctx, query, user.Username, user.Email, user.EmailVerified, user.Password, user.FullName).Scan(&userId)
 	return userId, err
 }
 
 func (r *userRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	db := getQueryer(ctx, r.db)
 	var user domain.User
-	query := `SELECT id, username, email, password, full_name FROM users WHERE email = $1`
+	query := `SELECT id, username, COALESCE(email, '') AS email, email_verified, password, full_name FROM users WHERE email = $1`
 	err := db.GetContext(ctx, &user, query, email)
 	if err == sql.ErrNoRows {
 		return nil, errors.New(constant.ErrUserNotFound)
@@ -43,7 +43,7 @@ func (r *userRepo) GetByEmail(ctx context.Context, email string) (*domain.User,
 func (r *userRepo) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
 	db := getQueryer(ctx, r.db)
 	var user domain.User
-	query := `SELECT id, username, email, password, full_name FROM users WHERE username = $1`
+	query := `SELECT id, username, COALESCE(email, '') AS email, email_verified, password, full_name FROM users WHERE username = $1`
 	err := db.GetContext(ctx, &user, query, username)
 	if err == sql.ErrNoRows {
 		return nil, errors.New(constant.ErrUserNotFound)
@@ -54,7 +54,7 @@ func (r *userRepo) GetByUsername(ctx context.Context, username string) (*domain.
 func (r *userRepo) GetByID(ctx context.Context, userId uuid.UUID) (*domain.User, error) {
 	db := getQueryer(ctx, r.db)
 	var user domain.User
-	query := `SELECT id, username, email, password, full_name FROM users WHERE id = $1`
+	query := `SELECT id, username, COALESCE(email, '') AS email, email_verified, password, full_name FROM users WHERE id = $1`
 	err := db.GetContext(ctx, &user, query, userId)
 	if err == sql.ErrNoRows {
 		return nil, errors.New(constant.ErrUserNotFound)
@@ -145,6 +145,20 @@ func (r *userRepo) RemoveExpiredToken(ctx context.Context, userID *uuid.UUID) er
 	return err
 }
 
+// CreatePersonalWorkspace creates the user's private workspace, which reuses the user's id.
+func (r *userRepo) CreatePersonalWorkspace(ctx context.Context, userID uuid.UUID) error {
+	db := getQueryer(ctx, r.db)
+	query := `INSERT INTO workspaces (id, name, owner_id, is_personal) VALUES ($1, 'Personal', $1, TRUE)`
+	if _, err := db.ExecContext(ctx, query, userID); err != nil {
+		return err
+	}
+
+	query = `INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $1, 'owner')`
+	_, err := db.ExecContext(ctx, query, userID)
+
+	return err
+}
+
 func (r *userRepo) SeedDefaultCategories(ctx context.Context, userID uuid.UUID) error {
 	db := getQueryer(ctx, r.db)
 	defaultAssets := []domain.Category{
@@ -212,3 +226,72 @@ func (r *userRepo) SeedDefaultCategories(ctx context.Context, userID uuid.UUID)
 
 	return nil
 }
+
+func (r *userRepo) GetLoginAttempt(ctx context.Context, keyType, keyValue string) (*domain.LoginAttempt, error) {
+	db := getQueryer(ctx, r.db)
+	var attempt domain.LoginAttempt
+	query := `
+		SELECT key_type, key_value, failed_count, last_failed_at, locked_until
+		FROM login_attempts
+		WHERE key_type = $1 AND key_value = $2
+	`
+	err := db.GetContext(ctx, &attempt, query, keyType, keyValue)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(constant.ErrNotFound)
+	}
+
+	return &attempt, err
+}
+
+// IncrementLoginFailure counts a failed login. The count restarts once a
+// window has passed since both the last failure and the end of the last
+// lockout, so failing again right after a lockout keeps escalating it.
+func (r *userRepo) IncrementLoginFailure(ctx context.Context, keyType, keyValue string, window time.Duration) (int, error) {
+	db := getQueryer(ctx, r.db)
+	query := `
+		INSERT INTO login_attempts (key_type, key_value, failed_count, last_failed_at)
+		VALUES ($1, $2, 1, now())
+		ON CONFLICT (key_type, key_value)
+		DO UPDATE SET
+			failed_count = CASE
+				WHEN login_attempts.last_failed_at < now() - make_interval(secs => $3)
+					AND (login_attempts.locked_until IS NULL OR login_attempts.locked_until < now() - make_interval(secs => $3))
+				THEN 1
+				ELSE login_attempts.failed_count + 1
+			END,
+			last_failed_at = now()
+		RETURNING failed_count
+	`
+
+	var count int
+	err := db.QueryRowContext(ctx, query, keyType, keyValue, window.Seconds()).Scan(&count)
+
+	return count, err
+}
+
+func (r *userRepo) LockLogin(ctx context.Context, keyType, keyValue string, until time.Time) error {
+	db := getQueryer(ctx, r.db)
+	query := `UPDATE login_attempts SET locked_until = $3 WHERE key_type = $1 AND key_value = $2`
+	_, err := db.ExecContext(ctx, query, keyType, keyValue, until)
+
+	return err
+}
+
+func (r *userRepo) ResetLoginAttempt(ctx context.Context, keyType, keyValue string) error {
+	db := getQueryer(ctx, r.db)
+	query := `DELETE FROM login_attempts WHERE key_type = $1 AND key_value = $2`
+	_, err := db.ExecContext(ctx, query, keyType, keyValue)
+
+	return err
+}
+
+func (r *userRepo) InsertAuthAudit(ctx context.Context, data domain.AuthAuditLog) error {
+	db := getQueryer(ctx, r.db)
+	query := `
+		INSERT INTO auth_audit_logs (user_id, username, ip_address, event)
+		VALUES (:user_id, :username, :ip_address, :event)
+	`
+	_, err := db.NamedExecContext(ctx, query, data)
+
+	return err
+}
@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type workspaceRepository struct {
+	db *sqlx.DB
+}
+
+func NewWorkspaceRepository(db *sqlx.DB) domain.WorkspaceRepository {
+	return &workspaceRepository{db: db}
+}
+
+// workspaceFromContext returns the workspace selected for the request,
+// defaulting to the user's personal workspace which shares the user's id.
+func workspaceFromContext(ctx context.Context, userID uuid.UUID) uuid.UUID {
+	if id, ok := ctx.Value("workspace_id").(uuid.UUID); ok && id != uuid.Nil {
+		return id
+	}
+	return userID
+}
+
+// memberOf builds a SQL condition that holds when userParam is a member of the
+// workspace in column, with write access if write is set.
+func memberOf(column, userParam string, write bool) string {
+	roles := []string{domain.RoleOwner, domain.RoleEditor}
+	if !write {
+		roles = append(roles, domain.RoleViewer)
+	}
+
+	return fmt.Sprintf(
+		`EXISTS (SELECT 1 FROM workspace_members wm WHERE wm.workspace_id = %s AND wm.user_id = %s AND wm.role IN ('%s'))`,
+		column, userParam, strings.Join(roles, "', '"),
+	)
+}
+
+// authorizeWorkspace returns constant.ErrNotAuthorized if the user can't access the workspace.
+func authorizeWorkspace(ctx context.Context, db Queryer, workspaceID, userID uuid.UUID, write bool) error {
+	var ok bool
+	query := fmt.Sprintf(`SELECT %s`, memberOf("$1", "$2", write))
+	if err := db.QueryRowContext(ctx, query, workspaceID, userID).Scan(&ok); err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.New(constant.ErrNotAuthorized)
+	}
+	return nil
+}
+
+// authorizeCategory checks that categoryID in table belongs to a member of the
+// workspace, as the workspace lists and filters show it, or is the category
+// the row rowID of owner already has, so editing a shared item can keep the
+// category of a member who has since left.
+func authorizeCategory(ctx context.Context, db Queryer, table, owner string, rowID, categoryID, workspaceID uuid.UUID) error {
+	var ok bool
+	query := fmt.Sprintf(`
+		SELECT EXISTS (
+				SELECT 1 FROM %s
+				WHERE id = $1 AND user_id IN (SELECT user_id FROM workspace_members WHERE workspace_id = $2)
+			)
+			OR EXISTS (SELECT 1 FROM %s WHERE id = $3 AND category_id = $1)
+	`, table, owner)
+	if err := db.QueryRowContext(ctx, query, categoryID, workspaceID, rowID).Scan(&ok); err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.New(constant.ErrInvalidCategory)
+	}
+	return nil
+}
+
+func (r *workspaceRepository) List(ctx context.Context, userID uuid.UUID) (*[]domain.Workspace, error) {
+	db := getQueryer(ctx, r.db)
+	var workspaces = make([]domain.Workspace, 0)
+	query := `
+		SELECT w.id, w.name, w.owner_id, w.is_personal, wm.role, w.created_at
+		FROM workspaces w
+		JOIN workspace_members wm ON wm.workspace_id = w.id
+		WHERE wm.user_id = $1
+		ORDER BY w.is_personal DESC, w.name ASC
+	`
+	err := db.SelectContext(ctx, &workspaces, query, userID)
+
+	return &workspaces, err
+}
+
+func (r *workspaceRepository) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Workspace, error) {
+	db := getQueryer(ctx, r.db)
+	var workspace domain.Workspace
+	query := `
+		SELECT w.id, w.name, w.owner_id, w.is_personal, wm.role, w.created_at
+		FROM workspaces w
+		JOIN workspace_members wm ON wm.workspace_id = w.id
+		WHERE w.id = $1 AND wm.user_id = $2
+	`
+	err := db.GetContext(ctx, &workspace, query, id, userID)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(constant.ErrNotFound)
+	}
+
+	return &workspace, err
+}
+
+func (r *workspaceRepository) Insert(ctx context.Context, data *domain.Workspace) (uuid.UUID, error) {
+	db := getQueryer(ctx, r.db)
+	query := `INSERT INTO workspaces (name, owner_id, is_personal) VALUES ($1, $2, $3) RETURNING id`
+
+	var id uuid.UUID
+	err := db.QueryRowContext(ctx, query, data.Name, data.OwnerID, data.IsPersonal).Scan(&id)
+
+	return id, err
+}
+
+func (r *workspaceRepository) Update(ctx context.Context, data *domain.Workspace) error {
+	db := getQueryer(ctx, r.db)
+	query := `UPDATE workspaces SET name = $1, updated_at = now() WHERE id = $2`
+	_, err := db.ExecContext(ctx, query, data.Name, data.ID)
+
+	return err
+}
+
+func (r *workspaceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	db := getQueryer(ctx, r.db)
+	query := `DELETE FROM workspaces WHERE id = $1 AND is_personal = FALSE`
+	_, err := db.ExecContext(ctx, query, id)
+
+	if err != nil && strings.Contains(err.Error(), "violates foreign key constraint") {
+		return errors.New("violates foreign key constraint")
+	}
+
+	return err
+}
+
+func (r *workspaceRepository) ListMembers(ctx context.Context, workspaceID uuid.UUID) (*[]domain.WorkspaceMember, error) {
+	db := getQueryer(ctx, r.db)
+	var members = make([]domain.WorkspaceMember, 0)
+	query := `
+		SELECT wm.workspace_id, wm.user_id, u.username, u.full_name, wm.role, wm.created_at
+		FROM workspace_members wm
+		JOIN users u ON u.id = wm.user_id
+		WHERE wm.workspace_id = $1
+		ORDER BY wm.created_at ASC
+	`
+	err := db.SelectContext(ctx, &members, query, workspaceID)
+
+	return &members, err
+}
+
+func (r *workspaceRepository) InsertMember(ctx context.Context, workspaceID, userID uuid.UUID, role string) error {
+	db := getQueryer(ctx, r.db)
+	query := `
+		INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, $3)
+		ON CONFLICT (workspace_id, user_id) DO NOTHING
+	`
+	_, err := db.ExecContext(ctx, query, workspaceID, userID, role)
+
+	return err
+}
+
+func (r *workspaceRepository) UpdateMemberRole(ctx context.Context, workspaceID, userID uuid.UUID, role string) error {
+	db := getQueryer(ctx, r.db)
+	query := `UPDATE workspace_members SET role = $3 WHERE workspace_id = $1 AND user_id = $2 AND role <> 'owner'`
+	res, err := db.ExecContext(ctx, query, workspaceID, userID, role)
+	if err != nil {
+		return err
+	}
+
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return errors.New(constant.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *workspaceRepository) DeleteMember(ctx context.Context, workspaceID, userID uuid.UUID) error {
+	db := getQueryer(ctx, r.db)
+	query := `DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2 AND role <> 'owner'`
+	res, err := db.ExecContext(ctx, query, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return errors.New(constant.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *workspaceRepository) InsertInvitation(ctx context.Context, data *domain.WorkspaceInvitation) error {
+	db := getQueryer(ctx, r.db)
+	query := `
+		INSERT INTO workspace_invitations (workspace_id, invited_by, invitee_id, role, expires_at)
+		VALUES (:workspace_id, :invited_by, :invitee_id, :role, :expires_at)
+	`
+	_, err := db.NamedExecContext(ctx, query, data)
+
+	return err
+}
+
+func (r *workspaceRepository) ListInvitations(ctx context.Context, inviteeID uuid.UUID) (*[]domain.WorkspaceInvitation, error) {
+	db := getQueryer(ctx, r.db)
+	var invitations = make([]domain.WorkspaceInvitation, 0)
+	query := `
+		SELECT wi.id, wi.workspace_id, w.name as workspace_name, wi.invited_by, wi.invitee_id, wi.role, wi.status,
+			wi.expires_at, wi.created_at
+		FROM workspace_invitations wi
+		JOIN workspaces w ON w.id = wi.workspace_id
+		WHERE wi.invitee_id = $1
+			AND wi.status = 'pending'
+			AND wi.expires_at > now()
+		ORDER BY wi.created_at DESC
+	`
+	err := db.SelectContext(ctx, &invitations, query, inviteeID)
+
+	return &invitations, err
+}
+
+func (r *workspaceRepository) GetInvitation(ctx context.Context, id, inviteeID uuid.UUID) (*domain.WorkspaceInvitation, error) {
+	db := getQueryer(ctx, r.db)
+	var invitation domain.WorkspaceInvitation
+	query := `
+		SELECT wi.id, wi.workspace_id, w.name as workspace_name, wi.invited_by, wi.invitee_id, wi.role, wi.status,
+			wi.expires_at, wi.created_at
+		FROM workspace_invitations wi
+		JOIN workspaces w ON w.id = wi.workspace_id
+		WHERE wi.id = $1 AND wi.invitee_id = $2
+	`
+	err := db.GetContext(ctx, &invitation, query, id, inviteeID)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(constant.ErrNotFound)
+	}
+
+	return &invitation, err
+}
+
+func (r *workspaceRepository) UpdateInvitationStatus(ctx context.Context, id uuid.UUID, status string) error {
+	db := getQueryer(ctx, r.db)
+	query := `UPDATE workspace_invitations SET status = $2 WHERE id = $1`
+	_, err := db.ExecContext(ctx, query, id, status)
+
+	return err
+}
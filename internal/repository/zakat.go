@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+type zakatRepository struct {
+	db *sqlx.DB
+}
+
+func NewZakatRepository(db *sqlx.DB) domain.ZakatRepository {
+	return &zakatRepository{db: db}
+}
+
+// ListCategoryBalances sums the current workspace's active assets per
+// category. A category without its own flag is zakatable unless physical.
+func (r *zakatRepository) ListCategoryBalances(ctx context.Context, userID uuid.UUID) (*[]domain.ZakatCategory, error) {
+	db := getQueryer(ctx, r.db)
+	var balances = make([]domain.ZakatCategory, 0)
+	query := `
+		SELECT ac.id AS category_id, ac.name, CAST(ac.base_type AS text) AS base_type, SUM(a.current_value) AS value,
+			COALESCE(ac.is_zakatable, ac.base_type <> 'physical') AS is_zakatable
+		FROM assets a
+		JOIN asset_categories ac ON ac.id = a.category_id
+		WHERE a.workspace_id = $2 AND a.is_active = TRUE
+			AND ` + memberOf("a.workspace_id", "$1", false) + `
+		GROUP BY ac.id, ac.name, ac.base_type, ac.is_zakatable
+		ORDER BY ac.base_type, ac.name
+	`
+	err := db.SelectContext(ctx, &balances, query, userID, workspaceFromContext(ctx, userID))
+
+	return &balances, err
+}
+
+// GetDebtsDue sums the outstanding short-term liabilities of the current workspace.
+func (r *zakatRepository) GetDebtsDue(ctx context.Context, userID uuid.UUID) (decimal.Decimal, error) {
+	db := getQueryer(ctx, r.db)
+	var total decimal.Decimal
+	query := `
+		SELECT COALESCE(SUM(l.remaining_balance), 0)
+		FROM liabilities l
+		JOIN liability_categories lc ON lc.id = l.category_id
+		WHERE l.workspace_id = $2 AND l.remaining_balance > 0 AND lc.base_type = 'short_term'
+			AND ` + memberOf("l.workspace_id", "$1", false) + `
+	`
+	err := db.GetContext(ctx, &total, query, userID, workspaceFromContext(ctx, userID))
+
+	return total, err
+}
+
+// GetHaulHistory reads the first snapshot of the current workspace that
+// recorded its zakatable wealth, and the lowest zakatable wealth since from.
+func (r *zakatRepository) GetHaulHistory(ctx context.Context, userID uuid.UUID, from time.Time) (*domain.HaulHistory, error) {
+	db := getQueryer(ctx, r.db)
+	var history domain.HaulHistory
+	query := `
+		SELECT MIN(recorded_date) AS tracked_since,
+			MIN(zakatable_wealth) FILTER (WHERE recorded_date >= $3) AS lowest_zakatable_wealth
+		FROM net_worth_histories
+		WHERE workspace_id = $2 AND zakatable_wealth IS NOT NULL
+			AND ` + memberOf("workspace_id", "$1", false) + `
+	`
+	err := db.GetContext(ctx, &history, query, userID, workspaceFromContext(ctx, userID), from)
+
+	return &history, err
+}
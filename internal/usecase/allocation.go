@@ -0,0 +1,311 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"slices"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// defaultAllocationTolerance is the drift, in percentage points, a target
+// allows when none is given.
+var defaultAllocationTolerance = decimal.NewFromInt(5)
+
+type allocationUsecase struct {
+	log          *log.Logger
+	repo         domain.AllocationRepository
+	networthRepo domain.NetworthRepository
+	categoryRepo domain.CategoryRepository
+	txManager    domain.TransactionManager
+}
+
+type AllocationUsecase interface {
+	ListTargets(ctx context.Context) (resp pkg.Response)
+	SetTargets(ctx context.Context, req *domain.SetAllocationTargets) (resp pkg.Response)
+	Rebalance(ctx context.Context, req *domain.RebalanceRequest) (resp pkg.Response)
+}
+
+func NewAllocationUsecase(
+	log *log.Logger,
+	repo domain.AllocationRepository,
+	networthRepo domain.NetworthRepository,
+	categoryRepo domain.CategoryRepository,
+	txManager domain.TransactionManager,
+) AllocationUsecase {
+	return &allocationUsecase{log, repo, networthRepo, categoryRepo, txManager}
+}
+
+// baseTypeName names a base type target after its balance sheet section.
+func baseTypeName(baseType string) string {
+	i := slices.IndexFunc(assetSections, func(s domain.StatementSection) bool { return s.BaseType == baseType })
+	if i < 0 {
+		return baseType
+	}
+	return assetSections[i].Name
+}
+
+func (u *allocationUsecase) ListTargets(ctx context.Context) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	targets, err := u.repo.ListTargets(ctx, userID)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.ListTargets: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	for i := range *targets {
+		if target := &(*targets)[i]; target.BaseType != nil {
+			target.Name = baseTypeName(*target.BaseType)
+		}
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", targets, nil)
+}
+
+func (u *allocationUsecase) validateTargets(ctx context.Context, req *domain.SetAllocationTargets) (resp pkg.Response, ok bool) {
+	if len(req.Targets) == 0 {
+		return resp, true
+	}
+
+	balances, err := u.networthRepo.ListAssetCategoryBalances(ctx, req.UserID)
+	if err != nil {
+		u.log.Printf("[ERROR] networthRepo.ListAssetCategoryBalances: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil), false
+	}
+
+	hundred := decimal.NewFromInt(100)
+	var total decimal.Decimal
+	seen := make(map[string]bool, len(req.Targets))
+	for i := range req.Targets {
+		target := &req.Targets[i]
+
+		if (target.CategoryID == nil) == (target.BaseType == "") {
+			return pkg.NewResponse(http.StatusBadRequest, "Each target needs either a category_id or a base_type", nil, nil), false
+		}
+
+		if !target.TargetPercent.IsPositive() || target.TargetPercent.GreaterThan(hundred) {
+			return pkg.NewResponse(http.StatusBadRequest, "target_percent must be greater than 0 and at most 100", nil, nil), false
+		}
+		total = total.Add(*target.TargetPercent)
+
+		if target.TolerancePercent == nil {
+			target.TolerancePercent = &defaultAllocationTolerance
+		}
+		if target.TolerancePercent.IsNegative() || target.TolerancePercent.GreaterThan(hundred) {
+			return pkg.NewResponse(http.StatusBadRequest, "tolerance_percent must be between 0 and 100", nil, nil), false
+		}
+
+		key := target.BaseType
+		if target.CategoryID != nil {
+			key = target.CategoryID.String()
+		}
+		if seen[key] {
+			return pkg.NewResponse(http.StatusBadRequest, "Each category and base type can only have one target", nil, nil), false
+		}
+		seen[key] = true
+
+		if target.CategoryID == nil {
+			continue
+		}
+
+		// a category held in the workspace may belong to another member
+		held := slices.ContainsFunc(*balances, func(b domain.AssetCategoryBalance) bool { return b.CategoryID == *target.CategoryID })
+		if held {
+			continue
+		}
+
+		if _, err := u.categoryRepo.GetByID(ctx, domain.CategoryKindAsset, *target.CategoryID, req.UserID); err != nil {
+			if err.Error() == constant.ErrNotFound {
+				return pkg.NewResponse(http.StatusBadRequest, "Invalid asset category ID", nil, nil), false
+			}
+
+			u.log.Printf("[ERROR] categoryRepo.GetByID: %s", err.Error())
+			return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil), false
+		}
+	}
+
+	if !total.Equal(hundred) {
+		return pkg.NewResponse(http.StatusBadRequest, "Targets must add up to 100, got "+total.String(), nil, nil), false
+	}
+
+	return resp, true
+}
+
+func (u *allocationUsecase) SetTargets(ctx context.Context, req *domain.SetAllocationTargets) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	if resp, ok := u.validateTargets(ctx, req); !ok {
+		return resp
+	}
+
+	err := u.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		return u.repo.ReplaceTargets(txCtx, req)
+	})
+	if err != nil {
+		if err.Error() == constant.ErrNotAuthorized {
+			return pkg.NewResponse(http.StatusForbidden, constant.ErrNotAuthorized, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.ReplaceTargets: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
+}
+
+// Rebalance compares the targets with the current value of the assets they
+// cover and suggests what to buy or sell to get back on target.
+func (u *allocationUsecase) Rebalance(ctx context.Context, req *domain.RebalanceRequest) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	if req.NewMoney.IsNegative() {
+		return pkg.NewResponse(http.StatusBadRequest, "new_money can't be negative", nil, nil)
+	}
+	if req.OnlyBuy && !req.NewMoney.IsPositive() {
+		return pkg.NewResponse(http.StatusBadRequest, "only_buy needs new_money to invest", nil, nil)
+	}
+
+	targets, err := u.repo.ListTargets(ctx, userID)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.ListTargets: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+	if len(*targets) == 0 {
+		return pkg.NewResponse(http.StatusBadRequest, "Set allocation targets first", nil, nil)
+	}
+
+	balances, err := u.networthRepo.ListAssetCategoryBalances(ctx, userID)
+	if err != nil {
+		u.log.Printf("[ERROR] networthRepo.ListAssetCategoryBalances: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", rebalance(*targets, *balances, req.NewMoney, req.OnlyBuy), nil)
+}
+
+// rebalance fills one bucket per target. A category counts toward its own
+// target, else toward the target of its base type, else it is excluded.
+func rebalance(targets []domain.AllocationTarget, balances []domain.AssetCategoryBalance, newMoney decimal.Decimal, onlyBuy bool) *domain.Rebalance {
+	hundred := decimal.NewFromInt(100)
+	result := &domain.Rebalance{
+		NewMoney: newMoney,
+		OnlyBuy:  onlyBuy,
+		Buckets:  make([]domain.AllocationBucket, len(targets)),
+		Excluded: make([]domain.AllocationExcluded, 0),
+	}
+
+	byCategory := make(map[uuid.UUID]int)
+	byBaseType := make(map[string]int)
+	for i, target := range targets {
+		result.Buckets[i] = domain.AllocationBucket{
+			CategoryID:       target.CategoryID,
+			BaseType:         target.BaseType,
+			Name:             target.Name,
+			TargetPercent:    target.TargetPercent,
+			TolerancePercent: target.TolerancePercent,
+		}
+		if target.CategoryID != nil {
+			byCategory[*target.CategoryID] = i
+		} else if target.BaseType != nil {
+			result.Buckets[i].Name = baseTypeName(*target.BaseType)
+			byBaseType[*target.BaseType] = i
+		}
+	}
+
+	for _, balance := range balances {
+		i, ok := byCategory[balance.CategoryID]
+		if !ok {
+			i, ok = byBaseType[balance.BaseType]
+		}
+		if !ok {
+			result.Excluded = append(result.Excluded, domain.AllocationExcluded{
+				CategoryID: balance.CategoryID,
+				Name:       balance.Name,
+				BaseType:   balance.BaseType,
+				Value:      balance.Value,
+			})
+			continue
+		}
+
+		result.Buckets[i].CurrentValue = result.Buckets[i].CurrentValue.Add(balance.Value)
+		result.PortfolioValue = result.PortfolioValue.Add(balance.Value)
+	}
+
+	total := result.PortfolioValue.Add(newMoney)
+	for i := range result.Buckets {
+		bucket := &result.Buckets[i]
+		if result.PortfolioValue.IsPositive() {
+			bucket.CurrentPercent = bucket.CurrentValue.Div(result.PortfolioValue).Mul(hundred).Round(2)
+		}
+		bucket.Drift = bucket.CurrentPercent.Sub(bucket.TargetPercent)
+		bucket.TargetValue = total.Mul(bucket.TargetPercent).Div(hundred).Round(2)
+
+		bucket.Status = domain.AllocationWithin
+		switch {
+		case bucket.Drift.GreaterThan(bucket.TolerancePercent):
+			bucket.Status = domain.AllocationOver
+		case bucket.Drift.Neg().GreaterThan(bucket.TolerancePercent):
+			bucket.Status = domain.AllocationUnder
+		}
+		if bucket.Status != domain.AllocationWithin {
+			result.NeedsRebalance = true
+		}
+	}
+
+	// signed trade per bucket, positive to buy
+	trades := make([]decimal.Decimal, len(result.Buckets))
+	switch {
+	case onlyBuy:
+		// the new money goes to the buckets below target in proportion to
+		// how far below they are; they always fall short by at least as
+		// much as is added
+		var shortfall decimal.Decimal
+		for _, bucket := range result.Buckets {
+			shortfall = shortfall.Add(decimal.Max(bucket.TargetValue.Sub(bucket.CurrentValue), decimal.Zero))
+		}
+		if shortfall.IsPositive() {
+			largest := 0
+			var allocated decimal.Decimal
+			for i, bucket := range result.Buckets {
+				gap := decimal.Max(bucket.TargetValue.Sub(bucket.CurrentValue), decimal.Zero)
+				trades[i] = newMoney.Mul(gap).Div(shortfall).Round(2)
+				allocated = allocated.Add(trades[i])
+				if trades[i].GreaterThan(trades[largest]) {
+					largest = i
+				}
+			}
+			// rounding leftovers go to the biggest buy
+			trades[largest] = trades[largest].Add(newMoney.Sub(allocated))
+		}
+	case result.NeedsRebalance || newMoney.IsPositive():
+		for i, bucket := range result.Buckets {
+			trades[i] = bucket.TargetValue.Sub(bucket.CurrentValue)
+		}
+	}
+
+	for i := range result.Buckets {
+		bucket := &result.Buckets[i]
+		bucket.Action = domain.AllocationHold
+		switch {
+		case trades[i].IsPositive():
+			bucket.Action = domain.AllocationBuy
+		case trades[i].IsNegative():
+			bucket.Action = domain.AllocationSell
+		}
+		bucket.Amount = trades[i].Abs()
+
+		if total.IsPositive() {
+			bucket.AfterPercent = bucket.CurrentValue.Add(trades[i]).Div(total).Mul(hundred).Round(2)
+		}
+	}
+
+	return result
+}
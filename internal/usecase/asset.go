@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"log"
-	"math"
 	"net/http"
 	"sync"
 
@@ -22,7 +21,6 @@ type assetUsecase struct {
 
 type AssetUsecase interface {
 	ListAsset(ctx context.Context, req *domain.ListAssetRequest) (resp pkg.Response)
-	ListAssetCategory(ctx context.Context) (resp pkg.Response)
 	GetByID(ctx context.Context, id uuid.UUID) (resp pkg.Response)
 	Delete(ctx context.Context, id uuid.UUID) (resp pkg.Response)
 	Create(ctx context.Context, req *domain.CreateAsset) (resp pkg.Response)
@@ -38,8 +36,16 @@ func (u *assetUsecase) ListAsset(ctx context.Context, req *domain.ListAssetReque
 	userId := ctx.Value("user_id").(uuid.UUID)
 	req.UserId = userId
 
-	assets, total, err := u.repo.ListAsset(ctx, req)
+	if req.LimitTooLarge() {
+		return pkg.NewResponse(http.StatusBadRequest, constant.ErrLimitTooLarge, nil, nil)
+	}
+
+	assets, page, err := u.repo.ListAsset(ctx, req)
 	if err != nil {
+		if err.Error() == constant.ErrInvalidCursor {
+			return pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidCursor, nil, nil)
+		}
+
 		u.log.Printf("[ERROR] repo.ListAsset: %s", err.Error())
 		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
 	}
@@ -58,42 +64,7 @@ func (u *assetUsecase) ListAsset(ctx context.Context, req *domain.ListAssetReque
 		}
 	}
 
-	var paginationMeta pkg.PaginationMeta
-	if req.Limit != nil && *req.Limit > 0 {
-		limit := int(*req.Limit)
-		page := 1
-
-		if req.Page != nil && *req.Page > 0 {
-			page = int(*req.Page)
-		}
-
-		totalPages := int(math.Ceil(float64(total) / float64(limit)))
-
-		if totalPages > 0 && page > totalPages {
-			page = totalPages
-		}
-
-		paginationMeta = pkg.PaginationMeta{
-			Page:       page,
-			Limit:      limit,
-			Total:      int(total),
-			TotalPages: totalPages,
-		}
-	}
-
-	return pkg.NewResponse(http.StatusOK, "Success", dataResponse, &paginationMeta)
-}
-
-func (u *assetUsecase) ListAssetCategory(ctx context.Context) (resp pkg.Response) {
-	userId := ctx.Value("user_id").(uuid.UUID)
-
-	categories, err := u.repo.ListCategory(ctx, userId)
-	if err != nil {
-		u.log.Printf("[ERROR] repo.ListCategory: %s", err.Error())
-		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
-	}
-
-	return pkg.NewResponse(http.StatusOK, "Success", categories, nil)
+	return pkg.NewResponse(http.StatusOK, "Success", dataResponse, pkg.NewPaginationMeta(&req.PaginationRequest, page))
 }
 
 func (u *assetUsecase) GetByID(ctx context.Context, id uuid.UUID) (resp pkg.Response) {
@@ -144,6 +115,13 @@ func (u *assetUsecase) Delete(ctx context.Context, id uuid.UUID) (resp pkg.Respo
 
 	err := u.repo.Delete(ctx, id, userId)
 	if err != nil {
+		if err.Error() == constant.ErrNotAuthorized {
+			return pkg.NewResponse(http.StatusForbidden, constant.ErrNotAuthorized, nil, nil)
+		}
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
 		u.log.Printf("[ERROR] repo.Delete: %s", err.Error())
 		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
 	}
@@ -176,6 +154,13 @@ func (u *assetUsecase) Create(ctx context.Context, req *domain.CreateAsset) (res
 
 	err := u.repo.Insert(ctx, assetDB)
 	if err != nil {
+		if err.Error() == constant.ErrNotAuthorized {
+			return pkg.NewResponse(http.StatusForbidden, constant.ErrNotAuthorized, nil, nil)
+		}
+		if err.Error() == constant.ErrInvalidCategory {
+			return pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidCategory, nil, nil)
+		}
+
 		u.log.Printf("[ERROR] repo.Insert: %s", err.Error())
 		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
 	}
@@ -209,6 +194,16 @@ func (u *assetUsecase) Update(ctx context.Context, req *domain.UpdateAsset) (res
 
 	err := u.repo.Update(ctx, assetDB)
 	if err != nil {
+		if err.Error() == constant.ErrNotAuthorized {
+			return pkg.NewResponse(http.StatusForbidden, constant.ErrNotAuthorized, nil, nil)
+		}
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+		if err.Error() == constant.ErrInvalidCategory {
+			return pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidCategory, nil, nil)
+		}
+
 		u.log.Printf("[ERROR] repo.Update: %s", err.Error())
 		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
 	}
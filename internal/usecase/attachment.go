@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/fazriegi/netbase-be/pkg/env"
+	"github.com/google/uuid"
+)
+
+type attachmentUsecase struct {
+	log       *log.Logger
+	repo      domain.AttachmentRepository
+	blobs     domain.BlobStore
+	txManager domain.TransactionManager
+	config    domain.AttachmentConfig
+}
+
+type AttachmentUsecase interface {
+	List(ctx context.Context, transactionID uuid.UUID) (resp pkg.Response)
+	Upload(ctx context.Context, req *domain.UploadAttachment) (resp pkg.Response)
+	Download(ctx context.Context, transactionID, id uuid.UUID) (resp pkg.Response)
+	Delete(ctx context.Context, transactionID, id uuid.UUID) (resp pkg.Response)
+}
+
+func NewAttachmentUsecase(
+	log *log.Logger,
+	repo domain.AttachmentRepository,
+	blobs domain.BlobStore,
+	txManager domain.TransactionManager,
+	config domain.AttachmentConfig,
+) AttachmentUsecase {
+	return &attachmentUsecase{log, repo, blobs, txManager, config}
+}
+
+// AttachmentConfigFromEnv reads the upload limits, allowing receipt photos and PDFs.
+func AttachmentConfigFromEnv() domain.AttachmentConfig {
+	return domain.AttachmentConfig{
+		MaxSize:      int64(env.GetInt("ATTACHMENT_MAX_SIZE_MB", 10)) << 20,
+		AllowedTypes: []string{"image/jpeg", "image/png", "image/webp", "application/pdf"},
+	}
+}
+
+// attachmentFileName keeps only the base name of an uploaded file, capped to
+// what the column can hold.
+func attachmentFileName(name string) string {
+	name = strings.ToValidUTF8(strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, `\`, "/"))), "")
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[:255])
+	}
+	return name
+}
+
+func (u *attachmentUsecase) List(ctx context.Context, transactionID uuid.UUID) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	attachments, err := u.repo.ListByTransaction(ctx, transactionID, userID)
+	if err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.ListByTransaction: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", attachments, nil)
+}
+
+func (u *attachmentUsecase) Upload(ctx context.Context, req *domain.UploadAttachment) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	if req.Size <= 0 {
+		return pkg.NewResponse(http.StatusBadRequest, "Attachment is empty", nil, nil)
+	}
+
+	if req.Size > u.config.MaxSize {
+		return pkg.NewResponse(http.StatusRequestEntityTooLarge, fmt.Sprintf("Attachment exceeds the %d MB limit", u.config.MaxSize>>20), nil, nil)
+	}
+
+	// the type is sniffed from the content, the client's Content-Type is not trusted
+	head := make([]byte, 512)
+	n, err := io.ReadFull(req.Content, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		u.log.Printf("[ERROR] read attachment: %s", err.Error())
+		return pkg.NewResponse(http.StatusBadRequest, "Could not read the attachment", nil, nil)
+	}
+	head = head[:n]
+
+	contentType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	if !slices.Contains(u.config.AllowedTypes, contentType) {
+		return pkg.NewResponse(http.StatusUnsupportedMediaType, "Only JPEG, PNG or WebP images and PDF files can be attached", nil, nil)
+	}
+
+	attachment := &domain.Attachment{
+		ID:            uuid.New(),
+		TransactionID: req.TransactionID,
+		UserID:        userID,
+		FileName:      attachmentFileName(req.FileName),
+		ContentType:   contentType,
+		SizeBytes:     req.Size,
+	}
+	attachment.StorageKey = attachment.TransactionID.String() + "/" + attachment.ID.String()
+
+	err = u.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := u.repo.Insert(txCtx, attachment); err != nil {
+			return err
+		}
+
+		content := io.LimitReader(io.MultiReader(bytes.NewReader(head), req.Content), u.config.MaxSize)
+		return u.blobs.Put(txCtx, attachment.StorageKey, content)
+	})
+	if err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] Upload attachment: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusCreated, "Success", attachment, nil)
+}
+
+// Download opens an attachment the user can read. On success the data is a
+// *domain.AttachmentContent the caller must close.
+func (u *attachmentUsecase) Download(ctx context.Context, transactionID, id uuid.UUID) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	attachment, err := u.repo.GetByID(ctx, id, transactionID, userID, false)
+	if err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.GetByID: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	content, err := u.blobs.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		u.log.Printf("[ERROR] blobs.Open %s: %s", attachment.StorageKey, err.Error())
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", &domain.AttachmentContent{Attachment: *attachment, Content: content}, nil)
+}
+
+func (u *attachmentUsecase) Delete(ctx context.Context, transactionID, id uuid.UUID) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	attachment, err := u.repo.GetByID(ctx, id, transactionID, userID, true)
+	if err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.GetByID: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	if err := u.repo.Delete(ctx, attachment.ID); err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.Delete: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	// the file goes only once the row is gone, a leftover file is harmless
+	// while a row without its file is not
+	if err := u.blobs.Delete(ctx, attachment.StorageKey); err != nil {
+		u.log.Printf("[ERROR] blobs.Delete %s: %s", attachment.StorageKey, err.Error())
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
+}
@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+)
+
+type categoryUsecase struct {
+	log       *log.Logger
+	repo      domain.CategoryRepository
+	txManager domain.TransactionManager
+}
+
+type CategoryUsecase interface {
+	List(ctx context.Context, req *domain.ListCategoryRequest) (resp pkg.Response)
+	Create(ctx context.Context, req *domain.CreateCategory) (resp pkg.Response)
+	Update(ctx context.Context, req *domain.UpdateCategory) (resp pkg.Response)
+	Delete(ctx context.Context, kind string, id uuid.UUID) (resp pkg.Response)
+	Merge(ctx context.Context, req *domain.MergeCategory) (resp pkg.Response)
+}
+
+func NewCategoryUsecase(log *log.Logger, repo domain.CategoryRepository, txManager domain.TransactionManager) CategoryUsecase {
+	return &categoryUsecase{log, repo, txManager}
+}
+
+func (u *categoryUsecase) List(ctx context.Context, req *domain.ListCategoryRequest) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	categories, err := u.repo.List(ctx, req)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.List: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	// a search result is returned flat, its matches rarely form a tree
+	if domain.CategoryHierarchical(req.Kind) && !req.Flat && req.Search == "" {
+		tree := buildCategoryTree(*categories)
+		return pkg.NewResponse(http.StatusOK, "Success", tree, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", categories, nil)
+}
+
+// buildCategoryTree nests categories under their parents. A category whose
+// parent isn't in the list, e.g. because the parent is archived, becomes a root.
+func buildCategoryTree(categories []domain.Category) []domain.Category {
+	present := make(map[uuid.UUID]bool, len(categories))
+	for _, c := range categories {
+		present[c.Id] = true
+	}
+
+	children := make(map[uuid.UUID][]domain.Category)
+	roots := make([]domain.Category, 0)
+	for _, c := range categories {
+		if c.ParentID != nil && present[*c.ParentID] {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+			continue
+		}
+		roots = append(roots, c)
+	}
+
+	var attach func(nodes []domain.Category) []domain.Category
+	attach = func(nodes []domain.Category) []domain.Category {
+		for i := range nodes {
+			nodes[i].Children = attach(children[nodes[i].Id])
+		}
+		return nodes
+	}
+
+	return attach(roots)
+}
+
+// checkParent validates moving category id (uuid.Nil when creating) under parentID.
+func (u *categoryUsecase) checkParent(ctx context.Context, kind string, id uuid.UUID, parentID *uuid.UUID, baseType string, userID uuid.UUID) (resp pkg.Response, ok bool) {
+	if parentID == nil {
+		return resp, true
+	}
+
+	if !domain.CategoryHierarchical(kind) {
+		return pkg.NewResponse(http.StatusBadRequest, "This category type can't be nested", nil, nil), false
+	}
+
+	if *parentID == id {
+		return pkg.NewResponse(http.StatusBadRequest, constant.ErrCategoryParentCycle, nil, nil), false
+	}
+
+	parent, err := u.repo.GetByID(ctx, kind, *parentID, userID)
+	if err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusBadRequest, "Parent category not found", nil, nil), false
+		}
+
+		u.log.Printf("[ERROR] repo.GetByID: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil), false
+	}
+
+	if parent.BaseType != baseType {
+		return pkg.NewResponse(http.StatusBadRequest, constant.ErrCategoryBaseMismatch, nil, nil), false
+	}
+
+	if id == uuid.Nil {
+		return resp, true
+	}
+
+	cycle, err := u.repo.IsDescendant(ctx, kind, *parentID, id)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.IsDescendant: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil), false
+	}
+
+	if cycle {
+		return pkg.NewResponse(http.StatusBadRequest, constant.ErrCategoryParentCycle, nil, nil), false
+	}
+
+	return resp, true
+}
+
+func (u *categoryUsecase) Create(ctx context.Context, req *domain.CreateCategory) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+	req.Name = strings.TrimSpace(req.Name)
+
+	if baseTypes := domain.CategoryBaseTypes[req.Kind]; !slices.Contains(baseTypes, req.BaseType) {
+		msg := fmt.Sprintf("base_type must be one of: %s", strings.Join(baseTypes, ", "))
+		return pkg.NewResponse(http.StatusBadRequest, msg, nil, nil)
+	}
+
+	if resp, ok := u.checkParent(ctx, req.Kind, uuid.Nil, req.ParentID, req.BaseType, userID); !ok {
+		return resp
+	}
+
+	category, err := u.repo.Insert(ctx, req)
+	if err != nil {
+		if err.Error() == constant.ErrCategoryExists {
+			return pkg.NewResponse(http.StatusConflict, constant.ErrCategoryExists, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.Insert: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusCreated, "Success", category, nil)
+}
+
+func (u *categoryUsecase) Update(ctx context.Context, req *domain.UpdateCategory) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+	req.Name = strings.TrimSpace(req.Name)
+
+	current, err := u.repo.GetByID(ctx, req.Kind, req.ID, userID)
+	if err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.GetByID: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	if resp, ok := u.checkParent(ctx, req.Kind, req.ID, req.ParentID, current.BaseType, userID); !ok {
+		return resp
+	}
+
+	category, err := u.repo.Update(ctx, req)
+	if err != nil {
+		switch err.Error() {
+		case constant.ErrNotFound:
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		case constant.ErrCategoryExists:
+			return pkg.NewResponse(http.StatusConflict, constant.ErrCategoryExists, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.Update: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", category, nil)
+}
+
+func (u *categoryUsecase) Delete(ctx context.Context, kind string, id uuid.UUID) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	err := u.repo.Delete(ctx, kind, id, userID)
+	if err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
+		if strings.Contains(err.Error(), "violates foreign key constraint") {
+			return pkg.NewResponse(http.StatusBadRequest, "Category is still in use, merge it into another category or archive it instead", nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.Delete: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
+}
+
+func (u *categoryUsecase) Merge(ctx context.Context, req *domain.MergeCategory) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	if req.SourceID == req.TargetID {
+		return pkg.NewResponse(http.StatusBadRequest, "Cannot merge a category into itself", nil, nil)
+	}
+
+	var (
+		target *domain.Category
+		moved  int64
+	)
+	err := u.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		source, err := u.repo.GetByID(txCtx, req.Kind, req.SourceID, userID)
+		if err != nil {
+			return err
+		}
+
+		target, err = u.repo.GetByID(txCtx, req.Kind, req.TargetID, userID)
+		if err != nil {
+			return err
+		}
+
+		// details and cashflow effects depend on the base type, so rows can't
+		// move across it
+		if source.BaseType != target.BaseType {
+			return errors.New(constant.ErrCategoryBaseMismatch)
+		}
+
+		// the source's children move under the target, which must not be one of them
+		cycle, err := u.repo.IsDescendant(txCtx, req.Kind, req.TargetID, req.SourceID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return errors.New(constant.ErrCategoryParentCycle)
+		}
+
+		moved, err = u.repo.Merge(txCtx, req.Kind, req.SourceID, req.TargetID)
+		return err
+	})
+	if err != nil {
+		switch err.Error() {
+		case constant.ErrNotFound:
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		case constant.ErrCategoryBaseMismatch, constant.ErrCategoryParentCycle:
+			return pkg.NewResponse(http.StatusBadRequest, err.Error(), nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.Merge: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", domain.MergeCategoryResponse{
+		Target:    *target,
+		MovedRows: moved,
+	}, nil)
+}
@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type dateRange struct {
+	from, to time.Time
+}
+
+func (r dateRange) period() domain.ComparisonPeriod {
+	return domain.ComparisonPeriod{From: r.from.Format(time.DateOnly), To: r.to.Format(time.DateOnly)}
+}
+
+// calendarPeriod returns the month, quarter or year date falls in.
+func calendarPeriod(period string, date time.Time) dateRange {
+	var from time.Time
+	var months int
+	switch period {
+	case domain.ComparePeriodQuarter:
+		from = time.Date(date.Year(), (date.Month()-1)/3*3+1, 1, 0, 0, 0, 0, time.UTC)
+		months = 3
+	case domain.ComparePeriodYear:
+		from = time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		months = 12
+	default:
+		from = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+		months = 1
+	}
+
+	return dateRange{from: from, to: from.AddDate(0, months, -1)}
+}
+
+func parseDateRange(fromStr, toStr, name string) (dateRange, error) {
+	from, errFrom := time.Parse(time.DateOnly, fromStr)
+	to, errTo := time.Parse(time.DateOnly, toStr)
+	if errFrom != nil || errTo != nil {
+		return dateRange{}, &BusinessError{Message: "Invalid " + name + " date format. Expected YYYY-MM-DD"}
+	}
+	if to.Before(from) {
+		return dateRange{}, &BusinessError{Message: name + " range can't end before it starts"}
+	}
+
+	return dateRange{from: from, to: to}, nil
+}
+
+// comparisonRanges resolves the two periods of a comparison request.
+func comparisonRanges(req *domain.ComparisonRequest) (current, previous dateRange, err error) {
+	if req.Period == "" {
+		req.Period = domain.ComparePeriodMonth
+	}
+	if req.Compare == "" {
+		req.Compare = domain.CompareWithPrevious
+	}
+
+	if req.Compare != domain.CompareWithPrevious && req.Compare != domain.CompareWithYearAgo {
+		return current, previous, &BusinessError{Message: "compare must be previous or year_ago"}
+	}
+
+	switch req.Period {
+	case domain.ComparePeriodCustom:
+		if req.From == "" || req.To == "" {
+			return current, previous, &BusinessError{Message: "A custom period needs from and to"}
+		}
+		if current, err = parseDateRange(req.From, req.To, "current"); err != nil {
+			return current, previous, err
+		}
+
+		if req.CompareFrom != "" || req.CompareTo != "" {
+			previous, err = parseDateRange(req.CompareFrom, req.CompareTo, "compare")
+			return current, previous, err
+		}
+
+		if req.Compare == domain.CompareWithYearAgo {
+			return current, dateRange{from: current.from.AddDate(-1, 0, 0), to: current.to.AddDate(-1, 0, 0)}, nil
+		}
+
+		// the range of the same length right before
+		days := int(current.to.Sub(current.from).Hours()/24) + 1
+		return current, dateRange{from: current.from.AddDate(0, 0, -days), to: current.from.AddDate(0, 0, -1)}, nil
+
+	case domain.ComparePeriodMonth, domain.ComparePeriodQuarter, domain.ComparePeriodYear:
+		date := time.Now()
+		if req.Date != "" {
+			if date, err = time.Parse(time.DateOnly, req.Date); err != nil {
+				return current, previous, &BusinessError{Message: "Invalid date format. Expected YYYY-MM-DD"}
+			}
+		}
+		current = calendarPeriod(req.Period, date)
+
+		switch {
+		case req.CompareDate != "":
+			compareDate, err := time.Parse(time.DateOnly, req.CompareDate)
+			if err != nil {
+				return current, previous, &BusinessError{Message: "Invalid compare_date format. Expected YYYY-MM-DD"}
+			}
+			previous = calendarPeriod(req.Period, compareDate)
+		case req.Compare == domain.CompareWithYearAgo:
+			previous = calendarPeriod(req.Period, current.from.AddDate(-1, 0, 0))
+		default:
+			previous = calendarPeriod(req.Period, current.from.AddDate(0, 0, -1))
+		}
+
+		return current, previous, nil
+
+	default:
+		return current, previous, &BusinessError{Message: "period must be month, quarter, year or custom"}
+	}
+}
+
+func compareValues(current, previous decimal.Decimal) domain.ComparisonValue {
+	value := domain.ComparisonValue{
+		Current:  current,
+		Previous: previous,
+		Change:   current.Sub(previous),
+	}
+
+	if !previous.IsZero() {
+		percent := value.Change.Div(previous.Abs()).Mul(decimal.NewFromInt(100)).Round(2)
+		value.ChangePercent = &percent
+	}
+
+	return value
+}
+
+// comparisonTotals is what a comparison takes from one period.
+type comparisonTotals struct {
+	income, expense, assets, liabilities decimal.Decimal
+	categories, assetCategories          map[uuid.UUID]decimal.Decimal
+}
+
+func (u *reportUsecase) comparisonTotals(ctx context.Context, userID uuid.UUID, r dateRange, names map[uuid.UUID]string) (*comparisonTotals, error) {
+	totals := &comparisonTotals{
+		categories:      make(map[uuid.UUID]decimal.Decimal),
+		assetCategories: make(map[uuid.UUID]decimal.Decimal),
+	}
+
+	filter := domain.ListTransactionRequest{
+		UserID:       userID,
+		FilterType:   "range",
+		StartDateStr: r.from.Format(time.DateOnly),
+		EndDateStr:   r.to.Format(time.DateOnly),
+	}
+
+	rows, err := u.repo.GetIncomeStatement(ctx, &filter)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.GetIncomeStatement: %s", err.Error())
+		return nil, err
+	}
+
+	for _, row := range *rows {
+		if row.BaseType == "income" {
+			totals.income = totals.income.Add(row.Amount)
+			continue
+		}
+		totals.expense = totals.expense.Add(row.Amount)
+		totals.categories[row.CategoryID] = totals.categories[row.CategoryID].Add(row.Amount)
+		names[row.CategoryID] = row.Category
+	}
+
+	rows, err = u.repo.GetBalanceSheet(ctx, userID, r.to)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.GetBalanceSheet: %s", err.Error())
+		return nil, err
+	}
+
+	for _, row := range *rows {
+		if row.Kind != "asset" {
+			totals.liabilities = totals.liabilities.Add(row.Amount)
+			continue
+		}
+		totals.assets = totals.assets.Add(row.Amount)
+		totals.assetCategories[row.CategoryID] = totals.assetCategories[row.CategoryID].Add(row.Amount)
+		names[row.CategoryID] = row.Category
+	}
+
+	return totals, nil
+}
+
+// compareLines pairs the amounts of every key of either period, largest
+// current amount first.
+func compareLines(current, previous map[uuid.UUID]decimal.Decimal, names map[uuid.UUID]string) []domain.ComparisonLine {
+	lines := make([]domain.ComparisonLine, 0, len(current))
+	for id, amount := range current {
+		lines = append(lines, domain.ComparisonLine{CategoryID: id, Name: names[id], ComparisonValue: compareValues(amount, previous[id])})
+	}
+	for id, amount := range previous {
+		if _, ok := current[id]; !ok {
+			lines = append(lines, domain.ComparisonLine{CategoryID: id, Name: names[id], ComparisonValue: compareValues(decimal.Zero, amount)})
+		}
+	}
+
+	slices.SortFunc(lines, func(a, b domain.ComparisonLine) int {
+		if c := b.Current.Cmp(a.Current); c != 0 {
+			return c
+		}
+		if c := b.Previous.Cmp(a.Previous); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return lines
+}
+
+// GetComparison compares income, expense and spend per category over two
+// periods, and net worth and asset category values at their ends.
+func (u *reportUsecase) GetComparison(ctx context.Context, req *domain.ComparisonRequest) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	current, previous, err := comparisonRanges(req)
+	if err != nil {
+		return pkg.NewResponse(http.StatusBadRequest, err.Error(), nil, nil)
+	}
+
+	names := make(map[uuid.UUID]string)
+	cur, err := u.comparisonTotals(ctx, userID, current, names)
+	if err != nil {
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+	prev, err := u.comparisonTotals(ctx, userID, previous, names)
+	if err != nil {
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	comparison := domain.Comparison{
+		Current:          current.period(),
+		Previous:         previous.period(),
+		Income:           compareValues(cur.income, prev.income),
+		Expense:          compareValues(cur.expense, prev.expense),
+		Net:              compareValues(cur.income.Sub(cur.expense), prev.income.Sub(prev.expense)),
+		TotalAssets:      compareValues(cur.assets, prev.assets),
+		TotalLiabilities: compareValues(cur.liabilities, prev.liabilities),
+		NetWorth:         compareValues(cur.assets.Sub(cur.liabilities), prev.assets.Sub(prev.liabilities)),
+		Categories:       compareLines(cur.categories, prev.categories, names),
+		AssetCategories:  compareLines(cur.assetCategories, prev.assetCategories, names),
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", comparison, nil)
+}
@@ -0,0 +1,418 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// forecastDefaultSeed seeds the simulations of a request without a seed, so
+// the same request always gets the same percentiles.
+const forecastDefaultSeed = 1
+
+// forecastRate is an annual return and its standard deviation, in percent.
+type forecastRate struct {
+	annualReturn, volatility float64
+}
+
+// forecastDefaults are assumed for a base type the request sets nothing for.
+var forecastDefaults = map[string]forecastRate{
+	"liquid":     {annualReturn: 2, volatility: 0.5},
+	"investment": {annualReturn: 7, volatility: 15},
+	"physical":   {annualReturn: 0, volatility: 5},
+}
+
+// monthly converts the rate to a monthly mean return and standard deviation.
+func (r forecastRate) monthly() (mean, stddev float64) {
+	return math.Pow(1+r.annualReturn/100, 1.0/12) - 1, r.volatility / 100 / math.Sqrt(12)
+}
+
+type forecastHolding struct {
+	value        float64
+	mean, stddev float64
+}
+
+// forecastLoan amortizes monthly. Installments of existing liabilities are
+// already among the recorded expenses, so only new loans are paid from savings.
+type forecastLoan struct {
+	balance, rate, installment float64
+	fromSavings                bool
+}
+
+type forecastPlan struct {
+	months                      int
+	income, expense             float64
+	incomeGrowth, expenseGrowth float64 // monthly factors
+	holdings                    []forecastHolding
+	savingsMean, savingsStddev  float64 // savings earn what liquid assets do
+	loans                       []forecastLoan
+	fixedLiabilities            float64
+	events                      [][]domain.ForecastEvent // by month, from 0
+	purchaseDefault             forecastRate
+}
+
+// run simulates the plan once and writes the net worth of every month to
+// netWorth. Without rng every return is its mean. Months are only detailed in
+// out when it isn't nil.
+func (p *forecastPlan) run(rng *rand.Rand, netWorth []float64, out []domain.ForecastMonth) {
+	holdings := slices.Clone(p.holdings)
+	loans := slices.Clone(p.loans)
+	var savings, recurring float64
+
+	draw := func(mean, stddev float64) float64 {
+		if rng == nil {
+			return mean
+		}
+		return mean + stddev*rng.NormFloat64()
+	}
+
+	for m := range p.months {
+		for i := range holdings {
+			holdings[i].value *= 1 + draw(holdings[i].mean, holdings[i].stddev)
+		}
+		if savings > 0 {
+			savings *= 1 + draw(p.savingsMean, p.savingsStddev)
+		}
+
+		var installments float64
+		for i := range loans {
+			payment := loans[i].pay()
+			if loans[i].fromSavings {
+				installments += payment
+			}
+		}
+
+		for _, event := range p.events[m] {
+			amount := event.Amount.InexactFloat64()
+			switch event.Type {
+			case domain.ForecastEventOneOff:
+				savings += amount
+			case domain.ForecastEventRecurring:
+				recurring += amount
+			case domain.ForecastEventPurchase:
+				rate := p.purchaseDefault
+				if event.AnnualReturn != nil {
+					rate.annualReturn = event.AnnualReturn.InexactFloat64()
+				}
+				mean, stddev := rate.monthly()
+				holdings = append(holdings, forecastHolding{value: amount, mean: mean, stddev: stddev})
+
+				loanAmount := event.LoanAmount.InexactFloat64()
+				savings -= amount - loanAmount
+				if loanAmount > 0 {
+					loans = append(loans, newForecastLoan(loanAmount, event.LoanInterestRate.InexactFloat64(), event.LoanTenor))
+				}
+			}
+		}
+
+		income := p.income * math.Pow(p.incomeGrowth, float64(m+1))
+		expense := p.expense * math.Pow(p.expenseGrowth, float64(m+1))
+		savings += income - expense - installments + recurring
+
+		// savings that ran dry are owed, not owned
+		assets := max(savings, 0)
+		liabilities := p.fixedLiabilities + max(-savings, 0)
+		for _, holding := range holdings {
+			assets += holding.value
+		}
+		for _, loan := range loans {
+			liabilities += max(loan.balance, 0)
+		}
+		netWorth[m] = assets - liabilities
+
+		if out != nil {
+			out[m].Income = forecastAmount(income + max(recurring, 0))
+			out[m].Expense = forecastAmount(expense + installments + max(-recurring, 0))
+			out[m].Savings = forecastAmount(savings)
+			out[m].TotalAssets = forecastAmount(assets)
+			out[m].TotalLiabilities = forecastAmount(liabilities)
+			out[m].NetWorth = forecastAmount(netWorth[m])
+		}
+	}
+}
+
+// simulate runs the plan n times from seed and returns the P10, P50 and P90
+// net worth of every month.
+func (p *forecastPlan) simulate(n int, seed uint64) []domain.ForecastPercentiles {
+	rng := rand.New(rand.NewPCG(seed, seed))
+
+	netWorth := make([]float64, p.months)
+	outcomes := make([][]float64, p.months)
+	for m := range outcomes {
+		outcomes[m] = make([]float64, n)
+	}
+	for i := range n {
+		p.run(rng, netWorth, nil)
+		for m, value := range netWorth {
+			outcomes[m][i] = value
+		}
+	}
+
+	percentiles := make([]domain.ForecastPercentiles, p.months)
+	for m, values := range outcomes {
+		slices.Sort(values)
+		percentiles[m] = domain.ForecastPercentiles{
+			P10: forecastAmount(percentile(values, 10)),
+			P50: forecastAmount(percentile(values, 50)),
+			P90: forecastAmount(percentile(values, 90)),
+		}
+	}
+	return percentiles
+}
+
+// pay books one month of the loan and returns what was paid.
+func (l *forecastLoan) pay() float64 {
+	if l.balance <= 0 {
+		return 0
+	}
+
+	interest := l.balance * l.rate
+	payment := min(l.installment, l.balance+interest)
+	// an installment that doesn't cover the interest leaves the balance as is
+	l.balance -= max(payment-interest, 0)
+	return payment
+}
+
+// newForecastLoan amortizes principal in equal installments over tenor months.
+func newForecastLoan(principal, annualRate float64, tenor int) forecastLoan {
+	rate := annualRate / 100 / 12
+	installment := principal / float64(tenor)
+	if rate > 0 {
+		installment = principal * rate / (1 - math.Pow(1+rate, -float64(tenor)))
+	}
+
+	return forecastLoan{balance: principal, rate: rate, installment: installment, fromSavings: true}
+}
+
+func forecastAmount(value float64) decimal.Decimal {
+	return decimal.NewFromFloat(value).Round(2)
+}
+
+// percentile picks the nearest-rank percentile p of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[min(max(rank-1, 0), len(sorted)-1)]
+}
+
+// forecastReturns resolves the rate of every held category: a return set for
+// the category, then one set for its base type, then the default. Savings get
+// the rate of liquid assets.
+func forecastReturns(req *domain.ForecastRequest, balances []domain.AssetCategoryBalance) (rates map[uuid.UUID]forecastRate, savings forecastRate, err error) {
+	held := make(map[uuid.UUID]bool, len(balances))
+	for _, balance := range balances {
+		held[balance.CategoryID] = true
+	}
+
+	byCategory := make(map[uuid.UUID]domain.ForecastReturn)
+	byBaseType := make(map[string]domain.ForecastReturn)
+	for _, r := range req.Returns {
+		if !validForecastRate(r.AnnualReturn) {
+			return nil, savings, &BusinessError{Message: "annual_return must be above -100 and at most 1000"}
+		}
+		if r.Volatility != nil && (r.Volatility.IsNegative() || r.Volatility.GreaterThan(decimal.NewFromInt(1000))) {
+			return nil, savings, &BusinessError{Message: "volatility must be between 0 and 1000"}
+		}
+
+		switch {
+		case r.CategoryID != nil:
+			if !held[*r.CategoryID] {
+				return nil, savings, &BusinessError{Message: "Invalid asset category ID: no active asset in it"}
+			}
+			byCategory[*r.CategoryID] = r
+		case r.BaseType != "":
+			byBaseType[r.BaseType] = r
+		default:
+			return nil, savings, &BusinessError{Message: "Each return needs a category_id or base_type"}
+		}
+	}
+
+	resolve := func(baseType string, r domain.ForecastReturn, ok bool) forecastRate {
+		rate := forecastDefaults[baseType]
+		if ok {
+			rate.annualReturn = r.AnnualReturn.InexactFloat64()
+			if r.Volatility != nil {
+				rate.volatility = r.Volatility.InexactFloat64()
+			}
+		}
+		return rate
+	}
+
+	rates = make(map[uuid.UUID]forecastRate, len(balances))
+	for _, balance := range balances {
+		r, ok := byCategory[balance.CategoryID]
+		if !ok {
+			r, ok = byBaseType[balance.BaseType]
+		}
+		rates[balance.CategoryID] = resolve(balance.BaseType, r, ok)
+	}
+
+	r, ok := byBaseType["liquid"]
+	return rates, resolve("liquid", r, ok), nil
+}
+
+// validForecastRate reports whether an annual rate in percent can be
+// compounded monthly: above a total loss and no more than 1000.
+func validForecastRate(rate decimal.Decimal) bool {
+	return rate.GreaterThan(decimal.NewFromInt(-100)) && rate.LessThanOrEqual(decimal.NewFromInt(1000))
+}
+
+func checkForecastRequest(req *domain.ForecastRequest) error {
+	if !validForecastRate(req.IncomeGrowth) || !validForecastRate(req.ExpenseInflation) {
+		return &BusinessError{Message: "income_growth and expense_inflation must be above -100 and at most 1000"}
+	}
+
+	for _, event := range req.Events {
+		if event.Month > req.Months {
+			return &BusinessError{Message: "Event " + event.Name + " is beyond the forecast horizon"}
+		}
+
+		if event.Type != domain.ForecastEventPurchase {
+			continue
+		}
+
+		if event.AnnualReturn != nil && !validForecastRate(*event.AnnualReturn) {
+			return &BusinessError{Message: "The annual_return of purchase " + event.Name + " must be above -100 and at most 1000"}
+		}
+		if !event.Amount.IsPositive() {
+			return &BusinessError{Message: "Purchase " + event.Name + " needs a positive amount"}
+		}
+		if event.LoanAmount.IsNegative() || event.LoanAmount.GreaterThan(event.Amount) {
+			return &BusinessError{Message: "The loan of purchase " + event.Name + " must be between 0 and its amount"}
+		}
+		if event.LoanAmount.IsPositive() && event.LoanTenor == 0 {
+			return &BusinessError{Message: "The loan of purchase " + event.Name + " needs a loan_tenor"}
+		}
+		if event.LoanInterestRate.IsNegative() {
+			return &BusinessError{Message: "The loan of purchase " + event.Name + " can't have a negative interest rate"}
+		}
+	}
+
+	return nil
+}
+
+// Forecast projects the net worth of the current workspace month by month,
+// starting from its balances and the income and expense averages of the last
+// full months. With simulations, returns are drawn at random around their
+// expected value and every month gets the percentiles of the outcomes.
+func (u *networthUsecase) Forecast(ctx context.Context, req *domain.ForecastRequest) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	if err := checkForecastRequest(req); err != nil {
+		return pkg.NewResponse(http.StatusBadRequest, err.Error(), nil, nil)
+	}
+
+	now := time.Now()
+	from, to := lastFullMonths(now, healthMonths)
+
+	inputs, err := u.repo.GetHealthInputs(ctx, userID, from, to)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.GetHealthInputs: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+	averageCashflow(inputs)
+
+	assets, err := u.repo.ListAssetCategoryBalances(ctx, userID)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.ListAssetCategoryBalances: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	liabilities, err := u.repo.ListLiabilityBalances(ctx, userID)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.ListLiabilityBalances: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	rates, savings, err := forecastReturns(req, *assets)
+	if err != nil {
+		return pkg.NewResponse(http.StatusBadRequest, err.Error(), nil, nil)
+	}
+
+	plan := &forecastPlan{
+		months:          req.Months,
+		income:          inputs.AverageIncome.InexactFloat64(),
+		expense:         inputs.AverageExpense.InexactFloat64(),
+		incomeGrowth:    math.Pow(1+req.IncomeGrowth.InexactFloat64()/100, 1.0/12),
+		expenseGrowth:   math.Pow(1+req.ExpenseInflation.InexactFloat64()/100, 1.0/12),
+		events:          make([][]domain.ForecastEvent, req.Months),
+		purchaseDefault: forecastDefaults["physical"],
+	}
+	plan.savingsMean, plan.savingsStddev = savings.monthly()
+
+	baseline := domain.ForecastBaseline{
+		AverageIncome:  inputs.AverageIncome,
+		AverageExpense: inputs.AverageExpense,
+		HistoryFrom:    from.Format(time.DateOnly),
+		HistoryTo:      to.Format(time.DateOnly),
+	}
+
+	for _, balance := range *assets {
+		baseline.TotalAssets = baseline.TotalAssets.Add(balance.Value)
+
+		holding := forecastHolding{value: balance.Value.InexactFloat64()}
+		holding.mean, holding.stddev = rates[balance.CategoryID].monthly()
+		plan.holdings = append(plan.holdings, holding)
+	}
+
+	amortize := req.AmortizeLiabilities == nil || *req.AmortizeLiabilities
+	for _, liability := range *liabilities {
+		baseline.TotalLiabilities = baseline.TotalLiabilities.Add(liability.RemainingBalance)
+
+		if !amortize || liability.BaseType != "long_term" || !liability.MonthlyInstallment.IsPositive() {
+			plan.fixedLiabilities += liability.RemainingBalance.InexactFloat64()
+			continue
+		}
+		plan.loans = append(plan.loans, forecastLoan{
+			balance:     liability.RemainingBalance.InexactFloat64(),
+			rate:        liability.InterestRatePA.InexactFloat64() / 100 / 12,
+			installment: liability.MonthlyInstallment.InexactFloat64(),
+		})
+	}
+	baseline.NetWorth = baseline.TotalAssets.Sub(baseline.TotalLiabilities)
+
+	forecast := domain.Forecast{
+		Baseline:    baseline,
+		Simulations: req.Simulations,
+		Projection:  make([]domain.ForecastMonth, req.Months),
+	}
+
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for m := range forecast.Projection {
+		forecast.Projection[m] = domain.ForecastMonth{
+			Month:  m + 1,
+			Date:   start.AddDate(0, m+2, -1).Format(time.DateOnly),
+			Events: []string{},
+		}
+	}
+	for _, event := range req.Events {
+		plan.events[event.Month-1] = append(plan.events[event.Month-1], event)
+		forecast.Projection[event.Month-1].Events = append(forecast.Projection[event.Month-1].Events, event.Name)
+	}
+
+	netWorth := make([]float64, req.Months)
+	plan.run(nil, netWorth, forecast.Projection)
+
+	if req.Simulations > 0 {
+		forecast.Seed = forecastDefaultSeed
+		if req.Seed != nil {
+			forecast.Seed = *req.Seed
+		}
+
+		percentiles := plan.simulate(req.Simulations, forecast.Seed)
+		for m := range percentiles {
+			forecast.Projection[m].Percentiles = &percentiles[m]
+		}
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", forecast, nil)
+}
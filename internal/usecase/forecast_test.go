@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+)
+
+// testForecastPlan holds one volatile investment and saves a fixed surplus
+// every month for a year.
+func testForecastPlan() *forecastPlan {
+	mean, stddev := forecastRate{annualReturn: 7, volatility: 15}.monthly()
+	savingsMean, savingsStddev := forecastDefaults["liquid"].monthly()
+
+	return &forecastPlan{
+		months:        12,
+		income:        10_000_000,
+		expense:       7_000_000,
+		incomeGrowth:  1,
+		expenseGrowth: 1,
+		holdings:      []forecastHolding{{value: 100_000_000, mean: mean, stddev: stddev}},
+		savingsMean:   savingsMean,
+		savingsStddev: savingsStddev,
+		events:        make([][]domain.ForecastEvent, 12),
+	}
+}
+
+func TestForecastSimulateIsDeterministic(t *testing.T) {
+	got := testForecastPlan().simulate(500, 42)
+	again := testForecastPlan().simulate(500, 42)
+
+	for m := range got {
+		if !got[m].P10.Equal(again[m].P10) || !got[m].P50.Equal(again[m].P50) || !got[m].P90.Equal(again[m].P90) {
+			t.Fatalf("month %d: seed 42 gave %+v, then %+v", m+1, got[m], again[m])
+		}
+	}
+
+	last := got[len(got)-1]
+	want := [3]string{"123982608.51", "141619871.08", "160989050.45"}
+	if last.P10.String() != want[0] || last.P50.String() != want[1] || last.P90.String() != want[2] {
+		t.Errorf("month 12: got P10 %s, P50 %s, P90 %s, want %v", last.P10, last.P50, last.P90, want)
+	}
+
+	if !(last.P10.LessThan(last.P50) && last.P50.LessThan(last.P90)) {
+		t.Errorf("month 12: percentiles out of order: %+v", last)
+	}
+}
+
+func TestForecastSimulateSeedZero(t *testing.T) {
+	zero := testForecastPlan().simulate(500, 0)
+	one := testForecastPlan().simulate(500, 1)
+
+	if zero[11].P50.Equal(one[11].P50) {
+		t.Errorf("seeds 0 and 1 gave the same P50 %s", zero[11].P50)
+	}
+}
+
+func TestForecastLoanAmortizesToZero(t *testing.T) {
+	tests := []struct {
+		name       string
+		principal  float64
+		annualRate float64
+		tenor      int
+	}{
+		{"interest free", 12_000_000, 0, 12},
+		{"with interest", 300_000_000, 9.5, 180},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := newForecastLoan(tt.principal, tt.annualRate, tt.tenor)
+
+			var paid float64
+			for range tt.tenor {
+				paid += loan.pay()
+			}
+
+			if math.Abs(loan.balance) > 0.01 {
+				t.Errorf("balance after %d months is %f, want 0", tt.tenor, loan.balance)
+			}
+			if paid < tt.principal {
+				t.Errorf("paid %f in total, less than the principal %f", paid, tt.principal)
+			}
+			if extra := loan.pay(); extra != 0 {
+				t.Errorf("paid %f after the loan was settled", extra)
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	for p, want := range map[float64]float64{0: 1, 10: 1, 50: 5, 90: 9, 100: 10} {
+		if got := percentile(sorted, p); got != want {
+			t.Errorf("percentile %v: got %v, want %v", p, got, want)
+		}
+	}
+}
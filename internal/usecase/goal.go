@@ -0,0 +1,319 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/fazriegi/netbase-be/pkg/env"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type goalUsecase struct {
+	log       *log.Logger
+	repo      domain.GoalRepository
+	assetRepo domain.AssetRepository
+	txManager domain.TransactionManager
+	config    domain.GoalConfig
+}
+
+type GoalUsecase interface {
+	List(ctx context.Context) (resp pkg.Response)
+	Get(ctx context.Context, id uuid.UUID) (resp pkg.Response)
+	Create(ctx context.Context, req *domain.CreateGoal) (resp pkg.Response)
+	Update(ctx context.Context, req *domain.CreateGoal) (resp pkg.Response)
+	Delete(ctx context.Context, id uuid.UUID) (resp pkg.Response)
+}
+
+func NewGoalUsecase(
+	log *log.Logger,
+	repo domain.GoalRepository,
+	assetRepo domain.AssetRepository,
+	txManager domain.TransactionManager,
+	config domain.GoalConfig,
+) GoalUsecase {
+	return &goalUsecase{log, repo, assetRepo, txManager, config}
+}
+
+// GoalConfigFromEnv reads how many full months of contributions the projection averages.
+func GoalConfigFromEnv() domain.GoalConfig {
+	return domain.GoalConfig{
+		ContributionMonths: env.GetInt("GOAL_CONTRIBUTION_MONTHS", 6),
+	}
+}
+
+func (u *goalUsecase) List(ctx context.Context) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	goals, err := u.repo.List(ctx, userID)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.List: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	if err := u.withProgress(ctx, *goals); err != nil {
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", goals, nil)
+}
+
+func (u *goalUsecase) Get(ctx context.Context, id uuid.UUID) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	goal, err := u.repo.GetByID(ctx, id, userID, false)
+	if err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.GetByID: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	goals := []domain.SavingsGoal{*goal}
+	if err := u.withProgress(ctx, goals); err != nil {
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", goals[0], nil)
+}
+
+func (u *goalUsecase) validate(ctx context.Context, req *domain.CreateGoal, userID uuid.UUID) (targetDate time.Time, resp pkg.Response, ok bool) {
+	targetDate, err := time.Parse("2006-01-02", req.TargetDate)
+	if err != nil {
+		return targetDate, pkg.NewResponse(http.StatusBadRequest, "Invalid date format. Expected YYYY-MM-DD", nil, nil), false
+	}
+
+	if !req.TargetAmount.IsPositive() {
+		return targetDate, pkg.NewResponse(http.StatusBadRequest, "target_amount must be greater than 0", nil, nil), false
+	}
+
+	seen := make(map[uuid.UUID]bool, len(req.AssetIDs))
+	assetIDs := make([]uuid.UUID, 0, len(req.AssetIDs))
+	for _, assetID := range req.AssetIDs {
+		if seen[assetID] {
+			continue
+		}
+		seen[assetID] = true
+
+		if _, err := u.assetRepo.GetByID(ctx, assetID, userID); err != nil {
+			if err.Error() == constant.ErrNotFound {
+				return targetDate, pkg.NewResponse(http.StatusBadRequest, "Invalid asset ID", nil, nil), false
+			}
+
+			u.log.Printf("[ERROR] assetRepo.GetByID: %s", err.Error())
+			return targetDate, pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil), false
+		}
+		assetIDs = append(assetIDs, assetID)
+	}
+	req.AssetIDs = assetIDs
+
+	return targetDate, resp, true
+}
+
+func (u *goalUsecase) Create(ctx context.Context, req *domain.CreateGoal) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	targetDate, resp, ok := u.validate(ctx, req, userID)
+	if !ok {
+		return resp
+	}
+
+	var id uuid.UUID
+	err := u.txManager.WithTransaction(ctx, func(txCtx context.Context) (err error) {
+		id, err = u.repo.Insert(txCtx, req, targetDate)
+		if err != nil {
+			return err
+		}
+
+		return u.repo.SetAssets(txCtx, id, req.AssetIDs)
+	})
+	if err != nil {
+		if err.Error() == constant.ErrNotAuthorized {
+			return pkg.NewResponse(http.StatusForbidden, constant.ErrNotAuthorized, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.Insert: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusCreated, "Success", map[string]any{"id": id}, nil)
+}
+
+func (u *goalUsecase) Update(ctx context.Context, req *domain.CreateGoal) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	targetDate, resp, ok := u.validate(ctx, req, userID)
+	if !ok {
+		return resp
+	}
+
+	err := u.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := u.repo.Update(txCtx, req, targetDate); err != nil {
+			return err
+		}
+
+		return u.repo.SetAssets(txCtx, req.ID, req.AssetIDs)
+	})
+	if err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.Update: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
+}
+
+func (u *goalUsecase) Delete(ctx context.Context, id uuid.UUID) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	if err := u.repo.Delete(ctx, id, userID); err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.Delete: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
+}
+
+// withProgress loads the linked assets of the goals and the contributions of
+// the last full months, and fills in their progress.
+func (u *goalUsecase) withProgress(ctx context.Context, goals []domain.SavingsGoal) error {
+	if len(goals) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from, to := lastFullMonths(now, u.config.ContributionMonths)
+
+	goalIDs := make([]uuid.UUID, len(goals))
+	for i, goal := range goals {
+		goalIDs[i] = goal.ID
+	}
+
+	assets, err := u.repo.ListAssets(ctx, goalIDs)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.ListAssets: %s", err.Error())
+		return err
+	}
+
+	contributions, err := u.repo.ListContributions(ctx, goalIDs, from, to)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.ListContributions: %s", err.Error())
+		return err
+	}
+
+	assetsByGoal := make(map[uuid.UUID][]domain.GoalAsset, len(goals))
+	for _, asset := range *assets {
+		assetsByGoal[asset.GoalID] = append(assetsByGoal[asset.GoalID], asset)
+	}
+
+	contributionByGoal := make(map[uuid.UUID]domain.GoalContribution, len(*contributions))
+	for _, contribution := range *contributions {
+		contributionByGoal[contribution.GoalID] = contribution
+	}
+
+	for i := range goals {
+		goal := &goals[i]
+		goal.Assets = assetsByGoal[goal.ID]
+		if goal.Assets == nil {
+			goal.Assets = []domain.GoalAsset{}
+		}
+
+		var average decimal.Decimal
+		if contribution, ok := contributionByGoal[goal.ID]; ok {
+			// assets saved into for less than the window are averaged over
+			// the months since their first contribution
+			average = contribution.Amount.Div(decimal.NewFromInt(int64(monthsBetween(contribution.FirstMonth, to) + 1))).Round(2)
+		}
+
+		goal.Progress = goalProgress(goal, average, today)
+		goal.Progress.ContributionFrom = from.Format(time.DateOnly)
+		goal.Progress.ContributionTo = to.Format(time.DateOnly)
+	}
+
+	return nil
+}
+
+// maxGoalProjectionMonths bounds how far out a completion date is projected.
+const maxGoalProjectionMonths = 1200
+
+// goalProgress projects when a goal is reached if the average monthly
+// contribution keeps up, and what it takes each month to reach it on time.
+func goalProgress(goal *domain.SavingsGoal, average decimal.Decimal, today time.Time) *domain.GoalProgress {
+	var current decimal.Decimal
+	for _, asset := range goal.Assets {
+		current = current.Add(asset.CurrentValue)
+	}
+
+	progress := &domain.GoalProgress{
+		CurrentAmount:   current,
+		RemainingAmount: decimal.Max(goal.TargetAmount.Sub(current), decimal.Zero),
+		ProgressPercent: decimal.NewFromInt(100),
+		MonthsLeft:      max(monthsBetween(today, goal.TargetDate), 0),
+		AverageMonthly:  average,
+	}
+
+	if goal.TargetAmount.IsPositive() && progress.RemainingAmount.IsPositive() {
+		progress.ProgressPercent = decimal.Max(current, decimal.Zero).Mul(decimal.NewFromInt(100)).Div(goal.TargetAmount).Round(2)
+	}
+
+	if !progress.RemainingAmount.IsPositive() {
+		progress.Status = domain.GoalCompleted
+		projected := today.Format(time.DateOnly)
+		progress.ProjectedCompletionDate = &projected
+		return progress
+	}
+
+	// past the target date the rest is due in one go
+	progress.RequiredMonthly = progress.RemainingAmount.Div(decimal.NewFromInt(int64(max(progress.MonthsLeft, 1)))).Round(2)
+
+	if !average.IsPositive() {
+		progress.Status = domain.GoalNoContributions
+		return progress
+	}
+
+	// a trickle that needs over a century to get there projects nothing
+	months := progress.RemainingAmount.Div(average).Ceil().IntPart()
+	if months > maxGoalProjectionMonths {
+		progress.Status = domain.GoalBehind
+		return progress
+	}
+
+	projectedDate := today.AddDate(0, int(months), 0)
+	projected := projectedDate.Format(time.DateOnly)
+	progress.ProjectedCompletionDate = &projected
+
+	progress.Status = domain.GoalOnTrack
+	if projectedDate.After(goal.TargetDate) {
+		progress.Status = domain.GoalBehind
+	}
+
+	return progress
+}
+
+// monthsBetween counts the whole months from one date to another, negative
+// when to is before from.
+func monthsBetween(from, to time.Time) int {
+	months := (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+	if to.Day() < from.Day() {
+		months--
+	}
+
+	return months
+}
@@ -0,0 +1,315 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/fazriegi/netbase-be/pkg/env"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type insightUsecase struct {
+	log       *log.Logger
+	repo      domain.InsightRepository
+	txManager domain.TransactionManager
+	config    domain.InsightConfig
+}
+
+type InsightUsecase interface {
+	List(ctx context.Context) (resp pkg.Response)
+	GenerateAll(ctx context.Context) error
+}
+
+func NewInsightUsecase(log *log.Logger, repo domain.InsightRepository, txManager domain.TransactionManager, config domain.InsightConfig) InsightUsecase {
+	return &insightUsecase{log, repo, txManager, config}
+}
+
+// InsightConfigFromEnv reads the anomaly detection thresholds, falling back to sane defaults.
+func InsightConfigFromEnv() domain.InsightConfig {
+	return domain.InsightConfig{
+		TrailingMonths:      env.GetInt("INSIGHT_TRAILING_MONTHS", 6),
+		MinHistoryMonths:    env.GetInt("INSIGHT_MIN_HISTORY_MONTHS", 3),
+		SpikeStdDev:         env.GetFloat("INSIGHT_SPIKE_STDDEV", 2),
+		ExpenseLookbackDays: env.GetInt("INSIGHT_EXPENSE_LOOKBACK_DAYS", 180),
+		MinHistoryExpenses:  env.GetInt("INSIGHT_MIN_HISTORY_EXPENSES", 10),
+		LargeExpenseStdDev:  env.GetFloat("INSIGHT_LARGE_EXPENSE_STDDEV", 3),
+		LargeExpenseDays:    env.GetInt("INSIGHT_LARGE_EXPENSE_DAYS", 7),
+		AssetDropDays:       env.GetInt("INSIGHT_ASSET_DROP_DAYS", 7),
+		AssetDropPercent:    env.GetFloat("INSIGHT_ASSET_DROP_PERCENT", 30),
+	}
+}
+
+func (u *insightUsecase) List(ctx context.Context) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	insights, err := u.repo.List(ctx, userID)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.List: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", insights, nil)
+}
+
+// GenerateAll recomputes the insights of every workspace. A failing workspace
+// keeps its previous insights and doesn't stop the others.
+func (u *insightUsecase) GenerateAll(ctx context.Context) error {
+	ids, err := u.repo.ListWorkspaceIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var failed int
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := u.generate(ctx, id, today); err != nil {
+			u.log.Printf("[ERROR] generate insights for workspace %s: %s", id, err.Error())
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("insights failed for %d of %d workspaces", failed, len(ids))
+	}
+	return nil
+}
+
+func (u *insightUsecase) generate(ctx context.Context, workspaceID uuid.UUID, today time.Time) error {
+	spikes, err := u.categorySpikes(ctx, workspaceID, today)
+	if err != nil {
+		return err
+	}
+
+	large, err := u.largeExpenses(ctx, workspaceID, today)
+	if err != nil {
+		return err
+	}
+
+	drops, err := u.assetDrops(ctx, workspaceID, today)
+	if err != nil {
+		return err
+	}
+
+	insights := append(append(spikes, large...), drops...)
+	for i := range insights {
+		insights[i].WorkspaceID = workspaceID
+	}
+
+	return u.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		return u.repo.Replace(txCtx, workspaceID, insights)
+	})
+}
+
+// meanStdDev returns the mean and sample standard deviation of values. A
+// steady series has no deviation at all, so a tenth of the mean stands in as
+// the least deviation that still counts.
+func meanStdDev(values []float64) (mean, stdDev float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	if len(values) > 1 {
+		for _, v := range values {
+			stdDev += (v - mean) * (v - mean)
+		}
+		stdDev = math.Sqrt(stdDev / float64(len(values)-1))
+	}
+
+	return mean, max(stdDev, mean/10)
+}
+
+// categorySpikes compares each category's spend of the current month with
+// the trailing months, counting months without spend as zero.
+func (u *insightUsecase) categorySpikes(ctx context.Context, workspaceID uuid.UUID, today time.Time) ([]domain.Insight, error) {
+	month := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	from := month.AddDate(0, -u.config.TrailingMonths, 0)
+
+	rows, err := u.repo.ListCategoryMonthSpend(ctx, workspaceID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	type categorySpend struct {
+		name   string
+		months map[time.Time]decimal.Decimal
+	}
+	categories := make(map[uuid.UUID]*categorySpend)
+	order := make([]uuid.UUID, 0)
+	for _, row := range *rows {
+		spend, ok := categories[row.CategoryID]
+		if !ok {
+			spend = &categorySpend{name: row.CategoryName, months: make(map[time.Time]decimal.Decimal)}
+			categories[row.CategoryID] = spend
+			order = append(order, row.CategoryID)
+		}
+		spend.months[row.Month.UTC()] = row.Amount
+	}
+
+	insights := make([]domain.Insight, 0)
+	for _, id := range order {
+		spend := categories[id]
+		current, ok := spend.months[month]
+		if !ok {
+			continue
+		}
+
+		history := make([]float64, 0, u.config.TrailingMonths)
+		var active int
+		for m := from; m.Before(month); m = m.AddDate(0, 1, 0) {
+			amount := spend.months[m]
+			if amount.IsPositive() {
+				active++
+			}
+			history = append(history, amount.InexactFloat64())
+		}
+		if active < u.config.MinHistoryMonths {
+			continue
+		}
+
+		mean, stdDev := meanStdDev(history)
+		if stdDev == 0 || current.InexactFloat64() <= mean+u.config.SpikeStdDev*stdDev {
+			continue
+		}
+
+		categoryID := id
+		baseline := decimal.NewFromFloat(mean).Round(2)
+		insights = append(insights, domain.Insight{
+			Type:        domain.InsightCategorySpike,
+			SubjectID:   &categoryID,
+			SubjectName: spend.name,
+			Message: fmt.Sprintf("Spending on %s this month is %s, well above the usual %s per month",
+				spend.name, current.StringFixed(2), baseline.StringFixed(2)),
+			Amount:      current,
+			Baseline:    baseline,
+			Score:       decimal.NewFromFloat((current.InexactFloat64() - mean) / stdDev).Round(2),
+			InsightDate: today,
+		})
+	}
+
+	return insights, nil
+}
+
+// largeExpenses looks for recent expenses far above the workspace's usual
+// expenses that nothing in their category came close to before, so regular
+// big bills like rent aren't reported every month.
+func (u *insightUsecase) largeExpenses(ctx context.Context, workspaceID uuid.UUID, today time.Time) ([]domain.Insight, error) {
+	windowStart := today.AddDate(0, 0, -u.config.LargeExpenseDays+1)
+	from := windowStart.AddDate(0, 0, -u.config.ExpenseLookbackDays)
+
+	expenses, err := u.repo.ListExpenses(ctx, workspaceID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	var history, recent []domain.ExpenseEntry
+	for _, expense := range *expenses {
+		if expense.TransactionDate.Before(windowStart) {
+			history = append(history, expense)
+		} else {
+			recent = append(recent, expense)
+		}
+	}
+
+	insights := make([]domain.Insight, 0)
+	if len(history) < u.config.MinHistoryExpenses {
+		return insights, nil
+	}
+
+	amounts := make([]float64, 0, len(history))
+	for _, expense := range history {
+		amounts = append(amounts, expense.Amount.InexactFloat64())
+	}
+	mean, stdDev := meanStdDev(amounts)
+	baseline := decimal.NewFromFloat(mean).Round(2)
+
+	for _, expense := range recent {
+		amount := expense.Amount.InexactFloat64()
+		if stdDev == 0 || amount <= mean+u.config.LargeExpenseStdDev*stdDev {
+			continue
+		}
+
+		half := expense.Amount.Div(decimal.NewFromInt(2))
+		recurring := false
+		for _, earlier := range history {
+			if earlier.CategoryID == expense.CategoryID && earlier.Amount.GreaterThanOrEqual(half) {
+				recurring = true
+				break
+			}
+		}
+		if recurring {
+			continue
+		}
+
+		transactionID := expense.ID
+		insights = append(insights, domain.Insight{
+			Type:        domain.InsightLargeExpense,
+			SubjectID:   &transactionID,
+			SubjectName: expense.CategoryName,
+			Message: fmt.Sprintf("A one-off %s expense of %s on %s is far above your typical expense of %s",
+				expense.CategoryName, expense.Amount.StringFixed(2), expense.TransactionDate.Format(time.DateOnly), baseline.StringFixed(2)),
+			Amount:      expense.Amount,
+			Baseline:    baseline,
+			Score:       decimal.NewFromFloat((amount - mean) / stdDev).Round(2),
+			InsightDate: expense.TransactionDate,
+		})
+	}
+
+	return insights, nil
+}
+
+// assetDrops reports liquid assets that lost a large share of their value to
+// the transactions of the last few days.
+func (u *insightUsecase) assetDrops(ctx context.Context, workspaceID uuid.UUID, today time.Time) ([]domain.Insight, error) {
+	changes, err := u.repo.ListLiquidAssetChanges(ctx, workspaceID, today.AddDate(0, 0, -u.config.AssetDropDays))
+	if err != nil {
+		return nil, err
+	}
+
+	insights := make([]domain.Insight, 0)
+	for _, change := range *changes {
+		previous := change.CurrentValue.Sub(change.Change)
+		drop := change.Change.Neg()
+		if !previous.IsPositive() || !drop.IsPositive() {
+			continue
+		}
+
+		percent := drop.Div(previous).Mul(decimal.NewFromInt(100)).Round(2)
+		if percent.InexactFloat64() < u.config.AssetDropPercent {
+			continue
+		}
+
+		assetID := change.AssetID
+		insights = append(insights, domain.Insight{
+			Type:        domain.InsightAssetDrop,
+			SubjectID:   &assetID,
+			SubjectName: change.Name,
+			Message: fmt.Sprintf("%s dropped %s%% in the last %d days, from %s to %s",
+				change.Name, percent.String(), u.config.AssetDropDays, previous.StringFixed(2), change.CurrentValue.StringFixed(2)),
+			Amount:      drop,
+			Baseline:    previous,
+			Score:       percent,
+			InsightDate: today,
+		})
+	}
+
+	return insights, nil
+}
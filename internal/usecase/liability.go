@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"log"
-	"math"
 	"net/http"
 
 	"github.com/fazriegi/netbase-be/internal/domain"
@@ -19,7 +18,6 @@ type liabilityUsecase struct {
 }
 
 type LiabilityUsecase interface {
-	ListCategory(ctx context.Context) (resp pkg.Response)
 	Create(ctx context.Context, req *domain.CreateLiability) (resp pkg.Response)
 	List(ctx context.Context, req *domain.ListLiabilityRequest) (resp pkg.Response)
 	GetByID(ctx context.Context, id uuid.UUID) (resp pkg.Response)
@@ -31,18 +29,6 @@ func NewLiabilityUsecase(log *log.Logger, repo domain.LiabilityRepository) Liabi
 	return &liabilityUsecase{log, repo}
 }
 
-func (u *liabilityUsecase) ListCategory(ctx context.Context) (resp pkg.Response) {
-	userId := ctx.Value("user_id").(uuid.UUID)
-
-	categories, err := u.repo.ListCategory(ctx, userId)
-	if err != nil {
-		u.log.Printf("[ERROR] repo.ListCategory: %s", err.Error())
-		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
-	}
-
-	return pkg.NewResponse(http.StatusOK, "Success", categories, nil)
-}
-
 func (u *liabilityUsecase) Create(ctx context.Context, req *domain.CreateLiability) (resp pkg.Response) {
 	userId := ctx.Value("user_id").(uuid.UUID)
 	req.UserId = userId
@@ -68,6 +54,13 @@ func (u *liabilityUsecase) Create(ctx context.Context, req *domain.CreateLiabili
 
 	err := u.repo.Insert(ctx, liabilityDB)
 	if err != nil {
+		if err.Error() == constant.ErrNotAuthorized {
+			return pkg.NewResponse(http.StatusForbidden, constant.ErrNotAuthorized, nil, nil)
+		}
+		if err.Error() == constant.ErrInvalidCategory {
+			return pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidCategory, nil, nil)
+		}
+
 		u.log.Printf("[ERROR] repo.Insert: %s", err.Error())
 		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
 	}
@@ -79,8 +72,16 @@ func (u *liabilityUsecase) List(ctx context.Context, req *domain.ListLiabilityRe
 	userId := ctx.Value("user_id").(uuid.UUID)
 	req.UserId = userId
 
-	liabilities, total, err := u.repo.List(ctx, req)
+	if req.LimitTooLarge() {
+		return pkg.NewResponse(http.StatusBadRequest, constant.ErrLimitTooLarge, nil, nil)
+	}
+
+	liabilities, page, err := u.repo.List(ctx, req)
 	if err != nil {
+		if err.Error() == constant.ErrInvalidCursor {
+			return pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidCursor, nil, nil)
+		}
+
 		u.log.Printf("[ERROR] repo.List: %s", err.Error())
 		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
 	}
@@ -98,30 +99,7 @@ func (u *liabilityUsecase) List(ctx context.Context, req *domain.ListLiabilityRe
 		}
 	}
 
-	var paginationMeta pkg.PaginationMeta
-	if req.Limit != nil && *req.Limit > 0 {
-		limit := int(*req.Limit)
-		page := 1
-
-		if req.Page != nil && *req.Page > 0 {
-			page = int(*req.Page)
-		}
-
-		totalPages := int(math.Ceil(float64(total) / float64(limit)))
-
-		if totalPages > 0 && page > totalPages {
-			page = totalPages
-		}
-
-		paginationMeta = pkg.PaginationMeta{
-			Page:       page,
-			Limit:      limit,
-			Total:      int(total),
-			TotalPages: totalPages,
-		}
-	}
-
-	return pkg.NewResponse(http.StatusOK, "Success", dataResponse, &paginationMeta)
+	return pkg.NewResponse(http.StatusOK, "Success", dataResponse, pkg.NewPaginationMeta(&req.PaginationRequest, page))
 }
 
 func (u *liabilityUsecase) GetByID(ctx context.Context, id uuid.UUID) (resp pkg.Response) {
@@ -193,6 +171,16 @@ func (u *liabilityUsecase) Update(ctx context.Context, req *domain.CreateLiabili
 
 	err := u.repo.Update(ctx, liabilityDB)
 	if err != nil {
+		if err.Error() == constant.ErrNotAuthorized {
+			return pkg.NewResponse(http.StatusForbidden, constant.ErrNotAuthorized, nil, nil)
+		}
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+		if err.Error() == constant.ErrInvalidCategory {
+			return pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidCategory, nil, nil)
+		}
+
 		u.log.Printf("[ERROR] repo.Update: %s", err.Error())
 		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
 	}
@@ -205,6 +193,13 @@ func (u *liabilityUsecase) Delete(ctx context.Context, id uuid.UUID) (resp pkg.R
 
 	err := u.repo.Delete(ctx, id, userId)
 	if err != nil {
+		if err.Error() == constant.ErrNotAuthorized {
+			return pkg.NewResponse(http.StatusForbidden, constant.ErrNotAuthorized, nil, nil)
+		}
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
 		u.log.Printf("[ERROR] repo.Delete: %s", err.Error())
 		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
 	}
@@ -2,13 +2,16 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/fazriegi/netbase-be/internal/domain"
 	"github.com/fazriegi/netbase-be/pkg"
 	"github.com/fazriegi/netbase-be/pkg/constant"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 type networthUsecase struct {
@@ -18,6 +21,8 @@ type networthUsecase struct {
 
 type NetworthUsecase interface {
 	GetCurrent(ctx context.Context) (resp pkg.Response)
+	GetHealth(ctx context.Context) (resp pkg.Response)
+	Forecast(ctx context.Context, req *domain.ForecastRequest) (resp pkg.Response)
 	CalculateDailyNetworth(ctx context.Context) error
 }
 
@@ -45,3 +50,163 @@ func (u *networthUsecase) CalculateDailyNetworth(ctx context.Context) error {
 	return u.repo.Calculate(ctx)
 }
 
+// healthMonths is the number of full months averaged for income and expense.
+const healthMonths = 6
+
+// lastFullMonths returns the first and last day of the given number of full
+// months before the one now is in.
+func lastFullMonths(now time.Time, months int) (from, to time.Time) {
+	to = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	from = time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1-months, 0)
+
+	return from, to
+}
+
+// averageCashflow sets the monthly income and expense averages of in. A young
+// workspace is averaged over the months it has, not the full window.
+func averageCashflow(in *domain.HealthInputs) {
+	if in.ActiveMonths > 0 {
+		months := decimal.NewFromInt(int64(in.ActiveMonths))
+		in.AverageIncome = in.Income.Div(months).Round(2)
+		in.AverageExpense = in.Expense.Div(months).Round(2)
+	}
+}
+
+// healthRule scores a ratio: poor and worse score 0, good and better score
+// 100, values in between are interpolated. Lower is better when good < poor.
+type healthRule struct {
+	key, name, unit string
+	good, poor      float64
+}
+
+var (
+	emergencyFundRule = healthRule{"emergency_fund_months", "Emergency fund", "months", 6, 0}
+	debtToAssetRule   = healthRule{"debt_to_asset", "Debt to asset", "percent", 30, 80}
+	debtServiceRule   = healthRule{"debt_service", "Debt service ratio", "percent", 30, 50}
+	savingsRateRule   = healthRule{"savings_rate", "Savings rate", "percent", 20, 0}
+	liquidityRule     = healthRule{"liquidity", "Liquidity ratio", "percent", 15, 5}
+)
+
+func (r healthRule) unavailable(explanation string) domain.HealthRatio {
+	return domain.HealthRatio{Key: r.key, Name: r.name, Unit: r.unit, Status: domain.HealthUnavailable, Explanation: explanation}
+}
+
+func (r healthRule) rate(value decimal.Decimal, explanation string) domain.HealthRatio {
+	value = value.Round(2)
+	progress := (value.InexactFloat64() - r.poor) / (r.good - r.poor)
+	score := decimal.NewFromFloat(min(max(progress, 0), 1) * 100).Round(2)
+
+	return domain.HealthRatio{
+		Key:         r.key,
+		Name:        r.name,
+		Value:       &value,
+		Unit:        r.unit,
+		Score:       &score,
+		Status:      healthStatus(score),
+		Explanation: explanation,
+	}
+}
+
+func healthStatus(score decimal.Decimal) string {
+	switch {
+	case score.GreaterThanOrEqual(decimal.NewFromInt(80)):
+		return domain.HealthGood
+	case score.GreaterThanOrEqual(decimal.NewFromInt(50)):
+		return domain.HealthFair
+	default:
+		return domain.HealthPoor
+	}
+}
+
+// healthRatios computes the ratios from in, whose averages must be set.
+func healthRatios(in *domain.HealthInputs) []domain.HealthRatio {
+	hundred := decimal.NewFromInt(100)
+	totalAssets := in.LiquidAssets.Add(in.InvestmentAssets).Add(in.PhysicalAssets)
+	totalLiabilities := in.ShortTermLiabilities.Add(in.LongTermLiabilities)
+	netWorth := totalAssets.Sub(totalLiabilities)
+	ratios := make([]domain.HealthRatio, 0, 5)
+
+	if in.AverageExpense.IsPositive() {
+		months := in.LiquidAssets.Div(in.AverageExpense)
+		ratios = append(ratios, emergencyFundRule.rate(months, fmt.Sprintf(
+			"Your liquid assets cover %s months of expenses. Aim for at least 6 months of expenses in cash or savings.",
+			months.StringFixed(1))))
+	} else {
+		ratios = append(ratios, emergencyFundRule.unavailable("There are no expenses in the last 6 months to measure your emergency fund against."))
+	}
+
+	if totalAssets.IsPositive() {
+		percent := totalLiabilities.Div(totalAssets).Mul(hundred)
+		ratios = append(ratios, debtToAssetRule.rate(percent, fmt.Sprintf(
+			"Your debts are %s%% of your assets. Under 30%% is healthy; above 80%% means most of what you own is financed.",
+			percent.StringFixed(1))))
+	} else {
+		ratios = append(ratios, debtToAssetRule.unavailable("There are no assets to weigh your debts against."))
+	}
+
+	if in.AverageIncome.IsPositive() {
+		percent := in.MonthlyInstallments.Div(in.AverageIncome).Mul(hundred)
+		ratios = append(ratios, debtServiceRule.rate(percent, fmt.Sprintf(
+			"Loan installments take %s%% of your monthly income. Keep it under 30%%; above 50%% squeezes everything else. Credit card balances are not included.",
+			percent.StringFixed(1))))
+
+		rate := in.AverageIncome.Sub(in.AverageExpense).Div(in.AverageIncome).Mul(hundred)
+		ratios = append(ratios, savingsRateRule.rate(rate, fmt.Sprintf(
+			"You keep %s%% of your income after expenses. Aim for 20%% or more.",
+			rate.StringFixed(1))))
+	} else {
+		ratios = append(ratios,
+			debtServiceRule.unavailable("There is no income in the last 6 months to weigh your installments against."),
+			savingsRateRule.unavailable("There is no income in the last 6 months to compute a savings rate from."),
+		)
+	}
+
+	if netWorth.IsPositive() {
+		percent := in.LiquidAssets.Div(netWorth).Mul(hundred)
+		ratios = append(ratios, liquidityRule.rate(percent, fmt.Sprintf(
+			"Liquid assets are %s%% of your net worth. Keep at least 15%% within quick reach.",
+			percent.StringFixed(1))))
+	} else {
+		ratios = append(ratios, liquidityRule.unavailable("Your net worth isn't positive, so there is nothing to weigh your liquid assets against."))
+	}
+
+	return ratios
+}
+
+// GetHealth computes the personal finance ratios of the current workspace
+// from its balances and the income and expense of the last full months.
+func (u *networthUsecase) GetHealth(ctx context.Context) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	from, to := lastFullMonths(time.Now(), healthMonths)
+
+	inputs, err := u.repo.GetHealthInputs(ctx, userID, from, to)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.GetHealthInputs: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+	averageCashflow(inputs)
+
+	health := domain.FinancialHealth{
+		Status: domain.HealthUnavailable,
+		From:   from.Format(time.DateOnly),
+		To:     to.Format(time.DateOnly),
+		Inputs: *inputs,
+		Ratios: healthRatios(inputs),
+	}
+
+	var total decimal.Decimal
+	var scored int64
+	for _, ratio := range health.Ratios {
+		if ratio.Score != nil {
+			total = total.Add(*ratio.Score)
+			scored++
+		}
+	}
+	if scored > 0 {
+		health.Score = total.Div(decimal.NewFromInt(scored)).Round(2)
+		health.Status = healthStatus(health.Score)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", health, nil)
+}
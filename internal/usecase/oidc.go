@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/fazriegi/netbase-be/pkg/password"
+	"github.com/google/uuid"
+)
+
+var invalidUsernameChars = regexp.MustCompile(`[^a-z0-9_.-]+`)
+
+type oidcUsecase struct {
+	log       *log.Logger
+	providers map[string]domain.OIDCProvider
+	repo      domain.OIDCRepository
+	userRepo  domain.UserRepository
+	txManager domain.TransactionManager
+}
+
+type OIDCUsecase interface {
+	Login(ctx context.Context, provider string) (resp pkg.Response)
+	Callback(ctx context.Context, req *domain.OIDCCallbackRequest) (resp pkg.Response)
+}
+
+func NewOIDCUsecase(
+	log *log.Logger,
+	providers map[string]domain.OIDCProvider,
+	repo domain.OIDCRepository,
+	userRepo domain.UserRepository,
+	txManager domain.TransactionManager,
+) OIDCUsecase {
+	return &oidcUsecase{log, providers, repo, userRepo, txManager}
+}
+
+func (u *oidcUsecase) Login(ctx context.Context, providerName string) (resp pkg.Response) {
+	provider, ok := u.providers[providerName]
+	if !ok {
+		return pkg.NewResponse(http.StatusNotFound, "Unknown login provider", nil, nil)
+	}
+
+	state, nonce, verifier := randomToken(), randomToken(), randomToken()
+	expiresAt := time.Now().Add(10 * time.Minute)
+
+	err := u.repo.InsertState(ctx, &domain.OIDCLoginState{
+		State:        state,
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    expiresAt,
+	})
+	if err != nil {
+		u.log.Printf("[ERROR] repo.InsertState: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+	redirectURL, err := provider.AuthCodeURL(ctx, state, nonce, base64.RawURLEncoding.EncodeToString(challenge[:]))
+	if err != nil {
+		u.log.Printf("[ERROR] provider.AuthCodeURL: %s", err.Error())
+		return pkg.NewResponse(http.StatusBadGateway, "Login provider is unavailable", nil, nil)
+	}
+
+	// the handler binds state to the browser with a cookie, so a callback carrying
+	// someone else's code and state is rejected
+	return pkg.NewResponse(http.StatusFound, "Success", map[string]any{
+		"redirect_url": redirectURL,
+		"state":        state,
+		"expires_at":   expiresAt,
+	}, nil)
+}
+
+func (u *oidcUsecase) Callback(ctx context.Context, req *domain.OIDCCallbackRequest) (resp pkg.Response) {
+	provider, ok := u.providers[req.Provider]
+	if !ok {
+		return pkg.NewResponse(http.StatusNotFound, "Unknown login provider", nil, nil)
+	}
+
+	loginState, err := u.repo.ConsumeState(ctx, req.State)
+	if err != nil {
+		if err.Error() != constant.ErrNotFound {
+			u.log.Printf("[ERROR] repo.ConsumeState: %s", err.Error())
+			return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+		}
+		return pkg.NewResponse(http.StatusBadRequest, "Invalid or expired login state", nil, nil)
+	}
+
+	if loginState.Provider != req.Provider {
+		return pkg.NewResponse(http.StatusBadRequest, "Invalid or expired login state", nil, nil)
+	}
+
+	claims, err := provider.Exchange(ctx, req.Code, loginState.CodeVerifier)
+	if err != nil {
+		u.log.Printf("[ERROR] provider.Exchange: %s", err.Error())
+		return pkg.NewResponse(http.StatusUnauthorized, constant.ErrInvalidCreds, nil, nil)
+	}
+
+	if claims.Nonce != loginState.Nonce {
+		u.log.Printf("[ERROR] OIDC nonce mismatch for provider %s", req.Provider)
+		return pkg.NewResponse(http.StatusUnauthorized, constant.ErrInvalidCreds, nil, nil)
+	}
+
+	user, err := u.resolveUser(ctx, req.Provider, claims)
+	if err != nil {
+		u.log.Printf("[ERROR] resolveUser: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	ip := remoteIP(req.RemoteAddr)
+	accessToken, refreshToken, err := issueSession(ctx, u.userRepo, u.txManager, user.ID, ip)
+	if err != nil {
+		u.log.Printf("[ERROR] issueSession: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	err = u.userRepo.InsertAuthAudit(ctx, domain.AuthAuditLog{
+		UserID:    &user.ID,
+		Username:  user.Username,
+		IPAddress: ip,
+		Event:     "login_oidc_" + req.Provider,
+	})
+	if err != nil {
+		u.log.Printf("[ERROR] repo.InsertAuthAudit: %s", err.Error())
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Login successful", map[string]any{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"user":          user,
+	}, nil)
+}
+
+// resolveUser finds the user linked to the external identity. Unknown identities are
+// linked to the user owning the same email if both sides verified it, or get a
+// brand new user. An address held by an unverified account isn't claimed, since
+// anyone can sign up locally with someone else's email.
+func (u *oidcUsecase) resolveUser(ctx context.Context, providerName string, claims *domain.OIDCClaims) (*domain.User, error) {
+	identity, err := u.repo.GetIdentity(ctx, providerName, claims.Subject)
+	if err == nil {
+		return u.userRepo.GetByID(ctx, identity.UserID)
+	}
+	if err.Error() != constant.ErrNotFound {
+		return nil, err
+	}
+
+	newIdentity := &domain.UserIdentity{
+		Provider: providerName,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}
+
+	claimEmail := claims.Email != "" && claims.EmailVerified
+	if claimEmail {
+		user, err := u.userRepo.GetByEmail(ctx, claims.Email)
+		if err == nil {
+			if user.EmailVerified {
+				newIdentity.UserID = user.ID
+				return user, u.repo.InsertIdentity(ctx, newIdentity)
+			}
+			claimEmail = false
+		} else if err.Error() != constant.ErrUserNotFound {
+			return nil, err
+		}
+	}
+
+	username, err := u.availableUsername(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// the account can only sign in through the provider until the user sets a password
+	hash, err := password.Hash(randomToken())
+	if err != nil {
+		return nil, err
+	}
+
+	user := &domain.User{
+		Username: username,
+		Password: hash,
+		FullName: claims.Name,
+	}
+	if claimEmail {
+		user.Email = claims.Email
+		user.EmailVerified = true
+	}
+	if user.FullName == "" {
+		user.FullName = username
+	}
+
+	err = u.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		user.ID, err = u.userRepo.Create(txCtx, user)
+		if err != nil {
+			return err
+		}
+
+		if err := u.userRepo.CreatePersonalWorkspace(txCtx, user.ID); err != nil {
+			return err
+		}
+
+		if err := u.userRepo.SeedDefaultCategories(txCtx, user.ID); err != nil {
+			return err
+		}
+
+		newIdentity.UserID = user.ID
+		return u.repo.InsertIdentity(txCtx, newIdentity)
+	})
+
+	return user, err
+}
+
+func (u *oidcUsecase) availableUsername(ctx context.Context, claims *domain.OIDCClaims) (string, error) {
+	base := claims.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	base = invalidUsernameChars.ReplaceAllString(strings.ToLower(base), "")
+	if base == "" {
+		base = "user"
+	}
+	if len(base) > 40 {
+		base = base[:40]
+	}
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		_, err := u.userRepo.GetByUsername(ctx, candidate)
+		if err != nil {
+			if err.Error() == constant.ErrUserNotFound {
+				return candidate, nil
+			}
+			return "", err
+		}
+
+		candidate = base + "-" + uuid.NewString()[:6]
+	}
+
+	return base + "-" + uuid.NewString()[:8], nil
+}
+
+func randomToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand never fails on supported platforms
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
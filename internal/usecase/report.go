@@ -0,0 +1,329 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// maxCashflowPeriods keeps a daily report to about a year.
+const maxCashflowPeriods = 400
+
+type reportUsecase struct {
+	log      *log.Logger
+	repo     domain.ReportRepository
+	renderer domain.StatementRenderer
+}
+
+type ReportUsecase interface {
+	GetCashflow(ctx context.Context, req *domain.CashflowRequest) (resp pkg.Response)
+	GetIncomeStatement(ctx context.Context, req *domain.StatementRequest) (resp pkg.Response)
+	GetBalanceSheet(ctx context.Context, req *domain.StatementRequest) (resp pkg.Response)
+	GetComparison(ctx context.Context, req *domain.ComparisonRequest) (resp pkg.Response)
+}
+
+func NewReportUsecase(log *log.Logger, repo domain.ReportRepository, renderer domain.StatementRenderer) ReportUsecase {
+	return &reportUsecase{log, repo, renderer}
+}
+
+// balance sheet sections, in the order of the asset_base_type and
+// liability_base_type enums
+var (
+	assetSections = []domain.StatementSection{
+		{BaseType: "liquid", Name: "Liquid assets"},
+		{BaseType: "investment", Name: "Investments"},
+		{BaseType: "physical", Name: "Physical assets"},
+	}
+	liabilitySections = []domain.StatementSection{
+		{BaseType: "short_term", Name: "Short-term liabilities"},
+		{BaseType: "long_term", Name: "Long-term liabilities"},
+	}
+)
+
+// cashflowPeriods counts the periods of a window, including partial ones at
+// either end.
+func cashflowPeriods(from, to time.Time, groupBy string) int {
+	switch groupBy {
+	case domain.CashflowGroupDay:
+		return int(to.Sub(from).Hours()/24) + 1
+	case domain.CashflowGroupWeek:
+		return int(to.Sub(from).Hours()/24)/7 + 2
+	default:
+		return (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	}
+}
+
+// savingsRate is the share of income left after expenses, in percent.
+func savingsRate(income, net decimal.Decimal) decimal.Decimal {
+	if !income.IsPositive() {
+		return decimal.Zero
+	}
+	return net.Div(income).Mul(decimal.NewFromInt(100)).Round(2)
+}
+
+func (u *reportUsecase) GetCashflow(ctx context.Context, req *domain.CashflowRequest) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	from, err := time.Parse(time.DateOnly, req.From)
+	if err != nil {
+		return pkg.NewResponse(http.StatusBadRequest, "from is required. Expected YYYY-MM-DD", nil, nil)
+	}
+
+	to, err := time.Parse(time.DateOnly, req.To)
+	if err != nil {
+		return pkg.NewResponse(http.StatusBadRequest, "to is required. Expected YYYY-MM-DD", nil, nil)
+	}
+
+	if to.Before(from) {
+		return pkg.NewResponse(http.StatusBadRequest, "to must not be before from", nil, nil)
+	}
+
+	if req.GroupBy == "" {
+		req.GroupBy = domain.CashflowGroupMonth
+	}
+	switch req.GroupBy {
+	case domain.CashflowGroupDay, domain.CashflowGroupWeek, domain.CashflowGroupMonth:
+	default:
+		return pkg.NewResponse(http.StatusBadRequest, "group_by must be day, week or month", nil, nil)
+	}
+
+	if req.By != "" && req.By != "category" {
+		return pkg.NewResponse(http.StatusBadRequest, "by must be category", nil, nil)
+	}
+
+	if cashflowPeriods(from, to, req.GroupBy) > maxCashflowPeriods {
+		return pkg.NewResponse(http.StatusBadRequest, "The window has too many periods, use a shorter window or a larger group_by", nil, nil)
+	}
+
+	// the report window replaces whatever date filter came with the request
+	req.FilterType = "range"
+	req.StartDateStr = req.From
+	req.EndDateStr = req.To
+
+	if resp, ok := checkListFilter(&req.ListTransactionRequest); !ok {
+		return resp
+	}
+
+	rows, err := u.repo.GetCashflow(ctx, req)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.GetCashflow: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	report := domain.CashflowReport{
+		From:    req.From,
+		To:      req.To,
+		GroupBy: req.GroupBy,
+		Periods: make([]domain.CashflowPeriod, 0),
+	}
+
+	// rows come ordered by period, so each period is the last one appended
+	for _, row := range *rows {
+		period := row.Period.Format(time.DateOnly)
+		if n := len(report.Periods); n == 0 || report.Periods[n-1].Period != period {
+			report.Periods = append(report.Periods, domain.CashflowPeriod{Period: period})
+		}
+		current := &report.Periods[len(report.Periods)-1]
+
+		// a window without any matching line has no categories to break down
+		if row.BaseType == nil {
+			continue
+		}
+
+		if *row.BaseType == "income" {
+			current.Income = current.Income.Add(row.Amount)
+		} else {
+			current.Expense = current.Expense.Add(row.Amount)
+		}
+
+		if row.CategoryID != nil {
+			current.Categories = append(current.Categories, domain.CashflowCategory{
+				CategoryID: *row.CategoryID,
+				Name:       *row.CategoryName,
+				BaseType:   *row.BaseType,
+				Amount:     row.Amount,
+			})
+		}
+	}
+
+	for i := range report.Periods {
+		period := &report.Periods[i]
+		period.Net = period.Income.Sub(period.Expense)
+		period.SavingsRate = savingsRate(period.Income, period.Net)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", report, nil)
+}
+
+// checkStatementRequest validates the format and returns the statement date,
+// today when none is given.
+func checkStatementRequest(req *domain.StatementRequest) (date time.Time, resp pkg.Response, ok bool) {
+	if req.Format == "" {
+		req.Format = domain.StatementFormatJSON
+	}
+	if req.Format != domain.StatementFormatJSON && req.Format != domain.StatementFormatPDF {
+		return date, pkg.NewResponse(http.StatusBadRequest, "format must be json or pdf", nil, nil), false
+	}
+
+	if req.Date == "" {
+		now := time.Now()
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), resp, true
+	}
+
+	date, err := time.Parse(time.DateOnly, req.Date)
+	if err != nil {
+		return date, pkg.NewResponse(http.StatusBadRequest, "Invalid date format. Expected YYYY-MM-DD", nil, nil), false
+	}
+
+	return date, resp, true
+}
+
+// statementResponse returns statement as JSON data, or rendered by render when
+// a PDF is requested.
+func (u *reportUsecase) statementResponse(format, fileName string, statement any, render func(w io.Writer) error) pkg.Response {
+	if format != domain.StatementFormatPDF {
+		return pkg.NewResponse(http.StatusOK, "Success", statement, nil)
+	}
+
+	var buf bytes.Buffer
+	if err := render(&buf); err != nil {
+		u.log.Printf("[ERROR] render %s: %s", fileName, err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	file := &domain.ReportFile{
+		FileName:    fileName,
+		ContentType: "application/pdf",
+		Content:     buf.Bytes(),
+	}
+	return pkg.NewResponse(http.StatusOK, "Success", file, nil)
+}
+
+func (u *reportUsecase) GetIncomeStatement(ctx context.Context, req *domain.StatementRequest) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	date, resp, ok := checkStatementRequest(req)
+	if !ok {
+		return resp
+	}
+
+	if req.Period == "" {
+		req.Period = domain.StatementPeriodMonth
+	}
+
+	var from, to time.Time
+	var fileName string
+	switch req.Period {
+	case domain.StatementPeriodMonth:
+		from = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+		to = from.AddDate(0, 1, -1)
+		fileName = "income-statement-" + from.Format("2006-01") + ".pdf"
+	case domain.StatementPeriodYear:
+		from = time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		to = from.AddDate(1, 0, -1)
+		fileName = "income-statement-" + from.Format("2006") + ".pdf"
+	default:
+		return pkg.NewResponse(http.StatusBadRequest, "period must be month or year", nil, nil)
+	}
+
+	filter := domain.ListTransactionRequest{
+		UserID:       userID,
+		FilterType:   "range",
+		StartDateStr: from.Format(time.DateOnly),
+		EndDateStr:   to.Format(time.DateOnly),
+	}
+
+	rows, err := u.repo.GetIncomeStatement(ctx, &filter)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.GetIncomeStatement: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	statement := domain.IncomeStatement{
+		From:    filter.StartDateStr,
+		To:      filter.EndDateStr,
+		Income:  domain.StatementSection{BaseType: "income", Name: "Income", Lines: make([]domain.StatementLine, 0)},
+		Expense: domain.StatementSection{BaseType: "expense", Name: "Expenses", Lines: make([]domain.StatementLine, 0)},
+	}
+
+	for _, row := range *rows {
+		section := &statement.Expense
+		if row.BaseType == "income" {
+			section = &statement.Income
+		}
+		section.Lines = append(section.Lines, domain.StatementLine{Name: row.Name, Amount: row.Amount})
+		section.Total = section.Total.Add(row.Amount)
+	}
+	statement.NetIncome = statement.Income.Total.Sub(statement.Expense.Total)
+
+	return u.statementResponse(req.Format, fileName, &statement, func(w io.Writer) error {
+		return u.renderer.IncomeStatement(w, &statement)
+	})
+}
+
+func (u *reportUsecase) GetBalanceSheet(ctx context.Context, req *domain.StatementRequest) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	date, resp, ok := checkStatementRequest(req)
+	if !ok {
+		return resp
+	}
+
+	rows, err := u.repo.GetBalanceSheet(ctx, userID, date)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.GetBalanceSheet: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	sheet := domain.BalanceSheet{
+		Date:        date.Format(time.DateOnly),
+		Assets:      slices.Clone(assetSections),
+		Liabilities: slices.Clone(liabilitySections),
+	}
+
+	for _, row := range *rows {
+		sections := sheet.Liabilities
+		if row.Kind == "asset" {
+			sections = sheet.Assets
+		}
+
+		i := slices.IndexFunc(sections, func(s domain.StatementSection) bool { return s.BaseType == row.BaseType })
+		if i < 0 {
+			continue
+		}
+		sections[i].Lines = append(sections[i].Lines, domain.StatementLine{Name: row.Name, Category: row.Category, Amount: row.Amount})
+		sections[i].Total = sections[i].Total.Add(row.Amount)
+	}
+
+	for i := range sheet.Assets {
+		if sheet.Assets[i].Lines == nil {
+			sheet.Assets[i].Lines = make([]domain.StatementLine, 0)
+		}
+		sheet.TotalAssets = sheet.TotalAssets.Add(sheet.Assets[i].Total)
+	}
+	for i := range sheet.Liabilities {
+		if sheet.Liabilities[i].Lines == nil {
+			sheet.Liabilities[i].Lines = make([]domain.StatementLine, 0)
+		}
+		sheet.TotalLiabilities = sheet.TotalLiabilities.Add(sheet.Liabilities[i].Total)
+	}
+	sheet.NetWorth = sheet.TotalAssets.Sub(sheet.TotalLiabilities)
+
+	fileName := "balance-sheet-" + sheet.Date + ".pdf"
+	return u.statementResponse(req.Format, fileName, &sheet, func(w io.Writer) error {
+		return u.renderer.BalanceSheet(w, &sheet)
+	})
+}
@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type ruleUsecase struct {
+	log          *log.Logger
+	repo         domain.RuleRepository
+	categoryRepo domain.CategoryRepository
+	assetRepo    domain.AssetRepository
+}
+
+type RuleUsecase interface {
+	List(ctx context.Context) (resp pkg.Response)
+	Create(ctx context.Context, req *domain.CreateRule) (resp pkg.Response)
+	Update(ctx context.Context, req *domain.CreateRule) (resp pkg.Response)
+	Delete(ctx context.Context, id uuid.UUID) (resp pkg.Response)
+}
+
+func NewRuleUsecase(
+	log *log.Logger,
+	repo domain.RuleRepository,
+	categoryRepo domain.CategoryRepository,
+	assetRepo domain.AssetRepository,
+) RuleUsecase {
+	return &ruleUsecase{log, repo, categoryRepo, assetRepo}
+}
+
+// ruleMatcher is a rule with its notes regex compiled, so a pass over many
+// transactions compiles each pattern once.
+type ruleMatcher struct {
+	rule *domain.CategorizationRule
+	re   *regexp.Regexp
+}
+
+func newRuleMatcher(rule *domain.CategorizationRule) ruleMatcher {
+	m := ruleMatcher{rule: rule}
+	if rule.NotesPattern != nil && rule.NotesMatch == domain.RuleMatchRegex {
+		// patterns are checked on save, one that doesn't compile never matches
+		m.re, _ = regexp.Compile(*rule.NotesPattern)
+	}
+	return m
+}
+
+// compileRules prepares the rules for one evaluation pass, keeping their order.
+func compileRules(rules []domain.CategorizationRule) []ruleMatcher {
+	matchers := make([]ruleMatcher, len(rules))
+	for i := range rules {
+		matchers[i] = newRuleMatcher(&rules[i])
+	}
+	return matchers
+}
+
+// matches reports whether a transaction satisfies every condition of the rule.
+func (m ruleMatcher) matches(notes *string, amount decimal.Decimal, assetID *uuid.UUID) bool {
+	rule := m.rule
+	if rule.MinAmount != nil && amount.LessThan(*rule.MinAmount) {
+		return false
+	}
+
+	if rule.MaxAmount != nil && amount.GreaterThan(*rule.MaxAmount) {
+		return false
+	}
+
+	if rule.ConditionAssetID != nil && (assetID == nil || *assetID != *rule.ConditionAssetID) {
+		return false
+	}
+
+	if rule.NotesPattern != nil {
+		var text string
+		if notes != nil {
+			text = *notes
+		}
+
+		if rule.NotesMatch == domain.RuleMatchRegex {
+			if m.re == nil || !m.re.MatchString(text) {
+				return false
+			}
+		} else if !strings.Contains(strings.ToLower(text), strings.ToLower(*rule.NotesPattern)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// appliesTo reports whether the rule may act on a transaction filed under a
+// category of baseType. A rule setting a category only touches transactions of
+// the same base type, so a broad pattern can't turn income into an expense and
+// reverse its balance effect. An empty baseType means the category isn't known yet.
+func (m ruleMatcher) appliesTo(baseType string) bool {
+	rule := m.rule
+	if baseType == "" || rule.SetCategoryID == nil || rule.SetCategoryType == nil {
+		return true
+	}
+	return *rule.SetCategoryType == baseType
+}
+
+// evaluateRules runs the rules in order over a transaction. The category and
+// asset come from the first matching rule that sets them; tags accumulate.
+// baseType is the base type of the category the transaction already has, if any.
+func evaluateRules(rules []ruleMatcher, notes *string, amount decimal.Decimal, assetID *uuid.UUID, baseType string) (outcome domain.RuleOutcome) {
+	for _, m := range rules {
+		if !m.appliesTo(baseType) || !m.matches(notes, amount, assetID) {
+			continue
+		}
+
+		rule := m.rule
+		if outcome.CategoryID == nil && rule.SetCategoryID != nil {
+			outcome.CategoryID = rule.SetCategoryID
+			outcome.CategoryOwnerID = rule.UserID
+		}
+
+		if outcome.AssetID == nil && rule.SetAssetID != nil {
+			outcome.AssetID = rule.SetAssetID
+		}
+
+		outcome.Tags = append(outcome.Tags, rule.SetTags...)
+	}
+
+	return outcome
+}
+
+// validate checks a rule has conditions and actions that can be used.
+func (u *ruleUsecase) validate(ctx context.Context, req *domain.CreateRule, userID uuid.UUID) (resp pkg.Response, ok bool) {
+	if req.NotesPattern != nil && strings.TrimSpace(*req.NotesPattern) == "" {
+		req.NotesPattern = nil
+	}
+
+	if req.NotesMatch == "" {
+		req.NotesMatch = domain.RuleMatchContains
+	}
+	req.SetTags = domain.NormalizeTagNames(req.SetTags)
+
+	if req.NotesPattern == nil && req.MinAmount == nil && req.MaxAmount == nil && req.ConditionAssetID == nil {
+		return pkg.NewResponse(http.StatusBadRequest, "Rule needs at least one condition", nil, nil), false
+	}
+
+	if req.SetCategoryID == nil && req.SetAssetID == nil && len(req.SetTags) == 0 {
+		return pkg.NewResponse(http.StatusBadRequest, "Rule needs at least one action", nil, nil), false
+	}
+
+	if req.MinAmount != nil && req.MaxAmount != nil && req.MinAmount.GreaterThan(*req.MaxAmount) {
+		return pkg.NewResponse(http.StatusBadRequest, "min_amount can't be greater than max_amount", nil, nil), false
+	}
+
+	if req.NotesPattern != nil && req.NotesMatch == domain.RuleMatchRegex {
+		if _, err := regexp.Compile(*req.NotesPattern); err != nil {
+			return pkg.NewResponse(http.StatusBadRequest, "Invalid notes_pattern regex: "+err.Error(), nil, nil), false
+		}
+	}
+
+	if req.SetCategoryID != nil {
+		if _, err := u.categoryRepo.GetByID(ctx, domain.CategoryKindTransaction, *req.SetCategoryID, userID); err != nil {
+			if err.Error() == constant.ErrNotFound {
+				return pkg.NewResponse(http.StatusBadRequest, "Invalid category ID", nil, nil), false
+			}
+
+			u.log.Printf("[ERROR] categoryRepo.GetByID: %s", err.Error())
+			return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil), false
+		}
+	}
+
+	for _, assetID := range []*uuid.UUID{req.ConditionAssetID, req.SetAssetID} {
+		if assetID == nil {
+			continue
+		}
+
+		if _, err := u.assetRepo.GetByID(ctx, *assetID, userID); err != nil {
+			if err.Error() == constant.ErrNotFound {
+				return pkg.NewResponse(http.StatusBadRequest, "Invalid asset ID", nil, nil), false
+			}
+
+			u.log.Printf("[ERROR] assetRepo.GetByID: %s", err.Error())
+			return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil), false
+		}
+	}
+
+	return resp, true
+}
+
+func (u *ruleUsecase) List(ctx context.Context) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	rules, err := u.repo.List(ctx, userID)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.List: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", rules, nil)
+}
+
+func (u *ruleUsecase) Create(ctx context.Context, req *domain.CreateRule) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	if resp, ok := u.validate(ctx, req, userID); !ok {
+		return resp
+	}
+
+	id, err := u.repo.Insert(ctx, req)
+	if err != nil {
+		if err.Error() == constant.ErrNotAuthorized {
+			return pkg.NewResponse(http.StatusForbidden, constant.ErrNotAuthorized, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.Insert: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusCreated, "Success", map[string]any{"id": id}, nil)
+}
+
+func (u *ruleUsecase) Update(ctx context.Context, req *domain.CreateRule) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	if resp, ok := u.validate(ctx, req, userID); !ok {
+		return resp
+	}
+
+	err := u.repo.Update(ctx, req)
+	if err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.Update: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
+}
+
+func (u *ruleUsecase) Delete(ctx context.Context, id uuid.UUID) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	if err := u.repo.Delete(ctx, id, userID); err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.Delete: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
+}
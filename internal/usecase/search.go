@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/fazriegi/netbase-be/pkg/token"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+type searchUsecase struct {
+	log  *log.Logger
+	repo domain.SearchRepository
+}
+
+type SearchUsecase interface {
+	Search(ctx context.Context, req *domain.SearchRequest) (resp pkg.Response)
+}
+
+func NewSearchUsecase(log *log.Logger, repo domain.SearchRepository) SearchUsecase {
+	return &searchUsecase{log, repo}
+}
+
+// prefixTSQuery turns free text into a tsquery that requires every word, each
+// matched as a prefix so results show up while the user is still typing.
+// Everything but letters and digits is dropped, so the input can't inject
+// tsquery operators.
+func prefixTSQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+func (u *searchUsecase) Search(ctx context.Context, req *domain.SearchRequest) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	tsQuery := prefixTSQuery(req.Query)
+	if tsQuery == "" {
+		return pkg.NewResponse(http.StatusBadRequest, "q must contain at least one letter or digit", nil, nil)
+	}
+
+	types := req.TypeList()
+	for _, t := range types {
+		if !slices.Contains(domain.SearchTypes, t) {
+			return pkg.NewResponse(http.StatusBadRequest, "types must be any of: "+strings.Join(domain.SearchTypes, ", "), nil, nil)
+		}
+	}
+
+	// a personal access token only finds what its scopes let it read, search:read
+	// alone doesn't open up transactions, assets or liabilities
+	if scopes, ok := ctx.Value("pat_scopes").([]string); ok {
+		types = slices.DeleteFunc(slices.Clone(types), func(t string) bool {
+			return !token.ScopeAllows(scopes, domain.SearchTypeResources[t], token.ScopeRead)
+		})
+		if len(types) == 0 {
+			return pkg.NewResponse(http.StatusOK, "Success", make([]domain.SearchResult, 0), nil)
+		}
+		req.Types = strings.Join(types, ",")
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = defaultSearchLimit
+	}
+	req.Limit = min(req.Limit, maxSearchLimit)
+
+	results, err := u.repo.Search(ctx, req, tsQuery)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.Search: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", results, nil)
+}
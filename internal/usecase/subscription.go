@@ -0,0 +1,376 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/fazriegi/netbase-be/pkg/env"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// subscriptionCadence is a billing interval a pattern is matched against:
+// every gap between its charges must fall within minDays and maxDays.
+type subscriptionCadence struct {
+	name                string
+	minDays, maxDays    int
+	years, months, days int
+	perYear             int64
+}
+
+var subscriptionCadences = []subscriptionCadence{
+	{name: domain.CadenceWeekly, minDays: 6, maxDays: 8, days: 7, perYear: 52},
+	{name: domain.CadenceBiweekly, minDays: 13, maxDays: 16, days: 14, perYear: 26},
+	{name: domain.CadenceMonthly, minDays: 27, maxDays: 33, months: 1, perYear: 12},
+	{name: domain.CadenceQuarterly, minDays: 85, maxDays: 97, months: 3, perYear: 4},
+	{name: domain.CadenceYearly, minDays: 355, maxDays: 375, years: 1, perYear: 1},
+}
+
+func (c subscriptionCadence) next(date time.Time) time.Time {
+	return date.AddDate(c.years, c.months, c.days)
+}
+
+func findCadence(name string) (subscriptionCadence, bool) {
+	i := slices.IndexFunc(subscriptionCadences, func(c subscriptionCadence) bool { return c.name == name })
+	if i < 0 {
+		return subscriptionCadence{}, false
+	}
+	return subscriptionCadences[i], true
+}
+
+type subscriptionUsecase struct {
+	log    *log.Logger
+	repo   domain.SubscriptionRepository
+	config domain.SubscriptionConfig
+}
+
+type SubscriptionUsecase interface {
+	List(ctx context.Context) (resp pkg.Response)
+	Detect(ctx context.Context) (resp pkg.Response)
+	Decide(ctx context.Context, req *domain.DecideSubscription) (resp pkg.Response)
+	Delete(ctx context.Context, id uuid.UUID) (resp pkg.Response)
+}
+
+func NewSubscriptionUsecase(log *log.Logger, repo domain.SubscriptionRepository, config domain.SubscriptionConfig) SubscriptionUsecase {
+	return &subscriptionUsecase{log, repo, config}
+}
+
+// SubscriptionConfigFromEnv reads the subscription detection thresholds, falling back to sane defaults.
+func SubscriptionConfigFromEnv() domain.SubscriptionConfig {
+	return domain.SubscriptionConfig{
+		LookbackDays:    env.GetInt("SUBSCRIPTION_LOOKBACK_DAYS", 400),
+		MinCharges:      env.GetInt("SUBSCRIPTION_MIN_CHARGES", 3),
+		AmountTolerance: env.GetFloat("SUBSCRIPTION_AMOUNT_TOLERANCE", 20),
+	}
+}
+
+// subscriptionPattern normalizes transaction notes so the charges of one
+// subscription match: lower case, with digits and punctuation such as dates
+// and invoice numbers dropped. It is cut to the 255 characters the column holds.
+func subscriptionPattern(notes string) string {
+	words := strings.FieldsFunc(strings.ToLower(notes), func(r rune) bool { return !unicode.IsLetter(r) })
+	pattern := strings.Join(words, " ")
+	if runes := []rune(pattern); len(runes) > 255 {
+		pattern = strings.TrimSpace(string(runes[:255]))
+	}
+	return pattern
+}
+
+// subscriptionName names a detection after its pattern, capitalized, or after
+// the category when the charges have no notes.
+func subscriptionName(pattern, categoryName string) string {
+	if pattern == "" {
+		return categoryName
+	}
+
+	words := strings.Fields(pattern)
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+type subscriptionKey struct {
+	categoryID uuid.UUID
+	pattern    string
+}
+
+// subscriptionGroup is the charges of one category and pattern, summed per day.
+type subscriptionGroup struct {
+	key          subscriptionKey
+	categoryName string
+	dates        []time.Time
+	amounts      []decimal.Decimal
+}
+
+func groupCharges(charges []domain.SubscriptionCharge) []*subscriptionGroup {
+	groups := make([]*subscriptionGroup, 0)
+	byKey := make(map[subscriptionKey]*subscriptionGroup)
+	for _, charge := range charges {
+		var notes string
+		if charge.Notes != nil {
+			notes = strings.TrimSpace(*charge.Notes)
+		}
+		key := subscriptionKey{categoryID: charge.CategoryID, pattern: subscriptionPattern(notes)}
+
+		group, ok := byKey[key]
+		if !ok {
+			group = &subscriptionGroup{key: key, categoryName: charge.CategoryName}
+			byKey[key] = group
+			groups = append(groups, group)
+		}
+
+		date := charge.TransactionDate.UTC()
+		if n := len(group.dates); n > 0 && group.dates[n-1].Equal(date) {
+			group.amounts[n-1] = group.amounts[n-1].Add(charge.Amount)
+			continue
+		}
+		group.dates = append(group.dates, date)
+		group.amounts = append(group.amounts, charge.Amount)
+	}
+
+	return groups
+}
+
+func medianDecimal(values []decimal.Decimal) decimal.Decimal {
+	sorted := slices.Clone(values)
+	slices.SortFunc(sorted, func(a, b decimal.Decimal) int { return a.Cmp(b) })
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return sorted[mid-1].Add(sorted[mid]).Div(decimal.NewFromInt(2))
+	}
+	return sorted[mid]
+}
+
+// detect tells whether the group is charged at a regular cadence. Charges
+// further than the tolerance from the usual amount are left out, and a
+// pattern that missed two charges in a row is taken as cancelled.
+func (g *subscriptionGroup) detect(config domain.SubscriptionConfig, today time.Time) (*domain.DetectedSubscription, bool) {
+	if len(g.dates) < config.MinCharges || len(g.dates) < 2 {
+		return nil, false
+	}
+
+	median := medianDecimal(g.amounts)
+	if !median.IsPositive() {
+		return nil, false
+	}
+	tolerance := median.Mul(decimal.NewFromFloat(config.AmountTolerance / 100))
+
+	var dates []time.Time
+	var amounts []decimal.Decimal
+	for i, amount := range g.amounts {
+		if amount.Sub(median).Abs().LessThanOrEqual(tolerance) {
+			dates = append(dates, g.dates[i])
+			amounts = append(amounts, amount)
+		}
+	}
+	if len(dates) < config.MinCharges || len(dates) < 2 {
+		return nil, false
+	}
+
+	i := slices.IndexFunc(subscriptionCadences, func(c subscriptionCadence) bool {
+		for j := 1; j < len(dates); j++ {
+			days := int(dates[j].Sub(dates[j-1]).Hours() / 24)
+			if days < c.minDays || days > c.maxDays {
+				return false
+			}
+		}
+		return true
+	})
+	if i < 0 {
+		return nil, false
+	}
+	cadence := subscriptionCadences[i]
+
+	last := dates[len(dates)-1]
+	next := cadence.next(last)
+	if cadence.next(next).Before(today) {
+		return nil, false
+	}
+
+	var total decimal.Decimal
+	for _, amount := range amounts {
+		total = total.Add(amount)
+	}
+	average := total.Div(decimal.NewFromInt(int64(len(amounts)))).Round(2)
+
+	return &domain.DetectedSubscription{
+		CategoryID:    g.key.categoryID,
+		CategoryName:  g.categoryName,
+		Pattern:       g.key.pattern,
+		Name:          subscriptionName(g.key.pattern, g.categoryName),
+		Cadence:       cadence.name,
+		Charges:       len(dates),
+		AverageAmount: average,
+		LastAmount:    amounts[len(amounts)-1],
+		FirstCharge:   dates[0].Format(time.DateOnly),
+		LastCharge:    last.Format(time.DateOnly),
+		NextCharge:    next.Format(time.DateOnly),
+		AnnualCost:    average.Mul(decimal.NewFromInt(cadence.perYear)),
+	}, true
+}
+
+// detectAll scans the expenses of the lookback window, returning the charge
+// groups along with the detections among them, costliest first.
+func (u *subscriptionUsecase) detectAll(ctx context.Context, userID uuid.UUID) ([]*subscriptionGroup, []domain.DetectedSubscription, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	charges, err := u.repo.ListCharges(ctx, userID, today.AddDate(0, 0, -u.config.LookbackDays))
+	if err != nil {
+		u.log.Printf("[ERROR] repo.ListCharges: %s", err.Error())
+		return nil, nil, err
+	}
+
+	groups := groupCharges(*charges)
+	detected := make([]domain.DetectedSubscription, 0)
+	for _, group := range groups {
+		if subscription, ok := group.detect(u.config, today); ok {
+			detected = append(detected, *subscription)
+		}
+	}
+
+	slices.SortFunc(detected, func(a, b domain.DetectedSubscription) int {
+		if c := b.AnnualCost.Cmp(a.AnnualCost); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return groups, detected, nil
+}
+
+// List returns the confirmed subscriptions, with the last charge taken from
+// the latest matching expense and the next one expected a cadence later.
+func (u *subscriptionUsecase) List(ctx context.Context) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	subscriptions, err := u.repo.List(ctx, userID, domain.SubscriptionConfirmed)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.List: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	groups, _, err := u.detectAll(ctx, userID)
+	if err != nil {
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	lastCharges := make(map[subscriptionKey]time.Time, len(groups))
+	for _, group := range groups {
+		lastCharges[group.key] = group.dates[len(group.dates)-1]
+	}
+
+	for i := range *subscriptions {
+		subscription := &(*subscriptions)[i]
+		key := subscriptionKey{categoryID: subscription.CategoryID, pattern: subscription.Pattern}
+		if last, ok := lastCharges[key]; ok && last.After(subscription.LastChargeDate) {
+			subscription.LastChargeDate = last
+		}
+
+		if cadence, ok := findCadence(subscription.Cadence); ok {
+			subscription.NextCharge = cadence.next(subscription.LastChargeDate).Format(time.DateOnly)
+			subscription.AnnualCost = subscription.Amount.Mul(decimal.NewFromInt(cadence.perYear))
+		}
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", subscriptions, nil)
+}
+
+// Detect lists the detected subscriptions not confirmed or dismissed yet.
+func (u *subscriptionUsecase) Detect(ctx context.Context) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	_, detected, err := u.detectAll(ctx, userID)
+	if err != nil {
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	decided, err := u.repo.List(ctx, userID, "")
+	if err != nil {
+		u.log.Printf("[ERROR] repo.List: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	detected = slices.DeleteFunc(detected, func(d domain.DetectedSubscription) bool {
+		return slices.ContainsFunc(*decided, func(s domain.Subscription) bool {
+			return s.CategoryID == d.CategoryID && s.Pattern == d.Pattern
+		})
+	})
+
+	return pkg.NewResponse(http.StatusOK, "Success", detected, nil)
+}
+
+// Decide confirms a detection, tracking it as a recurring item, or dismisses
+// it so it isn't suggested again.
+func (u *subscriptionUsecase) Decide(ctx context.Context, req *domain.DecideSubscription) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+	req.Pattern = subscriptionPattern(req.Pattern)
+
+	_, detected, err := u.detectAll(ctx, userID)
+	if err != nil {
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	i := slices.IndexFunc(detected, func(d domain.DetectedSubscription) bool {
+		return d.CategoryID == *req.CategoryID && d.Pattern == req.Pattern
+	})
+	if i < 0 {
+		return pkg.NewResponse(http.StatusBadRequest, "No subscription detected for this category and pattern", nil, nil)
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" && req.Status == domain.SubscriptionConfirmed {
+		detected[i].Name = name
+	}
+
+	subscription, err := u.repo.Save(ctx, req, &detected[i])
+	if err != nil {
+		if err.Error() == constant.ErrNotAuthorized {
+			return pkg.NewResponse(http.StatusForbidden, constant.ErrNotAuthorized, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.Save: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	if cadence, ok := findCadence(subscription.Cadence); ok && req.Status == domain.SubscriptionConfirmed {
+		subscription.NextCharge = detected[i].NextCharge
+		subscription.AnnualCost = subscription.Amount.Mul(decimal.NewFromInt(cadence.perYear))
+	}
+
+	status := http.StatusOK
+	if req.Status == domain.SubscriptionConfirmed {
+		status = http.StatusCreated
+	}
+
+	return pkg.NewResponse(status, "Success", subscription, nil)
+}
+
+// Delete stops tracking a subscription or undoes a dismissal, so the pattern
+// can be detected again.
+func (u *subscriptionUsecase) Delete(ctx context.Context, id uuid.UUID) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	if err := u.repo.Delete(ctx, id, userID); err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.Delete: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+)
+
+type tagUsecase struct {
+	log       *log.Logger
+	repo      domain.TagRepository
+	txManager domain.TransactionManager
+}
+
+type TagUsecase interface {
+	List(ctx context.Context) (resp pkg.Response)
+	Rename(ctx context.Context, req *domain.UpdateTag) (resp pkg.Response)
+	Merge(ctx context.Context, req *domain.MergeTag) (resp pkg.Response)
+	Delete(ctx context.Context, id uuid.UUID) (resp pkg.Response)
+}
+
+func NewTagUsecase(log *log.Logger, repo domain.TagRepository, txManager domain.TransactionManager) TagUsecase {
+	return &tagUsecase{log, repo, txManager}
+}
+
+func (u *tagUsecase) getTag(ctx context.Context, id, userID uuid.UUID) (tag *domain.Tag, resp pkg.Response, ok bool) {
+	tag, err := u.repo.GetByID(ctx, id, userID, true)
+	if err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return nil, pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil), false
+		}
+
+		u.log.Printf("[ERROR] repo.GetByID: %s", err.Error())
+		return nil, pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil), false
+	}
+
+	return tag, resp, true
+}
+
+func (u *tagUsecase) List(ctx context.Context) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	tags, err := u.repo.List(ctx, userID)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.List: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", tags, nil)
+}
+
+func (u *tagUsecase) Rename(ctx context.Context, req *domain.UpdateTag) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	names := domain.NormalizeTagNames([]string{req.Name})
+	if len(names) == 0 {
+		return pkg.NewResponse(http.StatusBadRequest, "Tag name can't be empty", nil, nil)
+	}
+
+	tag, resp, ok := u.getTag(ctx, req.ID, userID)
+	if !ok {
+		return resp
+	}
+
+	if err := u.repo.Rename(ctx, tag.ID, names[0]); err != nil {
+		if err.Error() == constant.ErrTagExists {
+			return pkg.NewResponse(http.StatusConflict, "Tag already exists, merge the tags instead", nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.Rename: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
+}
+
+func (u *tagUsecase) Merge(ctx context.Context, req *domain.MergeTag) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	if req.SourceID == req.TargetID {
+		return pkg.NewResponse(http.StatusBadRequest, "Cannot merge a tag into itself", nil, nil)
+	}
+
+	// both lookups are scoped to the current workspace, so the tags share it
+	source, resp, ok := u.getTag(ctx, req.SourceID, userID)
+	if !ok {
+		return resp
+	}
+
+	target, resp, ok := u.getTag(ctx, req.TargetID, userID)
+	if !ok {
+		return resp
+	}
+
+	err := u.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		return u.repo.Merge(txCtx, source.ID, target.ID)
+	})
+	if err != nil {
+		u.log.Printf("[ERROR] repo.Merge: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
+}
+
+func (u *tagUsecase) Delete(ctx context.Context, id uuid.UUID) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	tag, resp, ok := u.getTag(ctx, id, userID)
+	if !ok {
+		return resp
+	}
+
+	if err := u.repo.Delete(ctx, tag.ID); err != nil {
+		u.log.Printf("[ERROR] repo.Delete: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
+}
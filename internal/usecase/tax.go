@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type taxUsecase struct {
+	log  *log.Logger
+	repo domain.TaxRepository
+}
+
+type TaxUsecase interface {
+	EstimateIncomeTax(ctx context.Context, req *domain.TaxEstimateRequest) (resp pkg.Response)
+}
+
+func NewTaxUsecase(log *log.Logger, repo domain.TaxRepository) TaxUsecase {
+	return &taxUsecase{log, repo}
+}
+
+// EstimateIncomeTax applies the tax rules of the year to the income booked on
+// taxable categories over it.
+func (u *taxUsecase) EstimateIncomeTax(ctx context.Context, req *domain.TaxEstimateRequest) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	if req.Year == 0 {
+		req.Year = time.Now().Year()
+	}
+	if req.Year < 1900 || req.Year > 9999 {
+		return pkg.NewResponse(http.StatusBadRequest, "Invalid year", nil, nil)
+	}
+
+	req.Status = strings.ToUpper(strings.TrimSpace(req.Status))
+	if req.Status == "" {
+		req.Status = "TK/0"
+	}
+
+	rules, err := u.repo.GetYear(ctx, req.Year)
+	if err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusBadRequest, "No tax rules for "+strconv.Itoa(req.Year), nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.GetYear: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	ptkp, err := u.repo.GetPTKP(ctx, rules.Year, req.Status)
+	if err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusBadRequest, "Unknown PTKP status "+req.Status, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.GetPTKP: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	brackets, err := u.repo.ListBrackets(ctx, rules.Year)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.ListBrackets: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	from := time.Date(req.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	incomes, err := u.repo.ListIncome(ctx, userID, from, from.AddDate(1, 0, -1))
+	if err != nil {
+		u.log.Printf("[ERROR] repo.ListIncome: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	estimate := domain.TaxEstimate{
+		Year:      req.Year,
+		RulesYear: rules.Year,
+		Status:    req.Status,
+		Employee:  req.Employee,
+		PTKP:      ptkp,
+		Incomes:   *incomes,
+	}
+
+	for _, income := range *incomes {
+		if income.IsTaxable {
+			estimate.GrossIncome = estimate.GrossIncome.Add(income.Amount)
+		} else {
+			estimate.NonTaxableIncome = estimate.NonTaxableIncome.Add(income.Amount)
+		}
+	}
+
+	incomeTax(&estimate, rules, *brackets)
+
+	return pkg.NewResponse(http.StatusOK, "Success", estimate, nil)
+}
+
+// incomeTax fills in the tax on estimate.GrossIncome: the occupational cost
+// of employees and the PTKP are deducted, the rest is rounded down to the
+// thousand and taxed bracket by bracket.
+func incomeTax(estimate *domain.TaxEstimate, rules *domain.TaxYear, brackets []domain.TaxBracket) {
+	hundred := decimal.NewFromInt(100)
+	thousand := decimal.NewFromInt(1000)
+
+	if estimate.Employee {
+		estimate.OccupationalCost = decimal.Min(estimate.GrossIncome.Mul(rules.OccupationalCostRate).Div(hundred), rules.OccupationalCostCap).Round(2)
+	}
+	estimate.NetIncome = estimate.GrossIncome.Sub(estimate.OccupationalCost)
+
+	taxable := decimal.Max(estimate.NetIncome.Sub(estimate.PTKP), decimal.Zero)
+	estimate.TaxableIncome = taxable.Div(thousand).Floor().Mul(thousand)
+
+	estimate.Brackets = make([]domain.TaxBracketLine, len(brackets))
+	for i, bracket := range brackets {
+		line := domain.TaxBracketLine{LowerBound: bracket.LowerBound, Rate: bracket.Rate}
+
+		portion := estimate.TaxableIncome.Sub(bracket.LowerBound)
+		if i+1 < len(brackets) {
+			upper := brackets[i+1].LowerBound
+			line.UpperBound = &upper
+			portion = decimal.Min(portion, upper.Sub(bracket.LowerBound))
+		}
+
+		if portion.IsPositive() {
+			line.Taxable = portion
+			line.Tax = portion.Mul(bracket.Rate).Div(hundred).Floor()
+			estimate.Tax = estimate.Tax.Add(line.Tax)
+		}
+		estimate.Brackets[i] = line
+	}
+
+	if estimate.GrossIncome.IsPositive() {
+		estimate.EffectiveRate = estimate.Tax.Div(estimate.GrossIncome).Mul(hundred).Round(2)
+	}
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/fazriegi/netbase-be/pkg/token"
+	"github.com/google/uuid"
+)
+
+type tokenUsecase struct {
+	log  *log.Logger
+	repo domain.PersonalAccessTokenRepository
+}
+
+type TokenUsecase interface {
+	List(ctx context.Context) (resp pkg.Response)
+	Create(ctx context.Context, req *domain.CreatePersonalAccessToken) (resp pkg.Response)
+	Delete(ctx context.Context, id uuid.UUID) (resp pkg.Response)
+	Authenticate(ctx context.Context, raw string) (userID uuid.UUID, scopes []string, err error)
+}
+
+func NewTokenUsecase(log *log.Logger, repo domain.PersonalAccessTokenRepository) TokenUsecase {
+	return &tokenUsecase{log, repo}
+}
+
+func (u *tokenUsecase) List(ctx context.Context) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	tokens, err := u.repo.List(ctx, userID)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.List: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", tokens, nil)
+}
+
+func (u *tokenUsecase) Create(ctx context.Context, req *domain.CreatePersonalAccessToken) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	for _, scope := range req.Scopes {
+		if !token.ValidScope(scope) {
+			return pkg.NewResponse(http.StatusBadRequest, fmt.Sprintf("Invalid scope '%s'", scope), nil, nil)
+		}
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresAt != "" {
+		exp, err := time.Parse("2006-01-02", req.ExpiresAt)
+		if err != nil {
+			return pkg.NewResponse(http.StatusBadRequest, "Invalid date format. Expected YYYY-MM-DD", nil, nil)
+		}
+
+		if !exp.After(time.Now()) {
+			return pkg.NewResponse(http.StatusBadRequest, "Expiry date must be in the future", nil, nil)
+		}
+		expiresAt = &exp
+	}
+
+	plain, hash, prefix, err := token.GeneratePAT()
+	if err != nil {
+		u.log.Printf("[ERROR] token.GeneratePAT: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	pat := &domain.PersonalAccessToken{
+		UserID:      userID,
+		Name:        req.Name,
+		TokenHash:   hash,
+		TokenPrefix: prefix,
+		Scopes:      req.Scopes,
+		ExpiresAt:   expiresAt,
+	}
+
+	id, err := u.repo.Insert(ctx, pat)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.Insert: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	// the plaintext token is only ever returned here
+	return pkg.NewResponse(http.StatusCreated, "Success", map[string]any{
+		"id":    id,
+		"token": plain,
+	}, nil)
+}
+
+func (u *tokenUsecase) Delete(ctx context.Context, id uuid.UUID) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	err := u.repo.Delete(ctx, id, userID)
+	if err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.Delete: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
+}
+
+func (u *tokenUsecase) Authenticate(ctx context.Context, raw string) (uuid.UUID, []string, error) {
+	pat, err := u.repo.Touch(ctx, token.HashPAT(raw))
+	if err != nil {
+		if err.Error() != constant.ErrNotFound {
+			u.log.Printf("[ERROR] repo.Touch: %s", err.Error())
+		}
+		return uuid.Nil, nil, err
+	}
+
+	return pat.UserID, pat.Scopes, nil
+}
@@ -1,12 +1,14 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"math"
+	"maps"
 	"net/http"
-	"strings"
+	"slices"
 	"time"
 
 	"github.com/fazriegi/netbase-be/internal/domain"
@@ -17,23 +19,28 @@ import (
 )
 
 type transactionUsecase struct {
-	log       *log.Logger
-	repo      domain.TransactionRepository
-	txManager domain.TransactionManager
-	assetRepo domain.AssetRepository
-	liabRepo  domain.LiabilityRepository
+	log            *log.Logger
+	repo           domain.TransactionRepository
+	txManager      domain.TransactionManager
+	assetRepo      domain.AssetRepository
+	liabRepo       domain.LiabilityRepository
+	tagRepo        domain.TagRepository
+	ruleRepo       domain.RuleRepository
+	attachmentRepo domain.AttachmentRepository
+	blobs          domain.BlobStore
 }
 
 type TransactionUsecase interface {
-	ListCategory(ctx context.Context, req *domain.ListCategoryRequest) (resp pkg.Response)
-	CreateCategory(ctx context.Context, req *domain.Category) (resp pkg.Response)
-	DeleteCategory(ctx context.Context, id uuid.UUID) (resp pkg.Response)
 	List(ctx context.Context, req *domain.ListTransactionRequest) (resp pkg.Response)
 	GetSummary(ctx context.Context, req *domain.ListTransactionRequest) (resp pkg.Response)
+	GetTagSummary(ctx context.Context, req *domain.ListTransactionRequest) (resp pkg.Response)
 	GetByID(ctx context.Context, id uuid.UUID) (resp pkg.Response)
 	Create(ctx context.Context, req *domain.CreateTransaction) (resp pkg.Response)
 	Update(ctx context.Context, req *domain.CreateTransaction) (resp pkg.Response)
 	Delete(ctx context.Context, id uuid.UUID) (resp pkg.Response)
+	Bulk(ctx context.Context, req *domain.BulkTransactionRequest) (resp pkg.Response)
+	PreviewRule(ctx context.Context, ruleID uuid.UUID) (resp pkg.Response)
+	ApplyRule(ctx context.Context, ruleID uuid.UUID) (resp pkg.Response)
 }
 
 func NewTransactionUsecase(
@@ -42,58 +49,76 @@ func NewTransactionUsecase(
 	txManager domain.TransactionManager,
 	assetRepo domain.AssetRepository,
 	liabRepo domain.LiabilityRepository,
+	tagRepo domain.TagRepository,
+	ruleRepo domain.RuleRepository,
+	attachmentRepo domain.AttachmentRepository,
+	blobs domain.BlobStore,
 ) TransactionUsecase {
-	return &transactionUsecase{log, repo, txManager, assetRepo, liabRepo}
+	return &transactionUsecase{log, repo, txManager, assetRepo, liabRepo, tagRepo, ruleRepo, attachmentRepo, blobs}
 }
 
-func (u *transactionUsecase) ListCategory(ctx context.Context, req *domain.ListCategoryRequest) (resp pkg.Response) {
-	userID := ctx.Value("user_id").(uuid.UUID)
-	req.UserID = userID
-
-	categories, err := u.repo.ListCategory(ctx, req)
-	if err != nil {
-		u.log.Printf("[ERROR] repo.ListCategory: %s", err.Error())
-		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+// checkListFilter rejects filters that can't match anything meaningful. Every
+// endpoint taking the list filters, summaries included, runs it first so they
+// all accept the same requests.
+func checkListFilter(req *domain.ListTransactionRequest) (resp pkg.Response, ok bool) {
+	if req.BaseType != "" && req.BaseType != "income" && req.BaseType != "expense" {
+		return pkg.NewResponse(http.StatusBadRequest, "base_type must be income or expense", nil, nil), false
 	}
 
-	return pkg.NewResponse(http.StatusOK, "Success", categories, nil)
-}
-
-func (u *transactionUsecase) CreateCategory(ctx context.Context, req *domain.Category) (resp pkg.Response) {
-	userID := ctx.Value("user_id").(uuid.UUID)
-	req.UserID = userID
-
-	err := u.repo.InsertCategory(ctx, req)
-	if err != nil {
-		u.log.Printf("[ERROR] repo.InsertCategory: %s", err.Error())
-		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	if req.TagMatch != "" && req.TagMatch != domain.TagMatchAny && req.TagMatch != domain.TagMatchAll {
+		return pkg.NewResponse(http.StatusBadRequest, "tag_match must be any or all", nil, nil), false
 	}
 
-	return pkg.NewResponse(http.StatusCreated, "Success", nil, nil)
-}
+	switch req.FilterType {
+	case "", "week", "month", "quarter", "year":
+		if req.DateStr != "" {
+			if _, err := time.Parse(time.DateOnly, req.DateStr); err != nil {
+				return pkg.NewResponse(http.StatusBadRequest, "date must be YYYY-MM-DD", nil, nil), false
+			}
+		}
+	case "range":
+		start, err := time.Parse(time.DateOnly, req.StartDateStr)
+		if err != nil {
+			return pkg.NewResponse(http.StatusBadRequest, "start_date is required. Expected YYYY-MM-DD", nil, nil), false
+		}
 
-func (u *transactionUsecase) DeleteCategory(ctx context.Context, id uuid.UUID) (resp pkg.Response) {
-	userID := ctx.Value("user_id").(uuid.UUID)
+		end, err := time.Parse(time.DateOnly, req.EndDateStr)
+		if err != nil {
+			return pkg.NewResponse(http.StatusBadRequest, "end_date is required. Expected YYYY-MM-DD", nil, nil), false
+		}
 
-	err := u.repo.DeleteCategory(ctx, id, userID)
-	if err != nil {
-		if strings.Contains(err.Error(), "violates foreign key constraint") {
-			return pkg.NewResponse(http.StatusBadRequest, "Category is used by some transactions", nil, nil)
+		if end.Before(start) {
+			return pkg.NewResponse(http.StatusBadRequest, "end_date must not be before start_date", nil, nil), false
 		}
+	default:
+		return pkg.NewResponse(http.StatusBadRequest, "filter_type must be week, month, quarter, year or range", nil, nil), false
+	}
 
-		u.log.Printf("[ERROR] repo.DeleteCategory: %s", err.Error())
-		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	if req.MinAmount != nil && req.MaxAmount != nil && req.MinAmount.GreaterThan(*req.MaxAmount) {
+		return pkg.NewResponse(http.StatusBadRequest, "min_amount can't be greater than max_amount", nil, nil), false
 	}
 
-	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
+	return resp, true
 }
 
 func (u *transactionUsecase) List(ctx context.Context, req *domain.ListTransactionRequest) (resp pkg.Response) {
 	userID := ctx.Value("user_id").(uuid.UUID)
 	req.UserID = userID
 
-	transactions, total, err := u.repo.List(ctx, req)
+	if resp, ok := checkListFilter(req); !ok {
+		return resp
+	}
+
+	if req.LimitTooLarge() {
+		return pkg.NewResponse(http.StatusBadRequest, constant.ErrLimitTooLarge, nil, nil)
+	}
+
+	transactions, page, err := u.repo.List(ctx, req)
 	if err != nil {
+		if err.Error() == constant.ErrInvalidCursor {
+			return pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidCursor, nil, nil)
+		}
+
 		u.log.Printf("[ERROR] repo.List: %s", err.Error())
 		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
 	}
@@ -113,46 +138,92 @@ func (u *transactionUsecase) List(ctx context.Context, req *domain.ListTransacti
 				Amount:          tx.Amount,
 				TransactionDate: tx.TransactionDate,
 				Notes:           tx.Notes,
+				Tags:            tx.Tags,
+				IsSplit:         tx.IsSplit,
 			})
 		}
 	}
 
-	var paginationMeta pkg.PaginationMeta
-	if req.Limit != nil && *req.Limit > 0 {
-		limit := int(*req.Limit)
-		page := 1
+	return pkg.NewResponse(http.StatusOK, "Success", dataResponse, pkg.NewPaginationMeta(&req.PaginationRequest, page))
+}
 
-		if req.Page != nil && *req.Page > 0 {
-			page = int(*req.Page)
-		}
+func (u *transactionUsecase) GetSummary(ctx context.Context, req *domain.ListTransactionRequest) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	if resp, ok := checkListFilter(req); !ok {
+		return resp
+	}
+
+	summary, err := u.repo.GetSummary(ctx, req)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.GetSummary: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	categories, err := u.repo.GetCategorySummary(ctx, req)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.GetCategorySummary: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+	summary.Categories = rollUpCategorySummary(*categories)
+
+	return pkg.NewResponse(http.StatusOK, "Success", summary, nil)
+}
 
-		totalPages := int(math.Ceil(float64(total) / float64(limit)))
-		if totalPages > 0 && page > totalPages {
-			page = totalPages
+// rollUpCategorySummary nests the category summaries into a tree and sums each
+// node's own amount with the totals of its children.
+func rollUpCategorySummary(categories []domain.CategorySummary) []domain.CategorySummary {
+	children := make(map[uuid.UUID][]domain.CategorySummary)
+	roots := make([]domain.CategorySummary, 0)
+	for _, c := range categories {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+			continue
 		}
+		roots = append(roots, c)
+	}
 
-		paginationMeta = pkg.PaginationMeta{
-			Page:       page,
-			Limit:      limit,
-			Total:      int(total),
-			TotalPages: totalPages,
+	var build func(nodes []domain.CategorySummary) []domain.CategorySummary
+	build = func(nodes []domain.CategorySummary) []domain.CategorySummary {
+		for i := range nodes {
+			nodes[i].Children = build(children[nodes[i].ID])
+			nodes[i].Total = nodes[i].Amount
+			for _, child := range nodes[i].Children {
+				nodes[i].Total = nodes[i].Total.Add(child.Total)
+			}
 		}
+		return nodes
 	}
 
-	return pkg.NewResponse(http.StatusOK, "Success", dataResponse, &paginationMeta)
+	return build(roots)
 }
 
-func (u *transactionUsecase) GetSummary(ctx context.Context, req *domain.ListTransactionRequest) (resp pkg.Response) {
+func (u *transactionUsecase) GetTagSummary(ctx context.Context, req *domain.ListTransactionRequest) (resp pkg.Response) {
 	userID := ctx.Value("user_id").(uuid.UUID)
 	req.UserID = userID
 
-	summary, err := u.repo.GetSummary(ctx, req)
+	if resp, ok := checkListFilter(req); !ok {
+		return resp
+	}
+
+	summaries, err := u.repo.GetTagSummary(ctx, req)
 	if err != nil {
-		u.log.Printf("[ERROR] repo.GetSummary: %s", err.Error())
+		u.log.Printf("[ERROR] repo.GetTagSummary: %s", err.Error())
 		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
 	}
 
-	return pkg.NewResponse(http.StatusOK, "Success", summary, nil)
+	return pkg.NewResponse(http.StatusOK, "Success", summaries, nil)
+}
+
+// setTags replaces the tags of a transaction, creating missing tags in its workspace.
+func (u *transactionUsecase) setTags(ctx context.Context, txDB *domain.TransactionDB, names []string) error {
+	tagIDs, err := u.tagRepo.Upsert(ctx, txDB.WorkspaceID, domain.NormalizeTagNames(names))
+	if err != nil {
+		return err
+	}
+
+	return u.tagRepo.SetTransactionTags(ctx, txDB.ID, tagIDs)
 }
 
 func (u *transactionUsecase) GetByID(ctx context.Context, id uuid.UUID) (resp pkg.Response) {
@@ -167,23 +238,123 @@ func (u *transactionUsecase) GetByID(ctx context.Context, id uuid.UUID) (resp pk
 		return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
 	}
 
+	if tx.IsSplit {
+		splits, err := u.repo.ListSplits(ctx, tx.ID)
+		if err != nil {
+			u.log.Printf("[ERROR] repo.ListSplits: %s", err.Error())
+			return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+		}
+		tx.Splits = *splits
+	}
+
 	return pkg.NewResponse(http.StatusOK, "Success", tx, nil)
 }
 
-func (u *transactionUsecase) Create(ctx context.Context, req *domain.CreateTransaction) (resp pkg.Response) {
-	userID := ctx.Value("user_id").(uuid.UUID)
-	req.UserID = userID
+func toTransactionSplits(req []domain.CreateTransactionSplit) []domain.TransactionSplit {
+	splits := make([]domain.TransactionSplit, 0, len(req))
+	for _, split := range req {
+		splits = append(splits, domain.TransactionSplit{
+			CategoryID: split.CategoryID,
+			Amount:     *split.Amount,
+			Notes:      split.Notes,
+		})
+	}
+	return splits
+}
+
+// checkSplits validates the lines of a split transaction: there must be at
+// least two, all on categories of the transaction's base type, adding up to
+// the transaction amount.
+func (u *transactionUsecase) checkSplits(ctx context.Context, splits []domain.TransactionSplit, amount decimal.Decimal, baseType string, transactionID, userID uuid.UUID) error {
+	if len(splits) < 2 {
+		return &BusinessError{Message: "A split transaction needs at least two splits"}
+	}
+
+	total := decimal.Zero
+	for _, split := range splits {
+		if !split.Amount.IsPositive() {
+			return &BusinessError{Message: "Split amounts must be greater than zero"}
+		}
+		total = total.Add(split.Amount)
+
+		category, err := u.repo.GetCategoryByID(ctx, split.CategoryID, transactionID, userID)
+		if err != nil {
+			if err.Error() == constant.ErrNotFound {
+				return &BusinessError{Message: "Invalid split category ID"}
+			}
+			return err
+		}
 
+		if category.BaseType != baseType {
+			return &BusinessError{Message: fmt.Sprintf("Split category '%s' must be an %s category like the transaction", category.Name, baseType)}
+		}
+	}
+
+	if !total.Equal(amount) {
+		return &BusinessError{Message: fmt.Sprintf("Splits add up to %s but the transaction amount is %s", total.String(), amount.String())}
+	}
+
+	return nil
+}
+
+// newTransaction checks a create request and fills in what the active rules
+// assign, returning the row to insert along with its category and splits.
+func (u *transactionUsecase) newTransaction(
+	ctx context.Context,
+	req *domain.CreateTransaction,
+	rules []ruleMatcher,
+	userID uuid.UUID,
+) (*domain.TransactionDB, *domain.Category, []domain.TransactionSplit, error) {
 	txDate, err := time.Parse("2006-01-02", req.TransactionDate)
 	if err != nil {
-		u.log.Printf("[ERROR] time.Parse transaction date: %s", err.Error())
-		return pkg.NewResponse(http.StatusBadRequest, "Invalid date format. Expected YYYY-MM-DD", nil, nil)
+		return nil, nil, nil, &BusinessError{Message: "Invalid date format. Expected YYYY-MM-DD"}
 	}
 
-	category, err := u.repo.GetCategoryByID(ctx, req.CategoryID, userID)
-	if err != nil {
-		u.log.Printf("[ERROR] repo.GetCategoryByID: %s", err.Error())
-		return pkg.NewResponse(http.StatusBadRequest, "Invalid category ID", nil, nil)
+	// a split transaction is filed under its first split unless told otherwise
+	if req.CategoryID == uuid.Nil && len(req.Splits) > 0 {
+		req.CategoryID = req.Splits[0].CategoryID
+	}
+
+	var category *domain.Category
+	var baseType string
+	if req.CategoryID != uuid.Nil {
+		category, err = u.repo.GetCategoryByID(ctx, req.CategoryID, uuid.Nil, userID)
+		if err != nil {
+			if err.Error() == constant.ErrNotFound {
+				return nil, nil, nil, &BusinessError{Message: "Invalid category ID"}
+			}
+			return nil, nil, nil, err
+		}
+		baseType = category.BaseType
+	}
+
+	// rules only fill in what the request leaves empty
+	outcome := evaluateRules(rules, req.Notes, *req.Amount, req.AssetID, baseType)
+	if req.CategoryID == uuid.Nil && outcome.CategoryID != nil {
+		req.CategoryID = *outcome.CategoryID
+
+		category, err = u.repo.GetCategoryByID(ctx, req.CategoryID, uuid.Nil, outcome.CategoryOwnerID)
+		if err != nil {
+			if err.Error() == constant.ErrNotFound {
+				return nil, nil, nil, &BusinessError{Message: "Invalid category ID"}
+			}
+			return nil, nil, nil, err
+		}
+	}
+	if req.AssetID == nil && outcome.AssetID != nil {
+		req.AssetID = outcome.AssetID
+	}
+	req.Tags = append(req.Tags, outcome.Tags...)
+
+	if category == nil {
+		return nil, nil, nil, &BusinessError{Message: "category_id is required when no rule assigns a category"}
+	}
+
+	splits := toTransactionSplits(req.Splits)
+	if len(splits) > 0 {
+		if err := u.checkSplits(ctx, splits, *req.Amount, category.BaseType, uuid.Nil, userID); err != nil {
+			return nil, nil, nil, err
+		}
 	}
 
 	txDB := &domain.TransactionDB{
@@ -196,12 +367,55 @@ func (u *transactionUsecase) Create(ctx context.Context, req *domain.CreateTrans
 		Notes:           req.Notes,
 	}
 
+	return txDB, category, splits, nil
+}
+
+// insertTransaction stores a checked transaction with its tags and splits,
+// leaving the balance effect to the caller.
+func (u *transactionUsecase) insertTransaction(ctx context.Context, txDB *domain.TransactionDB, tags []string, splits []domain.TransactionSplit) error {
+	err := u.repo.Insert(ctx, txDB)
+	if err != nil {
+		return err
+	}
+
+	err = u.setTags(ctx, txDB, tags)
+	if err != nil {
+		return err
+	}
+
+	if len(splits) > 0 {
+		return u.repo.ReplaceSplits(ctx, txDB.ID, splits)
+	}
+
+	return nil
+}
+
+func (u *transactionUsecase) Create(ctx context.Context, req *domain.CreateTransaction) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	rules, err := u.ruleRepo.ListActive(ctx, userID)
+	if err != nil {
+		u.log.Printf("[ERROR] ruleRepo.ListActive: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	txDB, category, splits, err := u.newTransaction(ctx, req, compileRules(*rules), userID)
+	if err != nil {
+		if busErr, ok := err.(*BusinessError); ok {
+			return pkg.NewResponse(http.StatusBadRequest, busErr.Message, nil, nil)
+		}
+		u.log.Printf("[ERROR] newTransaction: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
 	err = u.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
-		err = u.repo.Insert(txCtx, txDB)
+		err = u.insertTransaction(txCtx, txDB, req.Tags, splits)
 		if err != nil {
 			return err
 		}
 
+		// the balance effect is applied once for the total, never per split
 		err = u.applyCashflowEffect(txCtx, category.BaseType, txDB.Amount, txDB.AssetID, txDB.LiabilityID, userID)
 		if err != nil {
 			return err
@@ -219,6 +433,12 @@ func (u *transactionUsecase) Create(ctx context.Context, req *domain.CreateTrans
 		if busErr, ok := err.(*BusinessError); ok {
 			return pkg.NewResponse(http.StatusBadRequest, busErr.Message, nil, nil)
 		}
+		if err.Error() == constant.ErrInvalidCategory {
+			return pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidCategory, nil, nil)
+		}
+		if err.Error() == constant.ErrNotAuthorized {
+			return pkg.NewResponse(http.StatusForbidden, constant.ErrNotAuthorized, nil, nil)
+		}
 		u.log.Printf("[ERROR] Create transaction: %s", err.Error())
 		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
 	}
@@ -236,6 +456,14 @@ func (u *transactionUsecase) Update(ctx context.Context, req *domain.CreateTrans
 		return pkg.NewResponse(http.StatusBadRequest, "Invalid date format. Expected YYYY-MM-DD", nil, nil)
 	}
 
+	if req.CategoryID == uuid.Nil && len(req.Splits) > 0 {
+		req.CategoryID = req.Splits[0].CategoryID
+	}
+
+	if req.CategoryID == uuid.Nil {
+		return pkg.NewResponse(http.StatusBadRequest, "Invalid category ID", nil, nil)
+	}
+
 	txDB := &domain.TransactionDB{
 		ID:              req.ID,
 		UserID:          userID,
@@ -253,45 +481,69 @@ func (u *transactionUsecase) Update(ctx context.Context, req *domain.CreateTrans
 			return err
 		}
 
-		oldCategory, err := u.repo.GetCategoryByID(txCtx, oldTx.CategoryID, userID)
+		newCategory, err := u.repo.GetCategoryByID(txCtx, req.CategoryID, oldTx.ID, userID)
 		if err != nil {
+			if err.Error() == constant.ErrNotFound {
+				return &BusinessError{Message: constant.ErrInvalidCategory}
+			}
 			return err
 		}
 
-		newCategory, err := u.repo.GetCategoryByID(txCtx, req.CategoryID, userID)
-		if err != nil {
-			return err
+		// existing splits are kept when the request leaves them out, so they
+		// still have to fit the updated amount and category
+		var splits []domain.TransactionSplit
+		if req.Splits != nil {
+			splits = toTransactionSplits(req.Splits)
+		} else if oldTx.IsSplit {
+			existing, err := u.repo.ListSplits(txCtx, oldTx.ID)
+			if err != nil {
+				return err
+			}
+			splits = *existing
 		}
 
-		err = u.revertCashflowEffect(txCtx, oldCategory.BaseType, oldTx.Amount, oldTx.AssetID, oldTx.LiabilityID, userID)
-		if err != nil {
-			return err
+		if len(splits) > 0 {
+			err = u.checkSplits(txCtx, splits, txDB.Amount, newCategory.BaseType, oldTx.ID, userID)
+			if err != nil {
+				return err
+			}
 		}
 
-		err = u.repo.Update(txCtx, txDB)
+		err = u.replaceTransaction(txCtx, oldTx, txDB, newCategory.BaseType, userID)
 		if err != nil {
 			return err
 		}
 
-		err = u.applyCashflowEffect(txCtx, newCategory.BaseType, txDB.Amount, txDB.AssetID, txDB.LiabilityID, userID)
-		if err != nil {
-			return err
+		if req.Splits != nil {
+			err = u.repo.ReplaceSplits(txCtx, txDB.ID, splits)
+			if err != nil {
+				return err
+			}
 		}
 
-		var assetIDs []uuid.UUID
-		var liabilityIDs []uuid.UUID
-		assetIDs = appendUniqueUUID(assetIDs, oldTx.AssetID)
-		assetIDs = appendUniqueUUID(assetIDs, txDB.AssetID)
-		liabilityIDs = appendUniqueUUID(liabilityIDs, oldTx.LiabilityID)
-		liabilityIDs = appendUniqueUUID(liabilityIDs, txDB.LiabilityID)
+		if req.Tags != nil {
+			err = u.setTags(txCtx, txDB, req.Tags)
+			if err != nil {
+				return err
+			}
+		}
 
-		return u.validateBalances(txCtx, assetIDs, liabilityIDs, userID)
+		return nil
 	})
 
 	if err != nil {
 		if busErr, ok := err.(*BusinessError); ok {
 			return pkg.NewResponse(http.StatusBadRequest, busErr.Message, nil, nil)
 		}
+		if err.Error() == constant.ErrInvalidCategory {
+			return pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidCategory, nil, nil)
+		}
+		if err.Error() == constant.ErrNotAuthorized {
+			return pkg.NewResponse(http.StatusForbidden, constant.ErrNotAuthorized, nil, nil)
+		}
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
 		u.log.Printf("[ERROR] Update transaction: %s", err.Error())
 		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
 	}
@@ -299,9 +551,193 @@ func (u *transactionUsecase) Update(ctx context.Context, req *domain.CreateTrans
 	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
 }
 
+// replaceTransaction overwrites oldTx with txDB, moving its cashflow effect from
+// the old category and asset/liability to the new ones.
+func (u *transactionUsecase) replaceTransaction(ctx context.Context, oldTx *domain.Transaction, txDB *domain.TransactionDB, newBaseType string, userID uuid.UUID) error {
+	oldCategory, err := u.repo.GetCategoryByID(ctx, oldTx.CategoryID, oldTx.ID, oldTx.UserID)
+	if err != nil {
+		return err
+	}
+
+	err = u.revertCashflowEffect(ctx, oldCategory.BaseType, oldTx.Amount, oldTx.AssetID, oldTx.LiabilityID, userID)
+	if err != nil {
+		return err
+	}
+
+	err = u.repo.Update(ctx, txDB)
+	if err != nil {
+		return err
+	}
+
+	err = u.applyCashflowEffect(ctx, newBaseType, txDB.Amount, txDB.AssetID, txDB.LiabilityID, userID)
+	if err != nil {
+		return err
+	}
+
+	var assetIDs []uuid.UUID
+	var liabilityIDs []uuid.UUID
+	assetIDs = appendUniqueUUID(assetIDs, oldTx.AssetID)
+	assetIDs = appendUniqueUUID(assetIDs, txDB.AssetID)
+	liabilityIDs = appendUniqueUUID(liabilityIDs, oldTx.LiabilityID)
+	liabilityIDs = appendUniqueUUID(liabilityIDs, txDB.LiabilityID)
+
+	return u.validateBalances(ctx, assetIDs, liabilityIDs, userID)
+}
+
+// ruleChanges lists the transactions of the current workspace the rule would change.
+func (u *transactionUsecase) ruleChanges(ctx context.Context, rule *domain.CategorizationRule, userID uuid.UUID) ([]domain.RulePreview, []domain.Transaction, error) {
+	transactions, err := u.repo.ListForRule(ctx, rule, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	previews := make([]domain.RulePreview, 0)
+	changed := make([]domain.Transaction, 0)
+	matcher := newRuleMatcher(rule)
+	for _, tx := range *transactions {
+		if !matcher.appliesTo(tx.CategoryType) || !matcher.matches(tx.Notes, tx.Amount, tx.AssetID) {
+			continue
+		}
+
+		// split transactions keep their categories, only the asset and tags apply
+		newCategoryID := tx.CategoryID
+		if rule.SetCategoryID != nil && !tx.IsSplit {
+			newCategoryID = *rule.SetCategoryID
+		}
+
+		newAssetID := tx.AssetID
+		if rule.SetAssetID != nil {
+			newAssetID = rule.SetAssetID
+		}
+
+		newTags := domain.NormalizeTagNames(append(slices.Clone(tx.Tags), rule.SetTags...))
+
+		sameAsset := (newAssetID == nil && tx.AssetID == nil) ||
+			(newAssetID != nil && tx.AssetID != nil && *newAssetID == *tx.AssetID)
+		if newCategoryID == tx.CategoryID && sameAsset && len(newTags) == len(tx.Tags) {
+			continue
+		}
+
+		previews = append(previews, domain.RulePreview{
+			TransactionID:   tx.ID,
+			TransactionDate: tx.TransactionDate,
+			Notes:           tx.Notes,
+			Amount:          tx.Amount,
+			CategoryID:      tx.CategoryID,
+			NewCategoryID:   newCategoryID,
+			AssetID:         tx.AssetID,
+			NewAssetID:      newAssetID,
+			Tags:            tx.Tags,
+			NewTags:         newTags,
+		})
+		changed = append(changed, tx)
+	}
+
+	return previews, changed, nil
+}
+
+func (u *transactionUsecase) PreviewRule(ctx context.Context, ruleID uuid.UUID) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	rule, err := u.ruleRepo.GetByID(ctx, ruleID, userID, false)
+	if err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] ruleRepo.GetByID: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	previews, _, err := u.ruleChanges(ctx, rule, userID)
+	if err != nil {
+		u.log.Printf("[ERROR] ruleChanges: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", previews, nil)
+}
+
+// ApplyRule applies a rule to every existing transaction it matches, all or nothing.
+func (u *transactionUsecase) ApplyRule(ctx context.Context, ruleID uuid.UUID) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	rule, err := u.ruleRepo.GetByID(ctx, ruleID, userID, true)
+	if err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] ruleRepo.GetByID: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	var updated int
+	err = u.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		previews, changed, err := u.ruleChanges(txCtx, rule, userID)
+		if err != nil {
+			return err
+		}
+
+		for i, oldTx := range changed {
+			preview := previews[i]
+
+			baseType := oldTx.CategoryType
+			if preview.NewCategoryID != oldTx.CategoryID {
+				category, err := u.repo.GetCategoryByID(txCtx, preview.NewCategoryID, oldTx.ID, rule.UserID)
+				if err != nil {
+					return err
+				}
+				baseType = category.BaseType
+			}
+
+			txDB := &domain.TransactionDB{
+				ID:              oldTx.ID,
+				UserID:          userID,
+				AssetID:         preview.NewAssetID,
+				LiabilityID:     oldTx.LiabilityID,
+				CategoryID:      preview.NewCategoryID,
+				Amount:          oldTx.Amount,
+				TransactionDate: oldTx.TransactionDate,
+				Notes:           oldTx.Notes,
+			}
+
+			err = u.replaceTransaction(txCtx, &oldTx, txDB, baseType, userID)
+			if err != nil {
+				return err
+			}
+
+			err = u.setTags(txCtx, txDB, preview.NewTags)
+			if err != nil {
+				return err
+			}
+		}
+
+		updated = len(changed)
+		return nil
+	})
+
+	if err != nil {
+		if busErr, ok := err.(*BusinessError); ok {
+			return pkg.NewResponse(http.StatusBadRequest, busErr.Message, nil, nil)
+		}
+		if err.Error() == constant.ErrInvalidCategory {
+			return pkg.NewResponse(http.StatusBadRequest, constant.ErrInvalidCategory, nil, nil)
+		}
+		if err.Error() == constant.ErrNotAuthorized {
+			return pkg.NewResponse(http.StatusForbidden, constant.ErrNotAuthorized, nil, nil)
+		}
+		u.log.Printf("[ERROR] ApplyRule: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", map[string]any{"updated": updated}, nil)
+}
+
 func (u *transactionUsecase) Delete(ctx context.Context, id uuid.UUID) (resp pkg.Response) {
 	userID := ctx.Value("user_id").(uuid.UUID)
 
+	var attachments *[]domain.Attachment
 	err := u.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
 		oldTx, err := u.repo.GetByID(txCtx, id, userID)
 		if err != nil {
@@ -311,7 +747,13 @@ func (u *transactionUsecase) Delete(ctx context.Context, id uuid.UUID) (resp pkg
 			return err
 		}
 
-		oldCategory, err := u.repo.GetCategoryByID(txCtx, oldTx.CategoryID, userID)
+		// the rows go with the transaction, the files are removed once it is committed
+		attachments, err = u.attachmentRepo.ListByTransaction(txCtx, id, userID)
+		if err != nil {
+			return err
+		}
+
+		oldCategory, err := u.repo.GetCategoryByID(txCtx, oldTx.CategoryID, oldTx.ID, oldTx.UserID)
 		if err != nil {
 			return err
 		}
@@ -338,13 +780,421 @@ func (u *transactionUsecase) Delete(ctx context.Context, id uuid.UUID) (resp pkg
 		if busErr, ok := err.(*BusinessError); ok {
 			return pkg.NewResponse(http.StatusBadRequest, busErr.Message, nil, nil)
 		}
+		if err.Error() == constant.ErrNotAuthorized {
+			return pkg.NewResponse(http.StatusForbidden, constant.ErrNotAuthorized, nil, nil)
+		}
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
 		u.log.Printf("[ERROR] Delete transaction: %s", err.Error())
 		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
 	}
 
+	if attachments != nil {
+		for _, attachment := range *attachments {
+			if err := u.blobs.Delete(ctx, attachment.StorageKey); err != nil {
+				u.log.Printf("[ERROR] blobs.Delete %s: %s", attachment.StorageKey, err.Error())
+			}
+		}
+	}
+
 	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
 }
 
+// Bulk runs a batch of operations in one database transaction. Each operation
+// runs under its own savepoint, so a failing one is reported and skipped, or,
+// with all_or_nothing, rolls the whole batch back. Balance effects are added
+// up and written once per asset and liability. With all_or_nothing the
+// balances are checked once on the net result; otherwise each operation is
+// checked as it runs, so only the ones that would overdraw an asset or
+// overpay a liability fail.
+func (u *transactionUsecase) Bulk(ctx context.Context, req *domain.BulkTransactionRequest) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	rules, err := u.ruleRepo.ListActive(ctx, userID)
+	if err != nil {
+		u.log.Printf("[ERROR] ruleRepo.ListActive: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	// bulk creates are how imports come in, they get the same rules as Create
+	matchers := compileRules(*rules)
+
+	result := domain.BulkTransactionResponse{Results: make([]domain.BulkTransactionResult, 0, len(req.Operations))}
+	var attachments []domain.Attachment
+	err = u.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		effects := newBalanceEffects()
+		guard := newBalanceGuard()
+
+		for i := range req.Operations {
+			op := &req.Operations[i]
+			opEffects := newBalanceEffects()
+
+			var id uuid.UUID
+			var opAttachments *[]domain.Attachment
+			err := u.txManager.WithTransaction(txCtx, func(opCtx context.Context) error {
+				var err error
+				id, opAttachments, err = u.bulkOperation(opCtx, op, matchers, opEffects, userID)
+				if err != nil || req.AllOrNothing {
+					return err
+				}
+				return u.checkBulkBalances(opCtx, guard, effects, opEffects, userID)
+			})
+
+			item := domain.BulkTransactionResult{Index: i, Op: op.Op}
+			if id != uuid.Nil {
+				item.ID = &id
+			}
+
+			if err != nil {
+				message, ok := bulkErrorMessage(err)
+				if !ok {
+					return err
+				}
+				item.Error = message
+				result.Failed++
+			} else {
+				item.Success = true
+				result.Succeeded++
+				effects.merge(opEffects)
+				if opAttachments != nil {
+					attachments = append(attachments, *opAttachments...)
+				}
+			}
+
+			result.Results = append(result.Results, item)
+		}
+
+		if req.AllOrNothing && result.Failed > 0 {
+			return errBulkRolledBack
+		}
+
+		return u.commitEffects(txCtx, effects, userID)
+	})
+
+	if err != nil {
+		if err == errBulkRolledBack {
+			message := fmt.Sprintf("%d of %d operations failed, no changes were saved", result.Failed, len(req.Operations))
+			return pkg.NewResponse(http.StatusBadRequest, message, result, nil)
+		}
+		if busErr, ok := err.(*BusinessError); ok {
+			return pkg.NewResponse(http.StatusBadRequest, busErr.Message, nil, nil)
+		}
+		u.log.Printf("[ERROR] Bulk transactions: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	for _, attachment := range attachments {
+		if err := u.blobs.Delete(ctx, attachment.StorageKey); err != nil {
+			u.log.Printf("[ERROR] blobs.Delete %s: %s", attachment.StorageKey, err.Error())
+		}
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", result, nil)
+}
+
+var errBulkRolledBack = errors.New("bulk operations rolled back")
+
+// balanceGuard remembers the balances a batch started from. Balances are only
+// written once the whole batch ran, so the stored ones are still the starting
+// point while operations are being checked.
+type balanceGuard struct {
+	assets      map[uuid.UUID]*domain.Asset
+	liabilities map[uuid.UUID]*domain.Liability
+}
+
+func newBalanceGuard() *balanceGuard {
+	return &balanceGuard{
+		assets:      make(map[uuid.UUID]*domain.Asset),
+		liabilities: make(map[uuid.UUID]*domain.Liability),
+	}
+}
+
+// checkBulkBalances fails an operation whose effect, on top of the operations
+// that already succeeded, would overdraw an asset or overpay a liability.
+func (u *transactionUsecase) checkBulkBalances(ctx context.Context, guard *balanceGuard, effects, opEffects *balanceEffects, userID uuid.UUID) error {
+	for id, delta := range opEffects.assets {
+		if delta.IsZero() {
+			continue
+		}
+
+		asset, ok := guard.assets[id]
+		if !ok {
+			var err error
+			if asset, err = u.assetRepo.GetByID(ctx, id, userID); err != nil {
+				return err
+			}
+			guard.assets[id] = asset
+		}
+
+		if asset.CurrentValue.Add(effects.assets[id]).Add(delta).LessThan(decimal.Zero) {
+			return &BusinessError{Message: fmt.Sprintf("Insufficient balance in asset '%s' to complete this transaction", asset.Name)}
+		}
+	}
+
+	for id, delta := range opEffects.liabilities {
+		if delta.IsZero() {
+			continue
+		}
+
+		liab, ok := guard.liabilities[id]
+		if !ok {
+			var err error
+			if liab, err = u.liabRepo.GetByID(ctx, id, userID); err != nil {
+				return err
+			}
+			guard.liabilities[id] = liab
+		}
+
+		if liab.RemainingBalance.Add(effects.liabilities[id]).Add(delta).LessThan(decimal.Zero) {
+			return &BusinessError{Message: fmt.Sprintf("Payment exceeds the remaining liability for '%s'", liab.Name)}
+		}
+	}
+
+	return nil
+}
+
+// bulkErrorMessage returns what to report for an operation that failed on its
+// own input. Any other error aborts the batch.
+func bulkErrorMessage(err error) (string, bool) {
+	if busErr, ok := err.(*BusinessError); ok {
+		return busErr.Message, true
+	}
+
+	switch err.Error() {
+	case constant.ErrNotFound, constant.ErrNotAuthorized, constant.ErrInvalidCategory:
+		return err.Error(), true
+	}
+
+	return "", false
+}
+
+// bulkOperation runs a single operation of a batch, recording its balance
+// effect in effects instead of writing it. It returns the transaction the
+// operation touched and, for a delete, the attachments whose files are to be
+// removed once the batch is committed.
+func (u *transactionUsecase) bulkOperation(
+	ctx context.Context,
+	op *domain.BulkTransactionOperation,
+	rules []ruleMatcher,
+	effects *balanceEffects,
+	userID uuid.UUID,
+) (uuid.UUID, *[]domain.Attachment, error) {
+	if op.Op == domain.BulkOpCreate {
+		if op.Create == nil {
+			return uuid.Nil, nil, &BusinessError{Message: "create is required for a create operation"}
+		}
+
+		op.Create.UserID = userID
+		txDB, category, splits, err := u.newTransaction(ctx, op.Create, rules, userID)
+		if err != nil {
+			return uuid.Nil, nil, err
+		}
+
+		err = u.insertTransaction(ctx, txDB, op.Create.Tags, splits)
+		if err != nil {
+			return uuid.Nil, nil, err
+		}
+
+		effects.apply(category.BaseType, txDB.Amount, txDB.AssetID, txDB.LiabilityID)
+		return txDB.ID, nil, nil
+	}
+
+	if op.ID == uuid.Nil {
+		return uuid.Nil, nil, &BusinessError{Message: fmt.Sprintf("id is required for a %s operation", op.Op)}
+	}
+
+	oldTx, err := u.repo.GetByID(ctx, op.ID, userID)
+	if err != nil {
+		return op.ID, nil, err
+	}
+
+	txDB := &domain.TransactionDB{
+		ID:              oldTx.ID,
+		UserID:          userID,
+		AssetID:         oldTx.AssetID,
+		LiabilityID:     oldTx.LiabilityID,
+		CategoryID:      oldTx.CategoryID,
+		Amount:          oldTx.Amount,
+		TransactionDate: oldTx.TransactionDate,
+		Notes:           oldTx.Notes,
+	}
+	baseType := oldTx.CategoryType
+
+	switch op.Op {
+	case domain.BulkOpDelete:
+		attachments, err := u.attachmentRepo.ListByTransaction(ctx, op.ID, userID)
+		if err != nil {
+			return op.ID, nil, err
+		}
+
+		err = u.repo.Delete(ctx, op.ID, userID)
+		if err != nil {
+			return op.ID, nil, err
+		}
+
+		effects.revert(oldTx.CategoryType, oldTx.Amount, oldTx.AssetID, oldTx.LiabilityID)
+		return op.ID, attachments, nil
+
+	case domain.BulkOpRetag:
+		if op.Tags == nil {
+			return op.ID, nil, &BusinessError{Message: "tags is required for a retag operation"}
+		}
+
+		err = u.repo.Update(ctx, txDB)
+		if err != nil {
+			return op.ID, nil, err
+		}
+
+		return op.ID, nil, u.setTags(ctx, txDB, op.Tags)
+
+	case domain.BulkOpRecategorize:
+		if op.CategoryID == nil {
+			return op.ID, nil, &BusinessError{Message: "category_id is required for a recategorize operation"}
+		}
+		if oldTx.IsSplit {
+			return op.ID, nil, &BusinessError{Message: "A split transaction is recategorized by editing its splits"}
+		}
+
+		category, err := u.repo.GetCategoryByID(ctx, *op.CategoryID, op.ID, userID)
+		if err != nil {
+			if err.Error() == constant.ErrNotFound {
+				return op.ID, nil, &BusinessError{Message: "Invalid category ID"}
+			}
+			return op.ID, nil, err
+		}
+		txDB.CategoryID = *op.CategoryID
+		baseType = category.BaseType
+
+	case domain.BulkOpMove:
+		if op.AssetID == nil {
+			return op.ID, nil, &BusinessError{Message: "asset_id is required for a move operation"}
+		}
+
+		if _, err := u.assetRepo.GetByID(ctx, *op.AssetID, userID); err != nil {
+			if err.Error() == constant.ErrNotFound {
+				return op.ID, nil, &BusinessError{Message: "Invalid asset ID"}
+			}
+			return op.ID, nil, err
+		}
+		txDB.AssetID = op.AssetID
+	}
+
+	err = u.repo.Update(ctx, txDB)
+	if err != nil {
+		return op.ID, nil, err
+	}
+
+	effects.revert(oldTx.CategoryType, oldTx.Amount, oldTx.AssetID, oldTx.LiabilityID)
+	effects.apply(baseType, txDB.Amount, txDB.AssetID, txDB.LiabilityID)
+	return op.ID, nil, nil
+}
+
+// balanceEffects adds up what a batch does to each asset and liability, so
+// every one of them is written once however many transactions touch it.
+type balanceEffects struct {
+	assets      map[uuid.UUID]decimal.Decimal
+	liabilities map[uuid.UUID]decimal.Decimal
+}
+
+func newBalanceEffects() *balanceEffects {
+	return &balanceEffects{
+		assets:      make(map[uuid.UUID]decimal.Decimal),
+		liabilities: make(map[uuid.UUID]decimal.Decimal),
+	}
+}
+
+// apply records a transaction the way applyCashflowEffect books it.
+func (e *balanceEffects) apply(baseType string, amount decimal.Decimal, assetID, liabilityID *uuid.UUID) {
+	assetDelta := amount
+	if baseType == "income" {
+		assetDelta = amount.Neg()
+	}
+
+	if assetID != nil && *assetID != uuid.Nil {
+		e.assets[*assetID] = e.assets[*assetID].Add(assetDelta)
+	}
+	if liabilityID != nil && *liabilityID != uuid.Nil {
+		e.liabilities[*liabilityID] = e.liabilities[*liabilityID].Sub(assetDelta)
+	}
+}
+
+// revert records the removal of a transaction, as revertCashflowEffect does.
+func (e *balanceEffects) revert(baseType string, amount decimal.Decimal, assetID, liabilityID *uuid.UUID) {
+	e.apply(baseType, amount.Neg(), assetID, liabilityID)
+}
+
+func (e *balanceEffects) merge(other *balanceEffects) {
+	for id, delta := range other.assets {
+		e.assets[id] = e.assets[id].Add(delta)
+	}
+	for id, delta := range other.liabilities {
+		e.liabilities[id] = e.liabilities[id].Add(delta)
+	}
+}
+
+// commitEffects writes the net change of every touched asset and liability,
+// in id order so concurrent batches lock them in the same order, and then
+// validates the resulting balances once.
+func (u *transactionUsecase) commitEffects(ctx context.Context, effects *balanceEffects, userID uuid.UUID) error {
+	assetIDs := slices.SortedFunc(maps.Keys(effects.assets), compareUUID)
+	liabilityIDs := slices.SortedFunc(maps.Keys(effects.liabilities), compareUUID)
+
+	for _, id := range assetIDs {
+		delta := effects.assets[id]
+		if delta.IsZero() {
+			continue
+		}
+
+		asset, err := u.assetRepo.GetByID(ctx, id, userID)
+		if err != nil {
+			return err
+		}
+		err = u.assetRepo.Update(ctx, &domain.AssetDB{
+			ID:           asset.ID,
+			UserId:       userID,
+			CategoryID:   asset.CategoryID,
+			Name:         asset.Name,
+			CurrentValue: asset.CurrentValue.Add(delta),
+			Details:      asset.Details,
+			IsActive:     asset.IsActive,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, id := range liabilityIDs {
+		delta := effects.liabilities[id]
+		if delta.IsZero() {
+			continue
+		}
+
+		liab, err := u.liabRepo.GetByID(ctx, id, userID)
+		if err != nil {
+			return err
+		}
+		err = u.liabRepo.Update(ctx, &domain.LiabilityDB{
+			ID:               liab.ID,
+			UserId:           userID,
+			CategoryID:       liab.CategoryID,
+			Name:             liab.Name,
+			PrincipalAmount:  liab.PrincipalAmount,
+			RemainingBalance: liab.RemainingBalance.Add(delta),
+			Details:          liab.Details,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return u.validateBalances(ctx, assetIDs, liabilityIDs, userID)
+}
+
+func compareUUID(a, b uuid.UUID) int {
+	return bytes.Compare(a[:], b[:])
+}
+
 func (u *transactionUsecase) applyCashflowEffect(
 	ctx context.Context,
 	baseType string,
@@ -360,7 +1210,7 @@ func (u *transactionUsecase) applyCashflowEffect(
 		}
 		assetDB := &domain.AssetDB{
 			ID:           asset.ID,
-			UserId:       asset.UserId,
+			UserId:       userID,
 			CategoryID:   asset.CategoryID,
 			Name:         asset.Name,
 			CurrentValue: asset.CurrentValue,
@@ -385,7 +1235,7 @@ func (u *transactionUsecase) applyCashflowEffect(
 		}
 		liabDB := &domain.LiabilityDB{
 			ID:               liab.ID,
-			UserId:           liab.UserId,
+			UserId:           userID,
 			CategoryID:       liab.CategoryID,
 			Name:             liab.Name,
 			PrincipalAmount:  liab.PrincipalAmount,
@@ -421,7 +1271,7 @@ func (u *transactionUsecase) revertCashflowEffect(
 		}
 		assetDB := &domain.AssetDB{
 			ID:           asset.ID,
-			UserId:       asset.UserId,
+			UserId:       userID,
 			CategoryID:   asset.CategoryID,
 			Name:         asset.Name,
 			CurrentValue: asset.CurrentValue,
@@ -446,7 +1296,7 @@ func (u *transactionUsecase) revertCashflowEffect(
 		}
 		liabDB := &domain.LiabilityDB{
 			ID:               liab.ID,
-			UserId:           liab.UserId,
+			UserId:           userID,
 			CategoryID:       liab.CategoryID,
 			Name:             liab.Name,
 			PrincipalAmount:  liab.PrincipalAmount,
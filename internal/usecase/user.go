@@ -3,21 +3,24 @@ package usecase
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/fazriegi/netbase-be/internal/domain"
 	"github.com/fazriegi/netbase-be/pkg"
 	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/fazriegi/netbase-be/pkg/env"
 	"github.com/fazriegi/netbase-be/pkg/password"
 	"github.com/fazriegi/netbase-be/pkg/token"
 	"github.com/google/uuid"
 )
 
 type userUsecase struct {
-	log  *log.Logger
-	repo domain.UserRepository
-	tx   domain.TransactionManager
+	log      *log.Logger
+	repo     domain.UserRepository
+	tx       domain.TransactionManager
+	throttle domain.LoginThrottleConfig
 }
 
 type UserUsecase interface {
@@ -29,8 +32,19 @@ type UserUsecase interface {
 	CleanupExpiredTokens(ctx context.Context) error
 }
 
-func NewUserUsecase(log *log.Logger, repo domain.UserRepository, tx domain.TransactionManager) UserUsecase {
-	return &userUsecase{log, repo, tx}
+func NewUserUsecase(log *log.Logger, repo domain.UserRepository, tx domain.TransactionManager, throttle domain.LoginThrottleConfig) UserUsecase {
+	return &userUsecase{log, repo, tx, throttle}
+}
+
+// LoginThrottleConfigFromEnv reads the login brute-force settings, falling back to sane defaults.
+func LoginThrottleConfigFromEnv() domain.LoginThrottleConfig {
+	return domain.LoginThrottleConfig{
+		MaxUsernameAttempts: env.GetInt("LOGIN_MAX_ATTEMPTS", 5),
+		MaxIPAttempts:       env.GetInt("LOGIN_MAX_IP_ATTEMPTS", 20),
+		Window:              env.GetDuration("LOGIN_ATTEMPT_WINDOW", 15*time.Minute),
+		BaseLockout:         env.GetDuration("LOGIN_LOCKOUT_BASE", 1*time.Minute),
+		MaxLockout:          env.GetDuration("LOGIN_LOCKOUT_MAX", 1*time.Hour),
+	}
 }
 
 func (uc *userUsecase) Register(ctx context.Context, req *domain.RegisterRequest) pkg.Response {
@@ -65,6 +79,10 @@ func (uc *userUsecase) Register(ctx context.Context, req *domain.RegisterRequest
 			return err
 		}
 
+		if err := uc.repo.CreatePersonalWorkspace(txCtx, userId); err != nil {
+			return err
+		}
+
 		if err := uc.repo.SeedDefaultCategories(txCtx, userId); err != nil {
 			return err
 		}
@@ -80,53 +98,80 @@ func (uc *userUsecase) Register(ctx context.Context, req *domain.RegisterRequest
 }
 
 func (uc *userUsecase) Login(ctx context.Context, req *domain.LoginRequest) (resp pkg.Response) {
+	ip := remoteIP(req.RemoteAddr)
+
+	locked, err := uc.isLoginLocked(ctx, req.Username, ip)
+	if err != nil {
+		uc.log.Printf("[ERROR] isLoginLocked: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	if locked {
+		uc.audit(ctx, nil, req.Username, ip, "login_locked")
+		return pkg.NewResponse(http.StatusTooManyRequests, constant.ErrLoginLocked, nil, nil)
+	}
+
 	user, err := uc.repo.GetByUsername(ctx, req.Username)
 	if err != nil {
 		uc.log.Printf("[ERROR] repo.GetByUsername: %s", err.Error())
+		uc.recordLoginFailure(ctx, nil, req.Username, ip)
 		return pkg.NewResponse(http.StatusUnauthorized, constant.ErrInvalidCreds, nil, nil)
 	}
 
 	if !password.Check(req.Password, user.Password) {
+		uc.recordLoginFailure(ctx, &user.ID, req.Username, ip)
 		return pkg.NewResponse(http.StatusUnauthorized, constant.ErrInvalidCreds, nil, nil)
 	}
 
-	accessToken, err := token.GenerateAccessToken(user.ID.String())
+	// the ip counter only runs out through its window, or a valid login on any
+	// account would wipe the failures guessed at others from the same address
+	if err := uc.repo.ResetLoginAttempt(ctx, "username", req.Username); err != nil {
+		uc.log.Printf("[ERROR] repo.ResetLoginAttempt: %s", err.Error())
+	}
+	uc.audit(ctx, &user.ID, req.Username, ip, "login_success")
+
+	accessToken, refreshToken, err := issueSession(ctx, uc.repo, uc.tx, user.ID, ip)
 	if err != nil {
-		uc.log.Printf("[ERROR] token.GenerateAccessToken: %s", err.Error())
+		uc.log.Printf("[ERROR] issueSession: %s", err.Error())
 		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
 	}
 
-	refreshToken, err := token.GenerateRefreshToken(user.ID.String())
+	return pkg.NewResponse(http.StatusOK, "Login successful", map[string]any{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"user":          user,
+	}, nil)
+}
+
+// issueSession generates a new access and refresh token pair and stores the refresh token.
+func issueSession(ctx context.Context, repo domain.UserRepository, tx domain.TransactionManager, userID uuid.UUID, ip string) (accessToken, refreshToken string, err error) {
+	accessToken, err = token.GenerateAccessToken(userID.String())
 	if err != nil {
-		uc.log.Printf("[ERROR] token.GenerateRefreshToken: %s", err.Error())
-		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+		return "", "", err
 	}
 
-	err = uc.tx.WithTransaction(ctx, func(txCtx context.Context) error {
-		return uc.repo.InsertRefreshToken(txCtx, domain.RefreshToken{
-			UserID:     user.ID,
+	refreshToken, err = token.GenerateRefreshToken(userID.String())
+	if err != nil {
+		return "", "", err
+	}
+
+	err = tx.WithTransaction(ctx, func(txCtx context.Context) error {
+		return repo.InsertRefreshToken(txCtx, domain.RefreshToken{
+			UserID:     userID,
 			Token:      refreshToken,
 			ExpiresAt:  time.Now().Add(7 * 24 * time.Hour),
 			DeviceInfo: "",
-			IPAddress:  req.RemoteAddr,
+			IPAddress:  ip,
 		})
 	})
-	if err != nil {
-		uc.log.Printf("[ERROR] InsertRefreshToken transaction failed: %s", err.Error())
-		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
-	}
 
-	return pkg.NewResponse(http.StatusOK, "Login successful", map[string]any{
-		"access_token":  accessToken,
-		"refresh_token": refreshToken,
-		"user":          user,
-	}, nil)
+	return accessToken, refreshToken, err
 }
 
 func (uc *userUsecase) RefreshToken(ctx context.Context, refreshToken, remoteAddr string) (resp pkg.Response) {
-	claims, err := token.ValidateToken(refreshToken)
+	claims, err := token.ValidateRefreshToken(refreshToken)
 	if err != nil {
-		uc.log.Printf("[ERROR] token.ValidateToken: %s", err.Error())
+		uc.log.Printf("[ERROR] token.ValidateRefreshToken: %s", err.Error())
 		return pkg.NewResponse(http.StatusUnauthorized, constant.ErrInvalidToken, nil, nil)
 	}
 
@@ -205,9 +250,10 @@ func (uc *userUsecase) Profile(ctx context.Context, accessToken string) (resp pk
 }
 
 func (uc *userUsecase) Logout(ctx context.Context, accessToken, refreshToken string) (resp pkg.Response) {
-	claims, err := token.ValidateToken(accessToken)
+	claims, err := token.ValidateAccessToken(accessToken)
 	if err != nil {
-		uc.log.Printf("[ERROR] token.ValidateToken: %s", err.Error())
+		uc.log.Printf("[ERROR] token.ValidateAccessToken: %s", err.Error())
+		return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
 	}
 
 	parsedUserID, err := uuid.Parse(claims.UserID)
@@ -229,3 +275,88 @@ func (uc *userUsecase) Logout(ctx context.Context, accessToken, refreshToken str
 func (uc *userUsecase) CleanupExpiredTokens(ctx context.Context) error {
 	return uc.repo.RemoveExpiredToken(ctx, nil)
 }
+
+func (uc *userUsecase) isLoginLocked(ctx context.Context, username, ip string) (bool, error) {
+	keys := [][2]string{{"username", username}, {"ip", ip}}
+
+	for _, key := range keys {
+		attempt, err := uc.repo.GetLoginAttempt(ctx, key[0], key[1])
+		if err != nil {
+			if err.Error() == constant.ErrNotFound {
+				continue
+			}
+			return false, err
+		}
+
+		if attempt.LockedUntil != nil && attempt.LockedUntil.After(time.Now()) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (uc *userUsecase) recordLoginFailure(ctx context.Context, userID *uuid.UUID, username, ip string) {
+	uc.audit(ctx, userID, username, ip, "login_failed")
+
+	limits := map[string]struct {
+		value string
+		max   int
+	}{
+		"username": {username, uc.throttle.MaxUsernameAttempts},
+		"ip":       {ip, uc.throttle.MaxIPAttempts},
+	}
+
+	for keyType, limit := range limits {
+		count, err := uc.repo.IncrementLoginFailure(ctx, keyType, limit.value, uc.throttle.Window)
+		if err != nil {
+			uc.log.Printf("[ERROR] repo.IncrementLoginFailure: %s", err.Error())
+			continue
+		}
+
+		if limit.max <= 0 || count < limit.max {
+			continue
+		}
+
+		until := time.Now().Add(lockoutDuration(count-limit.max, uc.throttle.BaseLockout, uc.throttle.MaxLockout))
+		if err := uc.repo.LockLogin(ctx, keyType, limit.value, until); err != nil {
+			uc.log.Printf("[ERROR] repo.LockLogin: %s", err.Error())
+			continue
+		}
+
+		uc.audit(ctx, userID, username, ip, "lockout_"+keyType)
+	}
+}
+
+func (uc *userUsecase) audit(ctx context.Context, userID *uuid.UUID, username, ip, event string) {
+	err := uc.repo.InsertAuthAudit(ctx, domain.AuthAuditLog{
+		UserID:    userID,
+		Username:  username,
+		IPAddress: ip,
+		Event:     event,
+	})
+	if err != nil {
+		uc.log.Printf("[ERROR] repo.InsertAuthAudit: %s", err.Error())
+	}
+}
+
+// lockoutDuration doubles the base lockout for every failure past the limit, capped at max.
+func lockoutDuration(overLimit int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 0; i < overLimit && d < max; i++ {
+		d *= 2
+	}
+
+	if max > 0 && d > max {
+		return max
+	}
+	return d
+}
+
+func remoteIP(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
@@ -0,0 +1,307 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+)
+
+type workspaceUsecase struct {
+	log       *log.Logger
+	repo      domain.WorkspaceRepository
+	userRepo  domain.UserRepository
+	txManager domain.TransactionManager
+}
+
+type WorkspaceUsecase interface {
+	List(ctx context.Context) (resp pkg.Response)
+	Create(ctx context.Context, req *domain.CreateWorkspace) (resp pkg.Response)
+	Update(ctx context.Context, req *domain.CreateWorkspace) (resp pkg.Response)
+	Delete(ctx context.Context, id uuid.UUID) (resp pkg.Response)
+	ListMembers(ctx context.Context, id uuid.UUID) (resp pkg.Response)
+	InviteMember(ctx context.Context, req *domain.InviteWorkspaceMember) (resp pkg.Response)
+	UpdateMember(ctx context.Context, req *domain.UpdateWorkspaceMember) (resp pkg.Response)
+	RemoveMember(ctx context.Context, workspaceID, memberID uuid.UUID) (resp pkg.Response)
+	ListInvitations(ctx context.Context) (resp pkg.Response)
+	RespondInvitation(ctx context.Context, id uuid.UUID, accept bool) (resp pkg.Response)
+}
+
+func NewWorkspaceUsecase(
+	log *log.Logger,
+	repo domain.WorkspaceRepository,
+	userRepo domain.UserRepository,
+	txManager domain.TransactionManager,
+) WorkspaceUsecase {
+	return &workspaceUsecase{log, repo, userRepo, txManager}
+}
+
+// getWorkspace loads the workspace as seen by the user and checks the user's role.
+// ok is false when resp holds the error response to return.
+func (u *workspaceUsecase) getWorkspace(ctx context.Context, id, userID uuid.UUID, roles ...string) (workspace *domain.Workspace, resp pkg.Response, ok bool) {
+	workspace, err := u.repo.GetByID(ctx, id, userID)
+	if err != nil {
+		if err.Error() != constant.ErrNotFound {
+			u.log.Printf("[ERROR] repo.GetByID: %s", err.Error())
+			return nil, pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil), false
+		}
+		return nil, pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil), false
+	}
+
+	if len(roles) == 0 {
+		return workspace, resp, true
+	}
+
+	for _, role := range roles {
+		if workspace.Role == role {
+			return workspace, resp, true
+		}
+	}
+
+	return nil, pkg.NewResponse(http.StatusForbidden, constant.ErrNotAuthorized, nil, nil), false
+}
+
+func (u *workspaceUsecase) List(ctx context.Context) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	workspaces, err := u.repo.List(ctx, userID)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.List: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", workspaces, nil)
+}
+
+func (u *workspaceUsecase) Create(ctx context.Context, req *domain.CreateWorkspace) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	var workspaceID uuid.UUID
+	err := u.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		workspaceID, err = u.repo.Insert(txCtx, &domain.Workspace{
+			Name:    req.Name,
+			OwnerID: userID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return u.repo.InsertMember(txCtx, workspaceID, userID, domain.RoleOwner)
+	})
+	if err != nil {
+		u.log.Printf("[ERROR] Create workspace: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusCreated, "Success", map[string]any{"id": workspaceID}, nil)
+}
+
+func (u *workspaceUsecase) Update(ctx context.Context, req *domain.CreateWorkspace) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	workspace, resp, ok := u.getWorkspace(ctx, req.ID, userID, domain.RoleOwner)
+	if !ok {
+		return resp
+	}
+
+	workspace.Name = req.Name
+	if err := u.repo.Update(ctx, workspace); err != nil {
+		u.log.Printf("[ERROR] repo.Update: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
+}
+
+func (u *workspaceUsecase) Delete(ctx context.Context, id uuid.UUID) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	workspace, resp, ok := u.getWorkspace(ctx, id, userID, domain.RoleOwner)
+	if !ok {
+		return resp
+	}
+
+	if workspace.IsPersonal {
+		return pkg.NewResponse(http.StatusBadRequest, "Personal workspace can't be deleted", nil, nil)
+	}
+
+	if err := u.repo.Delete(ctx, id); err != nil {
+		if strings.Contains(err.Error(), "violates foreign key constraint") {
+			return pkg.NewResponse(http.StatusBadRequest, "Workspace still has assets, liabilities or transactions", nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.Delete: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
+}
+
+func (u *workspaceUsecase) ListMembers(ctx context.Context, id uuid.UUID) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	if _, resp, ok := u.getWorkspace(ctx, id, userID); !ok {
+		return resp
+	}
+
+	members, err := u.repo.ListMembers(ctx, id)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.ListMembers: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", members, nil)
+}
+
+func (u *workspaceUsecase) InviteMember(ctx context.Context, req *domain.InviteWorkspaceMember) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	workspace, resp, ok := u.getWorkspace(ctx, req.WorkspaceID, userID, domain.RoleOwner)
+	if !ok {
+		return resp
+	}
+
+	if workspace.IsPersonal {
+		return pkg.NewResponse(http.StatusBadRequest, "Members can't be invited to a personal workspace", nil, nil)
+	}
+
+	invitee, err := u.userRepo.GetByUsername(ctx, req.Username)
+	if err != nil {
+		if err.Error() != constant.ErrUserNotFound {
+			u.log.Printf("[ERROR] userRepo.GetByUsername: %s", err.Error())
+			return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+		}
+		return pkg.NewResponse(http.StatusNotFound, constant.ErrUserNotFound, nil, nil)
+	}
+
+	if _, err := u.repo.GetByID(ctx, req.WorkspaceID, invitee.ID); err == nil {
+		return pkg.NewResponse(http.StatusBadRequest, "User is already a member of this workspace", nil, nil)
+	}
+
+	err = u.repo.InsertInvitation(ctx, &domain.WorkspaceInvitation{
+		WorkspaceID: req.WorkspaceID,
+		InvitedBy:   userID,
+		InviteeID:   invitee.ID,
+		Role:        req.Role,
+		ExpiresAt:   time.Now().Add(7 * 24 * time.Hour),
+	})
+	if err != nil {
+		u.log.Printf("[ERROR] repo.InsertInvitation: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusCreated, "Success", nil, nil)
+}
+
+func (u *workspaceUsecase) UpdateMember(ctx context.Context, req *domain.UpdateWorkspaceMember) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	workspace, resp, ok := u.getWorkspace(ctx, req.WorkspaceID, userID, domain.RoleOwner)
+	if !ok {
+		return resp
+	}
+
+	if req.UserID == workspace.OwnerID {
+		return pkg.NewResponse(http.StatusBadRequest, "The owner's role can't be changed", nil, nil)
+	}
+
+	if err := u.repo.UpdateMemberRole(ctx, req.WorkspaceID, req.UserID, req.Role); err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.UpdateMemberRole: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
+}
+
+func (u *workspaceUsecase) RemoveMember(ctx context.Context, workspaceID, memberID uuid.UUID) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	// members may always leave, only the owner can remove someone else
+	var roles []string
+	if memberID != userID {
+		roles = append(roles, domain.RoleOwner)
+	}
+
+	workspace, resp, ok := u.getWorkspace(ctx, workspaceID, userID, roles...)
+	if !ok {
+		return resp
+	}
+
+	if memberID == workspace.OwnerID {
+		return pkg.NewResponse(http.StatusBadRequest, "The owner can't leave or be removed from the workspace", nil, nil)
+	}
+
+	if err := u.repo.DeleteMember(ctx, workspaceID, memberID); err != nil {
+		if err.Error() == constant.ErrNotFound {
+			return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+		}
+
+		u.log.Printf("[ERROR] repo.DeleteMember: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
+}
+
+func (u *workspaceUsecase) ListInvitations(ctx context.Context) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	invitations, err := u.repo.ListInvitations(ctx, userID)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.ListInvitations: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", invitations, nil)
+}
+
+func (u *workspaceUsecase) RespondInvitation(ctx context.Context, id uuid.UUID, accept bool) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+
+	invitation, err := u.repo.GetInvitation(ctx, id, userID)
+	if err != nil {
+		if err.Error() != constant.ErrNotFound {
+			u.log.Printf("[ERROR] repo.GetInvitation: %s", err.Error())
+			return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+		}
+		return pkg.NewResponse(http.StatusNotFound, constant.ErrNotFound, nil, nil)
+	}
+
+	if invitation.Status != "pending" || invitation.ExpiresAt.Before(time.Now()) {
+		return pkg.NewResponse(http.StatusBadRequest, "Invitation is no longer valid", nil, nil)
+	}
+
+	status := "declined"
+	if accept {
+		status = "accepted"
+	}
+
+	err = u.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := u.repo.UpdateInvitationStatus(txCtx, id, status); err != nil {
+			return err
+		}
+
+		if !accept {
+			return nil
+		}
+
+		return u.repo.InsertMember(txCtx, invitation.WorkspaceID, userID, invitation.Role)
+	})
+	if err != nil {
+		u.log.Printf("[ERROR] RespondInvitation transaction failed: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", nil, nil)
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/fazriegi/netbase-be/internal/domain"
+	"github.com/fazriegi/netbase-be/pkg"
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Zakat maal is due at 2.5% once wealth worth at least 85 grams of gold has
+// been held for a lunar year.
+var (
+	zakatNisabGrams = decimal.NewFromInt(85)
+	zakatRate       = decimal.NewFromFloat(2.5)
+)
+
+const zakatHaulDays = 354
+
+type zakatUsecase struct {
+	log       *log.Logger
+	repo      domain.ZakatRepository
+	goldPrice domain.GoldPriceProvider
+}
+
+type ZakatUsecase interface {
+	Estimate(ctx context.Context, req *domain.ZakatRequest) (resp pkg.Response)
+}
+
+func NewZakatUsecase(log *log.Logger, repo domain.ZakatRepository, goldPrice domain.GoldPriceProvider) ZakatUsecase {
+	return &zakatUsecase{log, repo, goldPrice}
+}
+
+// Estimate values the zakatable assets net of the debts due against the
+// nisab. The haul is taken as completed when the daily snapshots of that
+// zakatable wealth cover the whole lunar year and none of them fell below the
+// nisab.
+func (u *zakatUsecase) Estimate(ctx context.Context, req *domain.ZakatRequest) (resp pkg.Response) {
+	userID := ctx.Value("user_id").(uuid.UUID)
+	req.UserID = userID
+
+	if req.GoldPrice.IsNegative() {
+		return pkg.NewResponse(http.StatusBadRequest, "gold_price can't be negative", nil, nil)
+	}
+
+	goldPrice := req.GoldPrice
+	if goldPrice.IsZero() {
+		price, err := u.goldPrice.FetchPricePerGram(ctx)
+		if err != nil {
+			u.log.Printf("[ERROR] goldPrice.FetchPricePerGram: %s", err.Error())
+			return pkg.NewResponse(http.StatusBadRequest, "Gold price is unavailable, pass gold_price to compute the nisab", nil, nil)
+		}
+		goldPrice = price
+	}
+
+	categories, err := u.repo.ListCategoryBalances(ctx, userID)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.ListCategoryBalances: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	debtsDue, err := u.repo.GetDebtsDue(ctx, userID)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.GetDebtsDue: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	now := time.Now()
+	haulEnd := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	haulStart := haulEnd.AddDate(0, 0, -zakatHaulDays)
+
+	history, err := u.repo.GetHaulHistory(ctx, userID, haulStart)
+	if err != nil {
+		u.log.Printf("[ERROR] repo.GetHaulHistory: %s", err.Error())
+		return pkg.NewResponse(http.StatusInternalServerError, constant.ErrServer, nil, nil)
+	}
+
+	zakat := domain.Zakat{
+		GoldPricePerGram:      goldPrice,
+		NisabGrams:            zakatNisabGrams,
+		Nisab:                 zakatNisabGrams.Mul(goldPrice).Round(2),
+		DebtsDue:              debtsDue,
+		HaulStart:             haulStart.Format(time.DateOnly),
+		HaulEnd:               haulEnd.Format(time.DateOnly),
+		LowestZakatableWealth: history.LowestZakatableWealth,
+		Rate:                  zakatRate,
+		Categories:            *categories,
+	}
+
+	for _, category := range *categories {
+		if category.IsZakatable {
+			zakat.ZakatableAssets = zakat.ZakatableAssets.Add(category.Value)
+		}
+	}
+	zakat.ZakatableWealth = decimal.Max(zakat.ZakatableAssets.Sub(debtsDue), decimal.Zero)
+	zakat.AboveNisab = zakat.ZakatableWealth.GreaterThanOrEqual(zakat.Nisab)
+
+	if history.TrackedSince != nil {
+		trackedSince := history.TrackedSince.Format(time.DateOnly)
+		zakat.TrackedSince = &trackedSince
+		zakat.HaulCompleted = !history.TrackedSince.After(haulStart) &&
+			history.LowestZakatableWealth != nil && history.LowestZakatableWealth.GreaterThanOrEqual(zakat.Nisab)
+	}
+
+	zakat.Due = zakat.AboveNisab && zakat.HaulCompleted
+	if zakat.Due {
+		zakat.Amount = zakat.ZakatableWealth.Mul(zakatRate).Div(decimal.NewFromInt(100)).Round(2)
+	}
+
+	return pkg.NewResponse(http.StatusOK, "Success", zakat, nil)
+}
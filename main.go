@@ -9,6 +9,7 @@ import (
 	"github.com/fazriegi/netbase-be/internal/delivery/http/handler"
 	"github.com/fazriegi/netbase-be/pkg/database"
 	"github.com/fazriegi/netbase-be/pkg/logger"
+	"github.com/fazriegi/netbase-be/pkg/token"
 	"github.com/joho/godotenv"
 )
 
@@ -33,6 +34,10 @@ func main() {
 
 	appLogger.Println("Starting application...")
 
+	if err := token.LoadKeys(); err != nil {
+		appLogger.Fatalf("could not load JWT signing keys: %v", err)
+	}
+
 	db := database.ConnectPostgres(appLogger)
 	defer db.Close()
 
@@ -10,8 +10,17 @@ var (
 	ErrInvalidJson     = "invalid JSON format"
 	ErrInvalidToken    = "invalid or expired token"
 	ErrInvalidParam    = "invalid param"
+	ErrInvalidCursor   = "invalid cursor"
+	ErrLimitTooLarge   = "limit can't be more than 100"
 
 	ErrUserNotFound   = "User not found"
 	ErrUsernameExists = "Username already exists"
 	ErrInvalidCreds   = "Invalid credentials"
+	ErrLoginLocked    = "Too many failed login attempts, please try again later"
+
+	ErrCategoryExists       = "Category already exists"
+	ErrInvalidCategory      = "Invalid category ID"
+	ErrCategoryBaseMismatch = "Categories must share the same base type"
+	ErrCategoryParentCycle  = "A category can't be placed under itself or its subcategories"
+	ErrTagExists            = "Tag already exists"
 )
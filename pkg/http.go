@@ -1,10 +1,14 @@
 package pkg
 
 import (
+	"encoding"
 	"errors"
+	"fmt"
 	"net/http"
 	"reflect"
 	"strconv"
+	"strings"
+	"time"
 )
 
 func MapQueryTags(v reflect.Value, result map[string]reflect.Value) {
@@ -49,26 +53,85 @@ func ParseQueryParam(r *http.Request, dest interface{}) error {
 	queryParams := r.URL.Query()
 
 	for tag, field := range fieldMap {
-		valStr := queryParams.Get(tag)
-		if valStr == "" {
+		values := queryParams[tag]
+		if len(values) == 0 || (len(values) == 1 && values[0] == "") {
 			continue
 		}
 
-		// --- HANDLE POINTER FIELD ---
-		isPtr := field.Kind() == reflect.Ptr
-		var targetField reflect.Value
-		var baseKind reflect.Kind
-
-		if isPtr {
-			// Jika pointer, alokasikan memori baru untuk tipe dasarnya (elem)
-			targetField = reflect.New(field.Type().Elem()).Elem()
-			baseKind = targetField.Kind()
-		} else {
-			// Jika bukan pointer, langsung gunakan field-nya
-			targetField = field
-			baseKind = targetField.Kind()
+		// --- HANDLE SLICE FIELD ---
+		// Param boleh diulang (?id=a&id=b) atau dipisah koma (?id=a,b)
+		if field.Kind() == reflect.Slice {
+			slice := reflect.MakeSlice(field.Type(), 0, len(values))
+			for _, v := range values {
+				for _, part := range strings.Split(v, ",") {
+					part = strings.TrimSpace(part)
+					if part == "" {
+						continue
+					}
+
+					elem := reflect.New(field.Type().Elem()).Elem()
+					if err := setQueryValue(elem, tag, part); err != nil {
+						return err
+					}
+					slice = reflect.Append(slice, elem)
+				}
+			}
+			field.Set(slice)
+			continue
 		}
 
+		if err := setQueryValue(field, tag, values[0]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// parseQueryTime accepts a date (YYYY-MM-DD) or a full RFC 3339 timestamp.
+func parseQueryTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// setQueryValue parses valStr into field. Scalars that fail to parse are left
+// untouched; dates and text types such as uuid.UUID or decimal.Decimal return
+// an error instead, since a silently dropped id or amount would widen a filter.
+func setQueryValue(field reflect.Value, tag, valStr string) error {
+	// --- HANDLE POINTER FIELD ---
+	isPtr := field.Kind() == reflect.Ptr
+	var targetField reflect.Value
+	var baseKind reflect.Kind
+
+	if isPtr {
+		// Jika pointer, alokasikan memori baru untuk tipe dasarnya (elem)
+		targetField = reflect.New(field.Type().Elem()).Elem()
+		baseKind = targetField.Kind()
+	} else {
+		// Jika bukan pointer, langsung gunakan field-nya
+		targetField = field
+		baseKind = targetField.Kind()
+	}
+
+	switch {
+	case targetField.Type() == timeType:
+		t, err := parseQueryTime(valStr)
+		if err != nil {
+			return fmt.Errorf("invalid %s: expected YYYY-MM-DD or RFC 3339 time", tag)
+		}
+		targetField.Set(reflect.ValueOf(t))
+	case reflect.PointerTo(targetField.Type()).Implements(textUnmarshalerType):
+		if err := targetField.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(valStr)); err != nil {
+			return fmt.Errorf("invalid %s: %w", tag, err)
+		}
+	default:
 		// Set nilai ke targetField berdasarkan tipe dasarnya
 		switch baseKind {
 		case reflect.String:
@@ -94,11 +157,11 @@ func ParseQueryParam(r *http.Request, dest interface{}) error {
 				targetField.SetFloat(f)
 			}
 		}
+	}
 
-		// Jika field di struct aslinya adalah pointer, kita set nilainya dengan address dari targetField
-		if isPtr {
-			field.Set(targetField.Addr())
-		}
+	// Jika field di struct aslinya adalah pointer, kita set nilainya dengan address dari targetField
+	if isPtr {
+		field.Set(targetField.Addr())
 	}
 
 	return nil
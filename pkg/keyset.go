@@ -0,0 +1,164 @@
+package pkg
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fazriegi/netbase-be/pkg/constant"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// KeysetColumn is one sort key of a keyset paginated list.
+type KeysetColumn struct {
+	Expr string // SQL expression of the key, e.g. transactions.created_at
+	Type string // SQL type the cursor value is cast back to, e.g. timestamptz
+}
+
+// Keyset is the sort order of a keyset paginated list. The keys must be unique
+// together, so the last one is usually the primary key.
+type Keyset struct {
+	Columns []KeysetColumn
+	Desc    bool
+}
+
+// Cursor points just past a row of a keyset page. Backward cursors page
+// towards the start of the list.
+type Cursor struct {
+	Values   []string `json:"v"`
+	Backward bool     `json:"b,omitempty"`
+}
+
+// Page is what a repository knows about the page it returned.
+type Page struct {
+	Total      int
+	NextCursor *string
+	PrevCursor *string
+}
+
+func EncodeCursor(c Cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses an opaque cursor issued for keyset. Cursors come back
+// from clients, so each value must parse as the type of its key before it
+// reaches the SQL cast.
+func DecodeCursor(raw string, keyset Keyset) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errors.New(constant.ErrInvalidCursor)
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil || len(c.Values) != len(keyset.Columns) {
+		return nil, errors.New(constant.ErrInvalidCursor)
+	}
+
+	for i, col := range keyset.Columns {
+		if !validCursorValue(col.Type, c.Values[i]) {
+			return nil, errors.New(constant.ErrInvalidCursor)
+		}
+	}
+	return &c, nil
+}
+
+// validCursorValue reports whether v casts to the SQL type typ.
+func validCursorValue(typ, v string) bool {
+	var err error
+	switch typ {
+	case "date":
+		_, err = time.Parse(time.DateOnly, v)
+	case "timestamptz":
+		_, err = time.Parse(time.RFC3339Nano, v)
+	case "uuid":
+		_, err = uuid.Parse(v)
+	default:
+		return false
+	}
+	return err == nil
+}
+
+// SelectWithKeyset appends the cursor condition, the keyset order and the
+// limit to query, which must end inside its WHERE clause. It fetches one row
+// more than limit so KeysetPage can tell whether another page follows.
+func SelectWithKeyset(ctx context.Context, db NamedQueryer, query string, args map[string]interface{}, keyset Keyset, cursor *Cursor, limit int) (*sqlx.Rows, error) {
+	exprs := make([]string, len(keyset.Columns))
+	for i, col := range keyset.Columns {
+		exprs[i] = col.Expr
+	}
+
+	// a backward page is read in reverse and flipped back by KeysetPage
+	desc := keyset.Desc
+	if cursor != nil && cursor.Backward {
+		desc = !desc
+	}
+
+	if cursor != nil {
+		params := make([]string, len(keyset.Columns))
+		for i, col := range keyset.Columns {
+			name := fmt.Sprintf("cursor_%d", i)
+			params[i] = fmt.Sprintf("CAST(:%s AS %s)", name, col.Type)
+			args[name] = cursor.Values[i]
+		}
+
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		query += fmt.Sprintf(" AND (%s) %s (%s)", strings.Join(exprs, ", "), op, strings.Join(params, ", "))
+	}
+
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+	for i := range exprs {
+		exprs[i] += " " + direction
+	}
+
+	query += " ORDER BY " + strings.Join(exprs, ", ") + " LIMIT :keyset_limit"
+	args["keyset_limit"] = limit + 1
+
+	return db.NamedQueryContext(ctx, query, args)
+}
+
+// KeysetPage trims the extra row fetched by SelectWithKeyset, restores the
+// order of a backward page and returns the cursors around it. key returns
+// the keyset values of a row, formatted so Postgres can cast them back.
+func KeysetPage[T any](rows []T, limit int, cursor *Cursor, key func(*T) []string) ([]T, Page) {
+	var page Page
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	hasNext, hasPrev := hasMore, cursor != nil
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(rows)
+		hasNext, hasPrev = true, hasMore
+	}
+
+	if len(rows) == 0 {
+		return rows, page
+	}
+
+	if hasNext {
+		next := EncodeCursor(Cursor{Values: key(&rows[len(rows)-1])})
+		page.NextCursor = &next
+	}
+
+	if hasPrev {
+		prev := EncodeCursor(Cursor{Values: key(&rows[0]), Backward: true})
+		page.PrevCursor = &prev
+	}
+
+	return rows, page
+}
@@ -1,7 +1,39 @@
 package pkg
 
+const (
+	PaginationOffset = "offset"
+	PaginationCursor = "cursor"
+
+	defaultCursorLimit = 20
+
+	// MaxPageLimit caps the page size of offset and keyset pages alike. Lists reject
+	// a larger limit, so a page is never cut short without the caller knowing.
+	MaxPageLimit = 100
+)
+
 type PaginationRequest struct {
-	Page  *int    `query:"page"`
-	Limit *int    `query:"limit"`
-	Sort  *string `query:"sort"`
+	Page      *int    `query:"page"`
+	Limit     *int    `query:"limit"`
+	Sort      *string `query:"sort"`       // offset mode only, cursor mode uses the fixed order of the list
+	Mode      string  `query:"pagination"` // "offset" (default), "cursor"
+	Cursor    *string `query:"cursor"`     // next_cursor or prev_cursor of a previous page, implies cursor mode
+	WithTotal bool    `query:"with_total"` // cursor mode only counts all matches when asked to
+}
+
+// UseCursor reports whether the request asks for keyset pagination.
+func (p *PaginationRequest) UseCursor() bool {
+	return p.Mode == PaginationCursor || p.Cursor != nil
+}
+
+// LimitTooLarge reports whether the request asks for a page above MaxPageLimit.
+func (p *PaginationRequest) LimitTooLarge() bool {
+	return p.Limit != nil && *p.Limit > MaxPageLimit
+}
+
+// CursorLimit returns the page size of a keyset page, which always has one.
+func (p *PaginationRequest) CursorLimit() int {
+	if p.Limit == nil || *p.Limit <= 0 {
+		return defaultCursorLimit
+	}
+	return min(*p.Limit, MaxPageLimit)
 }
@@ -2,6 +2,7 @@ package pkg
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
 )
 
@@ -18,11 +19,44 @@ type Status struct {
 	IsSuccess bool   `json:"is_success"`
 }
 
+// PaginationMeta describes an offset page, or a keyset page when the request
+// used cursor pagination. Keyset pages only have a total when it was asked for.
 type PaginationMeta struct {
-	Page       int `json:"page"`
-	Limit      int `json:"limit"`
-	Total      int `json:"total"`
-	TotalPages int `json:"total_pages"`
+	Page       int     `json:"page"`
+	Limit      int     `json:"limit"`
+	Total      int     `json:"total"`
+	TotalPages int     `json:"total_pages"`
+	NextCursor *string `json:"next_cursor,omitempty"`
+	PrevCursor *string `json:"prev_cursor,omitempty"`
+}
+
+// NewPaginationMeta builds the meta of a page returned for req.
+func NewPaginationMeta(req *PaginationRequest, page Page) *PaginationMeta {
+	var meta PaginationMeta
+	if req.UseCursor() {
+		meta.Limit = req.CursorLimit()
+		meta.Total = page.Total
+		meta.NextCursor = page.NextCursor
+		meta.PrevCursor = page.PrevCursor
+		return &meta
+	}
+
+	if req.Limit != nil && *req.Limit > 0 {
+		meta.Limit = min(*req.Limit, MaxPageLimit)
+		meta.Page = 1
+
+		if req.Page != nil && *req.Page > 0 {
+			meta.Page = *req.Page
+		}
+
+		meta.Total = page.Total
+		meta.TotalPages = int(math.Ceil(float64(page.Total) / float64(meta.Limit)))
+		if meta.TotalPages > 0 && meta.Page > meta.TotalPages {
+			meta.Page = meta.TotalPages
+		}
+	}
+
+	return &meta
 }
 
 func buildStatus(code int, msg string) Status {
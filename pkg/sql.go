@@ -72,13 +72,14 @@ func SelectWithPagination(ctx context.Context, db NamedQueryer, query string, ar
 	}
 
 	if page != nil && *page != 0 && limit != nil && *limit != 0 {
-		offset := (*page - 1) * *limit
+		pageLimit := min(*limit, MaxPageLimit)
+		offset := (*page - 1) * pageLimit
 		query += "LIMIT :limit OFFSET :offset"
 
 		if args == nil {
 			args = make(map[string]interface{})
 		}
-		args["limit"] = limit
+		args["limit"] = pageLimit
 		args["offset"] = offset
 	}
 
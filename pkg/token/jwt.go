@@ -1,50 +1,99 @@
 package token
 
 import (
+	"errors"
 	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var SecretKey = []byte(os.Getenv("JWT_SECRET"))
+const (
+	TypeAccess  = "access"
+	TypeRefresh = "refresh"
+
+	audienceAccess  = "netbase-api"
+	audienceRefresh = "netbase-refresh"
+)
 
 type Claims struct {
 	UserID string `json:"user_id"`
+	Type   string `json:"typ"`
 	jwt.RegisteredClaims
 }
 
-func GenerateAccessToken(userID string) (string, error) {
-	claims := &Claims{
-		UserID: userID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(15 * time.Minute)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+func issuer() string {
+	if iss := os.Getenv("JWT_ISSUER"); iss != "" {
+		return iss
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(SecretKey)
+	return "netbase-be"
 }
 
-func GenerateRefreshToken(userID string) (string, error) {
+func generate(userID, tokenType, audience string, ttl time.Duration) (string, error) {
+	k, err := keys.signing()
+	if err != nil {
+		return "", err
+	}
+
 	claims := &Claims{
 		UserID: userID,
+		Type:   tokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(7 * 24 * time.Hour)),
+			Issuer:    issuer(),
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(SecretKey)
+	token := jwt.NewWithClaims(k.method, claims)
+	token.Header["kid"] = k.kid
+	return token.SignedString(k.private)
 }
 
-func ValidateToken(tokenString string) (*Claims, error) {
+func GenerateAccessToken(userID string) (string, error) {
+	return generate(userID, TypeAccess, audienceAccess, 15*time.Minute)
+}
+
+func GenerateRefreshToken(userID string) (string, error) {
+	return generate(userID, TypeRefresh, audienceRefresh, 7*24*time.Hour)
+}
+
+func ValidateAccessToken(tokenString string) (*Claims, error) {
+	return validate(tokenString, TypeAccess, audienceAccess)
+}
+
+func ValidateRefreshToken(tokenString string) (*Claims, error) {
+	return validate(tokenString, TypeRefresh, audienceRefresh)
+}
+
+func validate(tokenString, tokenType, audience string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(t *jwt.Token) (interface{}, error) {
-		return SecretKey, nil
-	})
+		kid, _ := t.Header["kid"].(string)
+		k, ok := keys.lookup(kid)
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+
+		if t.Method.Alg() != k.method.Alg() {
+			return nil, errors.New("unexpected signing method")
+		}
+		return k.public, nil
+	},
+		jwt.WithValidMethods([]string{"RS256", "EdDSA", "HS256"}),
+		jwt.WithAudience(audience),
+		jwt.WithIssuer(issuer()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
+	if claims.Type != tokenType {
+		return nil, errors.New("unexpected token type")
 	}
-	return nil, err
+	return claims, nil
 }
@@ -0,0 +1,194 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type key struct {
+	kid     string
+	method  jwt.SigningMethod
+	private any // nil for verification-only keys
+	public  any
+}
+
+type keySet struct {
+	mu     sync.RWMutex
+	active *key
+	keys   map[string]*key
+}
+
+var keys = &keySet{keys: map[string]*key{}}
+
+// LoadKeys loads the signing keys. When JWT_KEYS_DIR is set every *.pem file in it
+// becomes a verification key named after the file (the kid), and JWT_ACTIVE_KID
+// selects the private key used to sign new tokens. Keeping the previous key in the
+// directory during rotation keeps the tokens it signed valid until they expire.
+// Without JWT_KEYS_DIR tokens are signed with HS256 using JWT_SECRET.
+func LoadKeys() error {
+	dir := os.Getenv("JWT_KEYS_DIR")
+	if dir == "" {
+		secret := os.Getenv("JWT_SECRET")
+		if secret == "" {
+			return errors.New("neither JWT_KEYS_DIR nor JWT_SECRET is set")
+		}
+
+		k := &key{kid: "default", method: jwt.SigningMethodHS256, private: []byte(secret), public: []byte(secret)}
+		keys.set(k, map[string]*key{k.kid: k})
+		return nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+
+	loaded := map[string]*key{}
+	for _, file := range files {
+		kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+
+		k, err := parseKeyFile(kid, file)
+		if err != nil {
+			return fmt.Errorf("loading key %s: %w", kid, err)
+		}
+		loaded[kid] = k
+	}
+
+	activeKid := os.Getenv("JWT_ACTIVE_KID")
+	active, ok := loaded[activeKid]
+	if !ok || active.private == nil {
+		return fmt.Errorf("JWT_ACTIVE_KID %q has no private key in %s", activeKid, dir)
+	}
+
+	keys.set(active, loaded)
+	return nil
+}
+
+func (s *keySet) set(active *key, all map[string]*key) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.active = active
+	s.keys = all
+}
+
+func (s *keySet) signing() (*key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.active == nil {
+		return nil, errors.New("signing keys are not loaded")
+	}
+	return s.active, nil
+}
+
+func (s *keySet) lookup(kid string) (*key, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	k, ok := s.keys[kid]
+	return k, ok
+}
+
+func parseKeyFile(kid, file string) (*key, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed any
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &key{kid: kid, method: jwt.SigningMethodRS256, private: k, public: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		return &key{kid: kid, method: jwt.SigningMethodRS256, public: k}, nil
+	case ed25519.PrivateKey:
+		return &key{kid: kid, method: jwt.SigningMethodEdDSA, private: k, public: k.Public()}, nil
+	case ed25519.PublicKey:
+		return &key{kid: kid, method: jwt.SigningMethodEdDSA, public: k}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// PublicJWKS returns the asymmetric verification keys. HS256 secrets are never published.
+func PublicJWKS() JWKS {
+	keys.mu.RLock()
+	defer keys.mu.RUnlock()
+
+	set := JWKS{Keys: make([]JWK, 0, len(keys.keys))}
+	for _, k := range keys.keys {
+		switch pub := k.public.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "RSA",
+				Kid: k.kid,
+				Use: "sig",
+				Alg: k.method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "OKP",
+				Kid: k.kid,
+				Use: "sig",
+				Alg: k.method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
@@ -0,0 +1,84 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+const PATPrefix = "nbp_"
+
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+)
+
+// PATResources lists the resources a personal access token can be scoped to.
+var PATResources = []string{"assets", "liabilities", "transactions", "net-worth", "profile", "search", "reports", "insights", "goals", "zakat", "tax", "subscriptions"}
+
+// GeneratePAT returns a new plaintext token, its SHA-256 hash and a short prefix for display.
+func GeneratePAT() (plain, hash, prefix string, err error) {
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+
+	plain = PATPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return plain, HashPAT(plain), plain[:len(PATPrefix)+6], nil
+}
+
+func HashPAT(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+func IsPAT(raw string) bool {
+	return strings.HasPrefix(raw, PATPrefix)
+}
+
+// ValidScope reports whether scope has the form "<resource>:<read|write>",
+// where resource is one of PATResources or "*".
+func ValidScope(scope string) bool {
+	resource, access, ok := strings.Cut(scope, ":")
+	if !ok || (access != ScopeRead && access != ScopeWrite) {
+		return false
+	}
+
+	if resource == "*" {
+		return true
+	}
+
+	for _, r := range PATResources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// sessionOnlyResources can't be reached with a personal access token at all,
+// whatever its scopes, so a token can't mint or revoke tokens.
+var sessionOnlyResources = []string{"tokens"}
+
+// ScopeAllows reports whether scopes grant access to resource. Write access implies read.
+func ScopeAllows(scopes []string, resource, access string) bool {
+	for _, r := range sessionOnlyResources {
+		if r == resource {
+			return false
+		}
+	}
+
+	for _, scope := range scopes {
+		r, a, ok := strings.Cut(scope, ":")
+		if !ok || (r != "*" && r != resource) {
+			continue
+		}
+
+		if a == access || a == ScopeWrite {
+			return true
+		}
+	}
+	return false
+}